@@ -6,6 +6,7 @@ import (
 	"github.com/teamhanko/passkey-server/config"
 	"github.com/teamhanko/passkey-server/mapper"
 	"github.com/teamhanko/passkey-server/persistence"
+	"log"
 	"sync"
 )
 
@@ -16,9 +17,13 @@ func StartPublic(cfg *config.Config, wg *sync.WaitGroup, persister persistence.P
 	mainRouter.Logger.Fatal(mainRouter.Start(cfg.Address))
 }
 
-func StartAdmin(cfg *config.Config, wg *sync.WaitGroup, persister persistence.Persister, prometheus echo.MiddlewareFunc) {
+func StartAdmin(cfg *config.Config, wg *sync.WaitGroup, persister persistence.Persister, prometheus echo.MiddlewareFunc, authenticatorMetadata mapper.AuthenticatorMetadata) {
 	defer wg.Done()
 
-	adminRouter := router.NewAdminRouter(cfg, persister, prometheus)
+	adminRouter, err := router.NewAdminRouter(cfg, persister, prometheus, authenticatorMetadata)
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	adminRouter.Logger.Fatal(adminRouter.Start(cfg.AdminAddress))
 }
@@ -0,0 +1,62 @@
+package request
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/teamhanko/passkey-server/persistence/models"
+	"time"
+)
+
+type CreateAaguidPolicyDto struct {
+	Passkey *CreateAaguidListsDto `json:"passkey" validate:"omitempty"`
+	Mfa     *CreateAaguidListsDto `json:"mfa" validate:"omitempty"`
+}
+
+type CreateAaguidListsDto struct {
+	Allow []string `json:"allow" validate:"omitempty,unique,dive,uuid"`
+	Deny  []string `json:"deny" validate:"omitempty,unique,dive,uuid"`
+}
+
+func (dto *CreateAaguidPolicyDto) ToModel(configModel models.Config) models.AaguidPolicies {
+	policies := make(models.AaguidPolicies, 0)
+	if dto == nil {
+		return policies
+	}
+
+	now := time.Now()
+	policies = append(policies, dto.Passkey.toModel(configModel, models.AaguidPolicyFlowPasskey, now)...)
+	policies = append(policies, dto.Mfa.toModel(configModel, models.AaguidPolicyFlowMfa, now)...)
+
+	return policies
+}
+
+func (dto *CreateAaguidListsDto) toModel(configModel models.Config, flow models.AaguidPolicyFlow, now time.Time) models.AaguidPolicies {
+	policies := make(models.AaguidPolicies, 0)
+	if dto == nil {
+		return policies
+	}
+
+	lists := []struct {
+		policyType models.AaguidPolicyType
+		aaguids    []string
+	}{
+		{models.AaguidPolicyTypeAllow, dto.Allow},
+		{models.AaguidPolicyTypeDeny, dto.Deny},
+	}
+
+	for _, list := range lists {
+		for _, aaguid := range list.aaguids {
+			policyId, _ := uuid.NewV4()
+			policies = append(policies, models.AaguidPolicy{
+				ID:        policyId,
+				Aaguid:    uuid.FromStringOrNil(aaguid),
+				Flow:      flow,
+				Type:      list.policyType,
+				ConfigID:  configModel.ID,
+				CreatedAt: now,
+				UpdatedAt: now,
+			})
+		}
+	}
+
+	return policies
+}
@@ -0,0 +1,28 @@
+package request
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/teamhanko/passkey-server/persistence/models"
+)
+
+func TestAaguidPolicyToModelKeepsOrder(t *testing.T) {
+	// given
+	dto := &CreateAaguidPolicyDto{
+		Passkey: &CreateAaguidListsDto{
+			Allow: []string{"cb69481e-8ff7-4039-93ec-0a2729a154a8"},
+			Deny:  []string{"ee882879-721c-4913-9775-3dfcce97072a"},
+		},
+	}
+
+	for i := 0; i < 10; i++ {
+		// when
+		policies := dto.ToModel(models.Config{})
+
+		// then
+		assert.Len(t, policies, 2)
+		assert.Equal(t, models.AaguidPolicyTypeAllow, policies[0].Type)
+		assert.Equal(t, models.AaguidPolicyTypeDeny, policies[1].Type)
+	}
+}
@@ -0,0 +1,48 @@
+package request
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/teamhanko/passkey-server/persistence/models"
+	"time"
+)
+
+type CreateBackupPolicyDto struct {
+	Passkey     *models.BackupRequirement `json:"passkey" validate:"omitempty,oneof=any device_bound synced"`
+	Mfa         *models.BackupRequirement `json:"mfa" validate:"omitempty,oneof=any device_bound synced"`
+	Transaction *models.BackupRequirement `json:"transaction" validate:"omitempty,oneof=any device_bound synced"`
+}
+
+func (dto *CreateBackupPolicyDto) ToModel(configModel models.Config) models.BackupPolicies {
+	policies := make(models.BackupPolicies, 0)
+	if dto == nil {
+		return policies
+	}
+
+	now := time.Now()
+	requirements := []struct {
+		flow        models.BackupPolicyFlow
+		requirement *models.BackupRequirement
+	}{
+		{models.BackupPolicyFlowPasskey, dto.Passkey},
+		{models.BackupPolicyFlowMfa, dto.Mfa},
+		{models.BackupPolicyFlowTransaction, dto.Transaction},
+	}
+
+	for _, requirement := range requirements {
+		if requirement.requirement == nil {
+			continue
+		}
+
+		policyId, _ := uuid.NewV4()
+		policies = append(policies, models.BackupPolicy{
+			ID:          policyId,
+			Flow:        requirement.flow,
+			Requirement: *requirement.requirement,
+			ConfigID:    configModel.ID,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		})
+	}
+
+	return policies
+}
@@ -0,0 +1,31 @@
+package request
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/teamhanko/passkey-server/persistence/models"
+)
+
+func TestBackupPolicyToModelKeepsOrder(t *testing.T) {
+	// given
+	deviceBound := models.BackupRequirementDeviceBound
+	dto := &CreateBackupPolicyDto{Passkey: &deviceBound, Mfa: &deviceBound, Transaction: &deviceBound}
+
+	for i := 0; i < 10; i++ {
+		// when
+		policies := dto.ToModel(models.Config{})
+
+		// then
+		flows := make([]models.BackupPolicyFlow, 0)
+		for _, policy := range policies {
+			flows = append(flows, policy.Flow)
+		}
+
+		assert.Equal(t, []models.BackupPolicyFlow{
+			models.BackupPolicyFlowPasskey,
+			models.BackupPolicyFlowMfa,
+			models.BackupPolicyFlowTransaction,
+		}, flows)
+	}
+}
@@ -0,0 +1,59 @@
+package request
+
+import (
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/gofrs/uuid"
+	"github.com/teamhanko/passkey-server/persistence/models"
+	"time"
+)
+
+type CreateCeremonyConfigDto struct {
+	Timeout          *int                                  `json:"timeout" validate:"omitempty,number,min=1"`
+	UserVerification *protocol.UserVerificationRequirement `json:"user_verification" validate:"omitempty,oneof=required preferred discouraged"`
+	Attachment       *protocol.AuthenticatorAttachment     `json:"attachment" validate:"omitempty,oneof=platform cross-platform"`
+}
+
+type CreateCeremoniesDto struct {
+	Registration     *CreateCeremonyConfigDto `json:"registration"`
+	Login            *CreateCeremonyConfigDto `json:"login"`
+	ConditionalLogin *CreateCeremonyConfigDto `json:"conditional_login"`
+	Transaction      *CreateCeremonyConfigDto `json:"transaction"`
+}
+
+func (dto *CreateCeremoniesDto) ToModel(configModel models.Config) models.WebauthnCeremonyConfigs {
+	ceremonyConfigs := make(models.WebauthnCeremonyConfigs, 0)
+	if dto == nil {
+		return ceremonyConfigs
+	}
+
+	now := time.Now()
+	ceremonies := []struct {
+		ceremony models.WebauthnCeremony
+		dto      *CreateCeremonyConfigDto
+	}{
+		{models.WebauthnCeremonyRegistration, dto.Registration},
+		{models.WebauthnCeremonyLogin, dto.Login},
+		{models.WebauthnCeremonyConditionalLogin, dto.ConditionalLogin},
+		{models.WebauthnCeremonyTransaction, dto.Transaction},
+	}
+
+	for _, ceremony := range ceremonies {
+		if ceremony.dto == nil {
+			continue
+		}
+
+		ceremonyConfigId, _ := uuid.NewV4()
+		ceremonyConfigs = append(ceremonyConfigs, models.WebauthnCeremonyConfig{
+			ID:               ceremonyConfigId,
+			Ceremony:         ceremony.ceremony,
+			Timeout:          ceremony.dto.Timeout,
+			UserVerification: ceremony.dto.UserVerification,
+			Attachment:       ceremony.dto.Attachment,
+			ConfigID:         configModel.ID,
+			CreatedAt:        now,
+			UpdatedAt:        now,
+		})
+	}
+
+	return ceremonyConfigs
+}
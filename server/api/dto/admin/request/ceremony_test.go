@@ -0,0 +1,37 @@
+package request
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/teamhanko/passkey-server/persistence/models"
+)
+
+func TestCeremoniesToModelKeepsOrder(t *testing.T) {
+	// given
+	timeout := 60000
+	dto := &CreateCeremoniesDto{
+		Registration:     &CreateCeremonyConfigDto{Timeout: &timeout},
+		Login:            &CreateCeremonyConfigDto{Timeout: &timeout},
+		ConditionalLogin: &CreateCeremonyConfigDto{Timeout: &timeout},
+		Transaction:      &CreateCeremonyConfigDto{Timeout: &timeout},
+	}
+
+	for i := 0; i < 10; i++ {
+		// when
+		ceremonyConfigs := dto.ToModel(models.Config{})
+
+		// then
+		ceremonies := make([]models.WebauthnCeremony, 0)
+		for _, ceremonyConfig := range ceremonyConfigs {
+			ceremonies = append(ceremonies, ceremonyConfig.Ceremony)
+		}
+
+		assert.Equal(t, []models.WebauthnCeremony{
+			models.WebauthnCeremonyRegistration,
+			models.WebauthnCeremonyLogin,
+			models.WebauthnCeremonyConditionalLogin,
+			models.WebauthnCeremonyTransaction,
+		}, ceremonies)
+	}
+}
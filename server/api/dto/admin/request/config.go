@@ -11,6 +11,10 @@ type CreateConfigDto struct {
 	Cors    CreateCorsDto          `json:"cors" validate:"required"`
 	Passkey CreatePasskeyConfigDto `json:"webauthn" validate:"required"`
 	Mfa     *CreateMFAConfigDto    `json:"mfa" validate:"omitempty"`
+	Jwt     *CreateJwtConfigDto    `json:"jwt" validate:"omitempty"`
+	Oidc    *CreateOidcConfigDto   `json:"oidc" validate:"omitempty"`
+	Aaguid  *CreateAaguidPolicyDto `json:"aaguid_policy" validate:"omitempty"`
+	Backup  *CreateBackupPolicyDto `json:"backup_policy" validate:"omitempty"`
 }
 
 func (dto *CreateConfigDto) ToModel(tenant models.Tenant) models.Config {
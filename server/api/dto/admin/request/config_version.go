@@ -0,0 +1,10 @@
+package request
+
+type GetConfigVersionDto struct {
+	Version int `param:"version" validate:"required,min=1"`
+}
+
+type DiffConfigVersionsDto struct {
+	From int `query:"from" validate:"required,min=1"`
+	To   int `query:"to" validate:"required,min=1"`
+}
@@ -0,0 +1,79 @@
+package request
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/teamhanko/passkey-server/persistence/models"
+	"time"
+)
+
+type CreateJwtConfigDto struct {
+	KeyRotationInterval *int                    `json:"key_rotation_interval" validate:"omitempty,min=0"`
+	KeyPendingDuration  *int                    `json:"key_pending_duration" validate:"omitempty,min=0"`
+	KeyRetiringDuration *int                    `json:"key_retiring_duration" validate:"omitempty,min=0"`
+	SigningAlgorithm    *jwa.SignatureAlgorithm `json:"signing_algorithm" validate:"omitempty,oneof=RS256 PS256 ES256 EdDSA"`
+	Issuer              *string                 `json:"issuer" validate:"omitempty,min=1"`
+	Audiences           []string                `json:"audiences" validate:"omitempty,unique,dive,required"`
+	TokenLifetime       *int                    `json:"token_lifetime" validate:"omitempty,min=1"`
+}
+
+func (dto *CreateJwtConfigDto) ToModel(configModel models.Config) models.JwtConfig {
+	jwtConfigId, _ := uuid.NewV4()
+	now := time.Now()
+
+	jwtConfig := models.JwtConfig{
+		ID:                  jwtConfigId,
+		ConfigID:            configModel.ID,
+		KeyRotationInterval: models.DefaultJwkRotationInterval,
+		KeyPendingDuration:  models.DefaultJwkPendingDuration,
+		KeyRetiringDuration: models.DefaultJwkRetiringDuration,
+		SigningAlgorithm:    models.DefaultJwtSigningAlgorithm,
+		Audiences:           make(models.JwtAudiences, 0),
+		TokenLifetime:       models.DefaultJwtTokenLifetime,
+		CreatedAt:           now,
+		UpdatedAt:           now,
+	}
+
+	if dto == nil {
+		return jwtConfig
+	}
+
+	if dto.KeyRotationInterval != nil {
+		jwtConfig.KeyRotationInterval = *dto.KeyRotationInterval
+	}
+
+	if dto.KeyPendingDuration != nil {
+		jwtConfig.KeyPendingDuration = *dto.KeyPendingDuration
+	}
+
+	if dto.KeyRetiringDuration != nil {
+		jwtConfig.KeyRetiringDuration = *dto.KeyRetiringDuration
+	}
+
+	if dto.SigningAlgorithm != nil {
+		jwtConfig.SigningAlgorithm = *dto.SigningAlgorithm
+	}
+
+	if dto.TokenLifetime != nil {
+		jwtConfig.TokenLifetime = *dto.TokenLifetime
+	}
+
+	jwtConfig.Issuer = dto.Issuer
+
+	for _, audience := range dto.Audiences {
+		audienceId, _ := uuid.NewV4()
+		jwtConfig.Audiences = append(jwtConfig.Audiences, models.JwtAudience{
+			ID:          audienceId,
+			JwtConfigID: jwtConfigId,
+			Audience:    audience,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		})
+	}
+
+	return jwtConfig
+}
+
+type RotateJwkDto struct {
+	Immediate bool `json:"immediate"`
+}
@@ -8,11 +8,13 @@ import (
 )
 
 type CreateMFAConfigDto struct {
-	Timeout                int                                   `json:"timeout" validate:"required,number"`
-	UserVerification       *protocol.UserVerificationRequirement `json:"user_verification" validate:"omitempty,oneof=required preferred discouraged"`
-	Attachment             *protocol.AuthenticatorAttachment     `json:"attachment" validate:"omitempty,oneof=platform cross-platform"`
-	AttestationPreference  *protocol.ConveyancePreference        `json:"attestation_preference" validate:"omitempty,oneof=none indirect direct enterprise"`
-	ResidentKeyRequirement *protocol.ResidentKeyRequirement      `json:"resident_key_requirement" validate:"omitempty,oneof=discouraged preferred required"`
+	Timeout                     int                                   `json:"timeout" validate:"required,number"`
+	UserVerification            *protocol.UserVerificationRequirement `json:"user_verification" validate:"omitempty,oneof=required preferred discouraged"`
+	Attachment                  *protocol.AuthenticatorAttachment     `json:"attachment" validate:"omitempty,oneof=platform cross-platform"`
+	AttestationPreference       *protocol.ConveyancePreference        `json:"attestation_preference" validate:"omitempty,oneof=none indirect direct enterprise"`
+	ResidentKeyRequirement      *protocol.ResidentKeyRequirement      `json:"resident_key_requirement" validate:"omitempty,oneof=discouraged preferred required"`
+	RequireTrustedAttestation   bool                                  `json:"require_trusted_attestation"`
+	RejectRevokedAuthenticators bool                                  `json:"reject_revoked_authenticators"`
 }
 
 func (dto *CreateMFAConfigDto) ToModel(configModel models.Config) models.MfaConfig {
@@ -20,11 +22,13 @@ func (dto *CreateMFAConfigDto) ToModel(configModel models.Config) models.MfaConf
 	now := time.Now()
 
 	mfaConfig := models.MfaConfig{
-		ID:        mfaConfigId,
-		ConfigID:  configModel.ID,
-		Timeout:   dto.Timeout,
-		CreatedAt: now,
-		UpdatedAt: now,
+		ID:                          mfaConfigId,
+		ConfigID:                    configModel.ID,
+		Timeout:                     dto.Timeout,
+		RequireTrustedAttestation:   dto.RequireTrustedAttestation,
+		RejectRevokedAuthenticators: dto.RejectRevokedAuthenticators,
+		CreatedAt:                   now,
+		UpdatedAt:                   now,
 	}
 
 	if dto.AttestationPreference == nil {
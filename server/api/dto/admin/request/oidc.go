@@ -0,0 +1,52 @@
+package request
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/teamhanko/passkey-server/persistence/models"
+	"time"
+)
+
+type CreateOidcConfigDto struct {
+	Clients []CreateOidcClientDto `json:"clients" validate:"omitempty,unique=ClientId,dive"`
+}
+
+type CreateOidcClientDto struct {
+	ClientId     string   `json:"client_id" validate:"required,max=128"`
+	RedirectUris []string `json:"redirect_uris" validate:"required,min=1,unique,dive,url"`
+}
+
+func (dto *CreateOidcConfigDto) ToModel(configModel models.Config) models.OidcClients {
+	clients := make(models.OidcClients, 0)
+	if dto == nil {
+		return clients
+	}
+
+	now := time.Now()
+	for _, clientDto := range dto.Clients {
+		clientId, _ := uuid.NewV4()
+
+		client := models.OidcClient{
+			ID:           clientId,
+			ClientID:     clientDto.ClientId,
+			ConfigID:     configModel.ID,
+			RedirectUris: make(models.OidcRedirectUris, 0, len(clientDto.RedirectUris)),
+			CreatedAt:    now,
+			UpdatedAt:    now,
+		}
+
+		for _, redirectUri := range clientDto.RedirectUris {
+			redirectUriId, _ := uuid.NewV4()
+			client.RedirectUris = append(client.RedirectUris, models.OidcRedirectUri{
+				ID:           redirectUriId,
+				RedirectUri:  redirectUri,
+				OidcClientID: clientId,
+				CreatedAt:    now,
+				UpdatedAt:    now,
+			})
+		}
+
+		clients = append(clients, client)
+	}
+
+	return clients
+}
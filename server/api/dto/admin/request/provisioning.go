@@ -0,0 +1,34 @@
+package request
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/teamhanko/passkey-server/persistence/models"
+	"time"
+)
+
+// ProvisionTenantDto is a tenant of the server config. Unlike tenants created with the admin API it has a fixed id
+// and its secrets are provided instead of generated.
+type ProvisionTenantDto struct {
+	Id          uuid.UUID            `json:"id" validate:"required"`
+	DisplayName string               `json:"display_name" validate:"required"`
+	Config      CreateConfigDto      `json:"config" validate:"required"`
+	ApiKeys     []ProvisionSecretDto `json:"api_keys" validate:"omitempty,dive"`
+	JwkKeys     []ProvisionSecretDto `json:"jwk_keys" validate:"omitempty,dive"`
+}
+
+type ProvisionSecretDto struct {
+	Name string `json:"name" validate:"required"`
+	Key  string `json:"key" validate:"required,min=16"`
+	// Scopes are only used for api keys. All scopes are granted when omitted.
+	Scopes    []models.ApiKeyScope `json:"scopes" validate:"omitempty,unique,dive,oneof=credentials:read credentials:write registration:init login:init transaction:write mfa audit_logs:read token:introspect"`
+	ExpiresAt *time.Time           `json:"expires_at" validate:"omitempty"`
+}
+
+// GetScopes returns the scopes of the api key, which are all scopes when none are set
+func (dto *ProvisionSecretDto) GetScopes() []models.ApiKeyScope {
+	if len(dto.Scopes) == 0 {
+		return models.AllApiKeyScopes
+	}
+
+	return dto.Scopes
+}
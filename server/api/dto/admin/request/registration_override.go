@@ -0,0 +1,50 @@
+package request
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/teamhanko/passkey-server/persistence/models"
+	"time"
+)
+
+type CreateRegistrationOverridesDto struct {
+	AuthenticatorAttachment []string `json:"authenticator_attachment" validate:"omitempty,unique,dive,oneof=platform cross-platform"`
+	ResidentKey             []string `json:"resident_key" validate:"omitempty,unique,dive,oneof=discouraged preferred required"`
+	UserVerification        []string `json:"user_verification" validate:"omitempty,unique,dive,oneof=required preferred discouraged"`
+	Attestation             []string `json:"attestation" validate:"omitempty,unique,dive,oneof=none indirect direct enterprise"`
+	Hints                   []string `json:"hints" validate:"omitempty,unique,dive,oneof=security-key client-device hybrid"`
+}
+
+func (dto *CreateRegistrationOverridesDto) ToModel(configModel models.Config) models.RegistrationOverrides {
+	overrides := make(models.RegistrationOverrides, 0)
+	if dto == nil {
+		return overrides
+	}
+
+	now := time.Now()
+	options := []struct {
+		option models.RegistrationOverrideOption
+		values []string
+	}{
+		{models.RegistrationOverrideOptionAuthenticatorAttachment, dto.AuthenticatorAttachment},
+		{models.RegistrationOverrideOptionResidentKey, dto.ResidentKey},
+		{models.RegistrationOverrideOptionUserVerification, dto.UserVerification},
+		{models.RegistrationOverrideOptionAttestation, dto.Attestation},
+		{models.RegistrationOverrideOptionHints, dto.Hints},
+	}
+
+	for _, option := range options {
+		for _, value := range option.values {
+			overrideId, _ := uuid.NewV4()
+			overrides = append(overrides, models.RegistrationOverride{
+				ID:        overrideId,
+				Option:    option.option,
+				Value:     value,
+				ConfigID:  configModel.ID,
+				CreatedAt: now,
+				UpdatedAt: now,
+			})
+		}
+	}
+
+	return overrides
+}
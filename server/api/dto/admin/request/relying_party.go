@@ -7,10 +7,11 @@ import (
 )
 
 type CreateRelyingPartyDto struct {
-	Id          string   `json:"id" validate:"required"`
-	DisplayName string   `json:"display_name" validate:"required"`
-	Icon        *string  `json:"icon" validate:"omitempty,url"`
-	Origins     []string `json:"origins" validate:"required,min=1"`
+	Id             string   `json:"id" validate:"required"`
+	DisplayName    string   `json:"display_name" validate:"required"`
+	Icon           *string  `json:"icon" validate:"omitempty,url"`
+	Origins        []string `json:"origins" validate:"required,min=1"`
+	RelatedOrigins []string `json:"related_origins" validate:"omitempty,unique,dive,url"`
 }
 
 func (dto *CreateRelyingPartyDto) ToModel(config models.WebauthnConfig) models.RelyingParty {
@@ -18,16 +19,33 @@ func (dto *CreateRelyingPartyDto) ToModel(config models.WebauthnConfig) models.R
 	now := time.Now()
 	var origins models.WebauthnOrigins
 
-	for _, origin := range dto.Origins {
+	relatedOrigins := make(map[string]bool)
+	for _, origin := range dto.RelatedOrigins {
+		relatedOrigins[origin] = true
+	}
+
+	// related origins are accepted like all other origins, so they are stored once and only marked as related
+	allOrigins := make([]string, 0, len(dto.Origins)+len(dto.RelatedOrigins))
+	allOrigins = append(allOrigins, dto.Origins...)
+	allOrigins = append(allOrigins, dto.RelatedOrigins...)
+
+	storedOrigins := make(map[string]bool)
+	for _, origin := range allOrigins {
+		if storedOrigins[origin] {
+			continue
+		}
+
 		originId, _ := uuid.NewV4()
 		originModel := models.WebauthnOrigin{
 			ID:        originId,
 			Origin:    origin,
+			IsRelated: relatedOrigins[origin],
 			CreatedAt: now,
 			UpdatedAt: now,
 		}
 
 		origins = append(origins, originModel)
+		storedOrigins[origin] = true
 	}
 
 	relyingParty := models.RelyingParty{
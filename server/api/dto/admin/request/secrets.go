@@ -10,27 +10,45 @@ import (
 
 type CreateSecretDto struct {
 	Name string `json:"name" validate:"required"`
+	// Scopes are only used for api keys. All scopes are granted when omitted.
+	Scopes    []models.ApiKeyScope `json:"scopes" validate:"omitempty,unique,dive,oneof=credentials:read credentials:write registration:init login:init transaction:write mfa audit_logs:read token:introspect"`
+	ExpiresAt *time.Time           `json:"expires_at" validate:"omitempty"`
 }
 
-func (dto *CreateSecretDto) ToModel(config *models.Config, isApiKey bool) (*models.Secret, error) {
+// ToModel creates a new secret. The returned key is the plain secret, which is only stored as hash for api keys.
+func (dto *CreateSecretDto) ToModel(config *models.Config, isApiKey bool) (*models.Secret, string, error) {
 	secretId, _ := uuid.NewV4()
 
 	secretKey, err := crypto.GenerateRandomStringURLSafe(64)
 	if err != nil {
-		return nil, fmt.Errorf("unable to create secret key: %w", err)
+		return nil, "", fmt.Errorf("unable to create secret key: %w", err)
 	}
 
 	now := time.Now()
 
-	return &models.Secret{
+	secret := &models.Secret{
 		ID:          secretId,
 		Name:        dto.Name,
 		Key:         secretKey,
 		IsAPISecret: isApiKey,
 		Config:      config,
+		ConfigID:    config.ID,
 		CreatedAt:   now,
 		UpdatedAt:   now,
-	}, nil
+	}
+
+	if isApiKey {
+		scopes := dto.Scopes
+		if len(scopes) == 0 {
+			scopes = models.AllApiKeyScopes
+		}
+
+		secret.Key = crypto.HashSecret(secretKey)
+		secret.Scopes = models.NewSecretScopes(secretId, scopes)
+		secret.ExpiresAt = dto.ExpiresAt
+	}
+
+	return secret, secretKey, nil
 }
 
 type RemoveSecretDto struct {
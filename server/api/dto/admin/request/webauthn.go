@@ -8,12 +8,20 @@ import (
 )
 
 type CreatePasskeyConfigDto struct {
-	RelyingParty           CreateRelyingPartyDto                 `json:"relying_party" validate:"required"`
-	Timeout                int                                   `json:"timeout" validate:"required,number"`
-	UserVerification       *protocol.UserVerificationRequirement `json:"user_verification" validate:"omitempty,oneof=required preferred discouraged"`
-	Attachment             *protocol.AuthenticatorAttachment     `json:"attachment" validate:"omitempty,oneof=platform cross-platform"`
-	AttestationPreference  *protocol.ConveyancePreference        `json:"attestation_preference" validate:"omitempty,oneof=none indirect direct enterprise"`
-	ResidentKeyRequirement *protocol.ResidentKeyRequirement      `json:"resident_key_requirement" validate:"omitempty,oneof=discouraged preferred required"`
+	RelyingParty                CreateRelyingPartyDto                 `json:"relying_party" validate:"required"`
+	Timeout                     int                                   `json:"timeout" validate:"required,number"`
+	ConditionalMediationTimeout *int                                  `json:"conditional_mediation_timeout" validate:"omitempty,number,min=1"`
+	UserVerification            *protocol.UserVerificationRequirement `json:"user_verification" validate:"omitempty,oneof=required preferred discouraged"`
+	Attachment                  *protocol.AuthenticatorAttachment     `json:"attachment" validate:"omitempty,oneof=platform cross-platform"`
+	AttestationPreference       *protocol.ConveyancePreference        `json:"attestation_preference" validate:"omitempty,oneof=none indirect direct enterprise"`
+	ResidentKeyRequirement      *protocol.ResidentKeyRequirement      `json:"resident_key_requirement" validate:"omitempty,oneof=discouraged preferred required"`
+	SignCounterPolicy           *models.SignCounterPolicy             `json:"sign_counter_policy" validate:"omitempty,oneof=log flag reject"`
+	RequireTrustedAttestation   bool                                  `json:"require_trusted_attestation"`
+	RejectRevokedAuthenticators bool                                  `json:"reject_revoked_authenticators"`
+	BackupEligibilityPolicy     *models.BackupEligibilityPolicy       `json:"backup_eligibility_policy" validate:"omitempty,oneof=flag reject"`
+	Ceremonies                  *CreateCeremoniesDto                  `json:"ceremonies"`
+	PreventUserEnumeration      bool                                  `json:"prevent_user_enumeration"`
+	RegistrationOverrides       *CreateRegistrationOverridesDto       `json:"registration_overrides"`
 }
 
 func (dto *CreatePasskeyConfigDto) ToModel(configModel models.Config) models.WebauthnConfig {
@@ -21,11 +29,14 @@ func (dto *CreatePasskeyConfigDto) ToModel(configModel models.Config) models.Web
 	now := time.Now()
 
 	passkeyConfig := models.WebauthnConfig{
-		ID:        passkeyConfigId,
-		ConfigID:  configModel.ID,
-		Timeout:   dto.Timeout,
-		CreatedAt: now,
-		UpdatedAt: now,
+		ID:                          passkeyConfigId,
+		ConfigID:                    configModel.ID,
+		Timeout:                     dto.Timeout,
+		RequireTrustedAttestation:   dto.RequireTrustedAttestation,
+		RejectRevokedAuthenticators: dto.RejectRevokedAuthenticators,
+		PreventUserEnumeration:      dto.PreventUserEnumeration,
+		CreatedAt:                   now,
+		UpdatedAt:                   now,
 	}
 
 	if dto.AttestationPreference == nil {
@@ -48,6 +59,24 @@ func (dto *CreatePasskeyConfigDto) ToModel(configModel models.Config) models.Web
 		passkeyConfig.UserVerification = *dto.UserVerification
 	}
 
+	if dto.SignCounterPolicy == nil {
+		passkeyConfig.SignCounterPolicy = models.SignCounterPolicyLog
+	} else {
+		passkeyConfig.SignCounterPolicy = *dto.SignCounterPolicy
+	}
+
+	if dto.BackupEligibilityPolicy == nil {
+		passkeyConfig.BackupEligibilityPolicy = models.BackupEligibilityPolicyFlag
+	} else {
+		passkeyConfig.BackupEligibilityPolicy = *dto.BackupEligibilityPolicy
+	}
+
+	if dto.ConditionalMediationTimeout == nil {
+		passkeyConfig.ConditionalMediationTimeout = 600000
+	} else {
+		passkeyConfig.ConditionalMediationTimeout = *dto.ConditionalMediationTimeout
+	}
+
 	return passkeyConfig
 }
 
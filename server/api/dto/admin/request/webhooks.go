@@ -0,0 +1,59 @@
+package request
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/teamhanko/passkey-server/crypto"
+	"github.com/teamhanko/passkey-server/persistence/models"
+)
+
+type CreateWebhookDto struct {
+	Url     string                `json:"url" validate:"required,url"`
+	Events  []models.AuditLogType `json:"events" validate:"required,min=1,unique"`
+	Enabled *bool                 `json:"enabled"`
+}
+
+// ToModel creates a new webhook with a generated signing secret
+func (dto *CreateWebhookDto) ToModel(tenant models.Tenant) (*models.Webhook, error) {
+	webhookId, _ := uuid.NewV4()
+
+	secret, err := crypto.GenerateRandomStringURLSafe(32)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create webhook secret: %w", err)
+	}
+
+	enabled := true
+	if dto.Enabled != nil {
+		enabled = *dto.Enabled
+	}
+
+	now := time.Now()
+
+	return &models.Webhook{
+		ID:        webhookId,
+		Url:       dto.Url,
+		Secret:    secret,
+		Enabled:   enabled,
+		Events:    models.NewWebhookEvents(webhookId, dto.Events),
+		TenantID:  tenant.ID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+type GetWebhookDto struct {
+	WebhookId string `param:"webhook_id" validate:"required,uuid4"`
+}
+
+type ListWebhookDeliveriesDto struct {
+	GetWebhookDto
+	PerPage int `query:"per_page"`
+	Page    int `query:"page"`
+}
+
+type RedeliverWebhookDto struct {
+	GetWebhookDto
+	DeliveryId string `param:"delivery_id" validate:"required,uuid4"`
+}
@@ -0,0 +1,26 @@
+package response
+
+import "github.com/teamhanko/passkey-server/persistence/models"
+
+type GetAaguidPolicyResponse struct {
+	Passkey GetAaguidListsResponse `json:"passkey"`
+	Mfa     GetAaguidListsResponse `json:"mfa"`
+}
+
+type GetAaguidListsResponse struct {
+	Allow []string `json:"allow"`
+	Deny  []string `json:"deny"`
+}
+
+func ToGetAaguidPolicyResponse(policies models.AaguidPolicies) GetAaguidPolicyResponse {
+	return GetAaguidPolicyResponse{
+		Passkey: GetAaguidListsResponse{
+			Allow: policies.GetAaguids(models.AaguidPolicyFlowPasskey, models.AaguidPolicyTypeAllow),
+			Deny:  policies.GetAaguids(models.AaguidPolicyFlowPasskey, models.AaguidPolicyTypeDeny),
+		},
+		Mfa: GetAaguidListsResponse{
+			Allow: policies.GetAaguids(models.AaguidPolicyFlowMfa, models.AaguidPolicyTypeAllow),
+			Deny:  policies.GetAaguids(models.AaguidPolicyFlowMfa, models.AaguidPolicyTypeDeny),
+		},
+	}
+}
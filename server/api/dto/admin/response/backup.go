@@ -0,0 +1,17 @@
+package response
+
+import "github.com/teamhanko/passkey-server/persistence/models"
+
+type GetBackupPolicyResponse struct {
+	Passkey     models.BackupRequirement `json:"passkey"`
+	Mfa         models.BackupRequirement `json:"mfa"`
+	Transaction models.BackupRequirement `json:"transaction"`
+}
+
+func ToGetBackupPolicyResponse(policies models.BackupPolicies) GetBackupPolicyResponse {
+	return GetBackupPolicyResponse{
+		Passkey:     policies.GetRequirement(models.BackupPolicyFlowPasskey),
+		Mfa:         policies.GetRequirement(models.BackupPolicyFlowMfa),
+		Transaction: policies.GetRequirement(models.BackupPolicyFlowTransaction),
+	}
+}
@@ -0,0 +1,36 @@
+package response
+
+import (
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/teamhanko/passkey-server/persistence/models"
+)
+
+type GetCeremonyConfigResponse struct {
+	Timeout          int                                  `json:"timeout"`
+	UserVerification protocol.UserVerificationRequirement `json:"user_verification"`
+	Attachment       *protocol.AuthenticatorAttachment    `json:"attachment,omitempty"`
+}
+
+type GetCeremoniesResponse struct {
+	Registration     GetCeremonyConfigResponse `json:"registration"`
+	Login            GetCeremonyConfigResponse `json:"login"`
+	ConditionalLogin GetCeremonyConfigResponse `json:"conditional_login"`
+	Transaction      GetCeremonyConfigResponse `json:"transaction"`
+}
+
+func ToGetCeremoniesResponse(ceremonyConfigs models.WebauthnCeremonyConfigs, webauthn *models.WebauthnConfig) GetCeremoniesResponse {
+	return GetCeremoniesResponse{
+		Registration:     toGetCeremonyConfigResponse(ceremonyConfigs.GetSettings(models.WebauthnCeremonyRegistration, *webauthn)),
+		Login:            toGetCeremonyConfigResponse(ceremonyConfigs.GetSettings(models.WebauthnCeremonyLogin, *webauthn)),
+		ConditionalLogin: toGetCeremonyConfigResponse(ceremonyConfigs.GetSettings(models.WebauthnCeremonyConditionalLogin, *webauthn)),
+		Transaction:      toGetCeremonyConfigResponse(ceremonyConfigs.GetSettings(models.WebauthnCeremonyTransaction, *webauthn)),
+	}
+}
+
+func toGetCeremonyConfigResponse(settings models.WebauthnCeremonySettings) GetCeremonyConfigResponse {
+	return GetCeremonyConfigResponse{
+		Timeout:          settings.Timeout,
+		UserVerification: settings.UserVerification,
+		Attachment:       settings.Attachment,
+	}
+}
@@ -3,15 +3,23 @@ package response
 import "github.com/teamhanko/passkey-server/persistence/models"
 
 type GetConfigResponse struct {
-	Cors     GetCorsResponse     `json:"cors"`
-	Webauthn GetWebauthnResponse `json:"webauthn"`
-	MFA      GetMFAResponse      `json:"mfa"`
+	Cors     GetCorsResponse         `json:"cors"`
+	Webauthn GetWebauthnResponse     `json:"webauthn"`
+	MFA      GetMFAResponse          `json:"mfa"`
+	Jwt      GetJwtResponse          `json:"jwt"`
+	Oidc     GetOidcResponse         `json:"oidc"`
+	Aaguid   GetAaguidPolicyResponse `json:"aaguid_policy"`
+	Backup   GetBackupPolicyResponse `json:"backup_policy"`
 }
 
 func ToGetConfigResponse(config *models.Config) GetConfigResponse {
 	return GetConfigResponse{
 		Cors:     ToGetCorsResponse(&config.Cors),
-		Webauthn: ToGetWebauthnResponse(&config.WebauthnConfig),
+		Webauthn: ToGetWebauthnResponse(&config.WebauthnConfig, config.CeremonyConfigs, config.RegistrationOverrides),
 		MFA:      ToGetMFAResponse(config.MfaConfig),
+		Jwt:      ToGetJwtResponse(config.JwtConfig),
+		Oidc:     ToGetOidcResponse(config.OidcClients),
+		Aaguid:   ToGetAaguidPolicyResponse(config.AaguidPolicies),
+		Backup:   ToGetBackupPolicyResponse(config.BackupPolicies),
 	}
 }
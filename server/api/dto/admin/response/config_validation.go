@@ -0,0 +1,53 @@
+package response
+
+import "strings"
+
+type ConfigFindingSeverity string
+
+var (
+	ConfigFindingSeverityError   ConfigFindingSeverity = "error"
+	ConfigFindingSeverityWarning ConfigFindingSeverity = "warning"
+)
+
+type ConfigFinding struct {
+	Severity ConfigFindingSeverity `json:"severity"`
+	Field    string                `json:"field"`
+	Message  string                `json:"message"`
+}
+
+type ConfigFindings []ConfigFinding
+
+// HasErrors returns true if at least one finding blocks the config from being stored
+func (findings ConfigFindings) HasErrors() bool {
+	for _, finding := range findings {
+		if finding.Severity == ConfigFindingSeverityError {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Errors joins the messages of all blocking findings
+func (findings ConfigFindings) Errors() string {
+	messages := make([]string, 0)
+	for _, finding := range findings {
+		if finding.Severity == ConfigFindingSeverityError {
+			messages = append(messages, finding.Field+": "+finding.Message)
+		}
+	}
+
+	return strings.Join(messages, " and ")
+}
+
+type ValidateConfigResponse struct {
+	Valid    bool           `json:"valid"`
+	Findings ConfigFindings `json:"findings"`
+}
+
+func ToValidateConfigResponse(findings ConfigFindings) ValidateConfigResponse {
+	return ValidateConfigResponse{
+		Valid:    !findings.HasErrors(),
+		Findings: findings,
+	}
+}
@@ -0,0 +1,50 @@
+package response
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/teamhanko/passkey-server/persistence/models"
+)
+
+type GetConfigVersionResponse struct {
+	Version    int       `json:"version"`
+	Actor      *string   `json:"actor"`
+	RollbackOf *int      `json:"rollback_of,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type ListConfigVersionsResponse []GetConfigVersionResponse
+
+type GetConfigVersionDetailResponse struct {
+	GetConfigVersionResponse
+	Config json.RawMessage `json:"config"`
+}
+
+func ToGetConfigVersionResponse(version *models.ConfigVersion) GetConfigVersionResponse {
+	return GetConfigVersionResponse{
+		Version:    version.Version,
+		Actor:      version.Actor,
+		RollbackOf: version.RollbackOf,
+		CreatedAt:  version.CreatedAt,
+	}
+}
+
+func ToGetConfigVersionDetailResponse(version *models.ConfigVersion) GetConfigVersionDetailResponse {
+	return GetConfigVersionDetailResponse{
+		GetConfigVersionResponse: ToGetConfigVersionResponse(version),
+		Config:                   json.RawMessage(version.Config),
+	}
+}
+
+type ConfigChange struct {
+	Path string      `json:"path"`
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+type ConfigDiffResponse struct {
+	From    int            `json:"from"`
+	To      int            `json:"to"`
+	Changes []ConfigChange `json:"changes"`
+}
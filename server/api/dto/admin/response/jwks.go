@@ -0,0 +1,42 @@
+package response
+
+import (
+	"github.com/lestrrat-go/jwx/v2/jwk"
+	hankoJwk "github.com/teamhanko/passkey-server/crypto/jwk"
+	"github.com/teamhanko/passkey-server/persistence/models"
+	"time"
+)
+
+type JwkResponseDto struct {
+	Id          int             `json:"id"`
+	KeyId       string          `json:"kid"`
+	Algorithm   string          `json:"alg"`
+	State       models.JwkState `json:"state"`
+	CreatedAt   time.Time       `json:"created_at"`
+	ActivatedAt *time.Time      `json:"activated_at,omitempty"`
+	RetiredAt   *time.Time      `json:"retired_at,omitempty"`
+	RevokedAt   *time.Time      `json:"revoked_at,omitempty"`
+	PublicKey   jwk.Key         `json:"public_key,omitempty"`
+}
+
+type JwkResponseListDto = []JwkResponseDto
+
+func ToJwkResponse(key hankoJwk.ManagedKey) JwkResponseDto {
+	dto := JwkResponseDto{
+		Id:          key.Model.ID,
+		KeyId:       key.PublicKey.KeyID(),
+		Algorithm:   key.Model.Algorithm.String(),
+		State:       key.Model.State,
+		CreatedAt:   key.Model.CreatedAt,
+		ActivatedAt: key.Model.ActivatedAt,
+		RetiredAt:   key.Model.RetiredAt,
+		RevokedAt:   key.Model.RevokedAt,
+	}
+
+	// revoked keys are not published anymore, so their public key is omitted as well
+	if key.Model.IsPublished() {
+		dto.PublicKey = key.PublicKey
+	}
+
+	return dto
+}
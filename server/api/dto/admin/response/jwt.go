@@ -0,0 +1,41 @@
+package response
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/teamhanko/passkey-server/persistence/models"
+)
+
+type GetJwtResponse struct {
+	KeyRotationInterval int                    `json:"key_rotation_interval"`
+	KeyPendingDuration  int                    `json:"key_pending_duration"`
+	KeyRetiringDuration int                    `json:"key_retiring_duration"`
+	SigningAlgorithm    jwa.SignatureAlgorithm `json:"signing_algorithm"`
+	Issuer              *string                `json:"issuer,omitempty"`
+	Audiences           []string               `json:"audiences"`
+	TokenLifetime       int                    `json:"token_lifetime"`
+}
+
+func ToGetJwtResponse(jwtConfig *models.JwtConfig) GetJwtResponse {
+	// tenants which did not issue a token yet have no stored jwt config and use the defaults
+	if jwtConfig == nil || jwtConfig.ID == uuid.Nil {
+		return GetJwtResponse{
+			KeyRotationInterval: models.DefaultJwkRotationInterval,
+			KeyPendingDuration:  models.DefaultJwkPendingDuration,
+			KeyRetiringDuration: models.DefaultJwkRetiringDuration,
+			SigningAlgorithm:    models.DefaultJwtSigningAlgorithm,
+			Audiences:           make([]string, 0),
+			TokenLifetime:       models.DefaultJwtTokenLifetime,
+		}
+	}
+
+	return GetJwtResponse{
+		KeyRotationInterval: jwtConfig.KeyRotationInterval,
+		KeyPendingDuration:  jwtConfig.KeyPendingDuration,
+		KeyRetiringDuration: jwtConfig.KeyRetiringDuration,
+		SigningAlgorithm:    jwtConfig.SigningAlgorithm,
+		Issuer:              jwtConfig.Issuer,
+		Audiences:           jwtConfig.Audiences.GetValues(),
+		TokenLifetime:       jwtConfig.TokenLifetime,
+	}
+}
@@ -6,19 +6,23 @@ import (
 )
 
 type GetMFAResponse struct {
-	Timeout                int                                  `json:"timeout"`
-	UserVerification       protocol.UserVerificationRequirement `json:"user_verification"`
-	Attachment             protocol.AuthenticatorAttachment     `json:"attachment"`
-	AttestationPreference  protocol.ConveyancePreference        `json:"attestation_preference"`
-	ResidentKeyRequirement protocol.ResidentKeyRequirement      `json:"resident_key_requirement"`
+	Timeout                     int                                  `json:"timeout"`
+	UserVerification            protocol.UserVerificationRequirement `json:"user_verification"`
+	Attachment                  protocol.AuthenticatorAttachment     `json:"attachment"`
+	AttestationPreference       protocol.ConveyancePreference        `json:"attestation_preference"`
+	ResidentKeyRequirement      protocol.ResidentKeyRequirement      `json:"resident_key_requirement"`
+	RequireTrustedAttestation   bool                                 `json:"require_trusted_attestation"`
+	RejectRevokedAuthenticators bool                                 `json:"reject_revoked_authenticators"`
 }
 
 func ToGetMFAResponse(webauthn *models.MfaConfig) GetMFAResponse {
 	return GetMFAResponse{
-		Timeout:                webauthn.Timeout,
-		UserVerification:       webauthn.UserVerification,
-		Attachment:             webauthn.Attachment,
-		AttestationPreference:  webauthn.AttestationPreference,
-		ResidentKeyRequirement: webauthn.ResidentKeyRequirement,
+		Timeout:                     webauthn.Timeout,
+		UserVerification:            webauthn.UserVerification,
+		Attachment:                  webauthn.Attachment,
+		AttestationPreference:       webauthn.AttestationPreference,
+		ResidentKeyRequirement:      webauthn.ResidentKeyRequirement,
+		RequireTrustedAttestation:   webauthn.RequireTrustedAttestation,
+		RejectRevokedAuthenticators: webauthn.RejectRevokedAuthenticators,
 	}
 }
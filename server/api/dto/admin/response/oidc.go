@@ -0,0 +1,26 @@
+package response
+
+import "github.com/teamhanko/passkey-server/persistence/models"
+
+type GetOidcResponse struct {
+	Clients []GetOidcClientResponse `json:"clients"`
+}
+
+type GetOidcClientResponse struct {
+	ClientId     string   `json:"client_id"`
+	RedirectUris []string `json:"redirect_uris"`
+}
+
+func ToGetOidcResponse(clients models.OidcClients) GetOidcResponse {
+	clientResponses := make([]GetOidcClientResponse, 0, len(clients))
+	for _, client := range clients {
+		clientResponses = append(clientResponses, GetOidcClientResponse{
+			ClientId:     client.ClientID,
+			RedirectUris: client.RedirectUris.GetValues(),
+		})
+	}
+
+	return GetOidcResponse{
+		Clients: clientResponses,
+	}
+}
@@ -0,0 +1,21 @@
+package response
+
+import "github.com/teamhanko/passkey-server/persistence/models"
+
+type GetRegistrationOverridesResponse struct {
+	AuthenticatorAttachment []string `json:"authenticator_attachment"`
+	ResidentKey             []string `json:"resident_key"`
+	UserVerification        []string `json:"user_verification"`
+	Attestation             []string `json:"attestation"`
+	Hints                   []string `json:"hints"`
+}
+
+func ToGetRegistrationOverridesResponse(overrides models.RegistrationOverrides) GetRegistrationOverridesResponse {
+	return GetRegistrationOverridesResponse{
+		AuthenticatorAttachment: overrides.GetValues(models.RegistrationOverrideOptionAuthenticatorAttachment),
+		ResidentKey:             overrides.GetValues(models.RegistrationOverrideOptionResidentKey),
+		UserVerification:        overrides.GetValues(models.RegistrationOverrideOptionUserVerification),
+		Attestation:             overrides.GetValues(models.RegistrationOverrideOptionAttestation),
+		Hints:                   overrides.GetValues(models.RegistrationOverrideOptionHints),
+	}
+}
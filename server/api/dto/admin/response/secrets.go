@@ -7,22 +7,46 @@ import (
 )
 
 type SecretResponseDto struct {
-	Id        uuid.UUID `json:"id"`
-	Name      string    `json:"name"`
-	Secret    string    `json:"secret"`
-	CreatedAt time.Time `json:"created_at"`
+	Id         uuid.UUID  `json:"id"`
+	Name       string     `json:"name"`
+	Secret     string     `json:"secret,omitempty"`
+	Scopes     []string   `json:"scopes,omitempty"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
 }
 
 type SecretResponseListDto = []SecretResponseDto
 
+// ToSecretResponse maps a stored secret. Api keys are only stored as hash, so their secret is never part of the response.
 func ToSecretResponse(secret *models.Secret) *SecretResponseDto {
 	if secret == nil {
 		return nil
 	}
-	return &SecretResponseDto{
+
+	dto := &SecretResponseDto{
 		Id:        secret.ID,
 		Name:      secret.Name,
-		Secret:    secret.Key,
 		CreatedAt: secret.CreatedAt,
 	}
+
+	if secret.IsAPISecret {
+		dto.Scopes = secret.Scopes.GetNames()
+		dto.ExpiresAt = secret.ExpiresAt
+		dto.LastUsedAt = secret.LastUsedAt
+	} else {
+		dto.Secret = secret.Key
+	}
+
+	return dto
+}
+
+// ToCreatedSecretResponse maps a newly created secret including its plain key. It must only be used once after creation.
+func ToCreatedSecretResponse(secret *models.Secret, key string) *SecretResponseDto {
+	dto := ToSecretResponse(secret)
+	if dto != nil {
+		dto.Secret = key
+	}
+
+	return dto
 }
@@ -36,9 +36,9 @@ type CreateTenantResponse struct {
 	ApiKey *SecretResponseDto `json:"api_key,omitempty"`
 }
 
-func ToCreateTenantResponse(tenant *models.Tenant, apiKey *models.Secret) CreateTenantResponse {
+func ToCreateTenantResponse(tenant *models.Tenant, apiSecret *models.Secret, apiKey string) CreateTenantResponse {
 	return CreateTenantResponse{
 		Id:     tenant.ID,
-		ApiKey: ToSecretResponse(apiKey),
+		ApiKey: ToCreatedSecretResponse(apiSecret, apiKey),
 	}
 }
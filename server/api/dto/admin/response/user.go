@@ -3,6 +3,7 @@ package response
 import (
 	"github.com/gofrs/uuid"
 	"github.com/teamhanko/passkey-server/api/dto/response"
+	"github.com/teamhanko/passkey-server/mapper"
 	"github.com/teamhanko/passkey-server/persistence/models"
 )
 
@@ -30,7 +31,7 @@ type UserGetDto struct {
 	Transactions []response.TransactionDto `json:"transactions"`
 }
 
-func UserGetDtoFromModel(user models.WebauthnUser) UserGetDto {
+func UserGetDtoFromModel(user models.WebauthnUser, authenticatorMetadata mapper.AuthenticatorMetadata) UserGetDto {
 	dto := UserGetDto{
 		UserListDto:  UserListDtoFromModel(user),
 		Credentials:  make([]response.CredentialDto, 0),
@@ -38,7 +39,7 @@ func UserGetDtoFromModel(user models.WebauthnUser) UserGetDto {
 	}
 
 	for _, credential := range user.WebauthnCredentials {
-		dto.Credentials = append(dto.Credentials, response.CredentialDtoFromModel(credential))
+		dto.Credentials = append(dto.Credentials, response.CredentialDtoFromModel(credential, authenticatorMetadata))
 	}
 
 	for _, transaction := range user.Transactions {
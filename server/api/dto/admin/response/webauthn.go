@@ -6,21 +6,37 @@ import (
 )
 
 type GetWebauthnResponse struct {
-	RelyingParty           GetRelyingPartyResponse              `json:"relying_party"`
-	Timeout                int                                  `json:"timeout"`
-	UserVerification       protocol.UserVerificationRequirement `json:"user_verification"`
-	Attachment             *protocol.AuthenticatorAttachment    `json:"attachment,omitempty"`
-	AttestationPreference  protocol.ConveyancePreference        `json:"attestation_preference"`
-	ResidentKeyRequirement protocol.ResidentKeyRequirement      `json:"resident_key_requirement"`
+	RelyingParty                GetRelyingPartyResponse              `json:"relying_party"`
+	Timeout                     int                                  `json:"timeout"`
+	ConditionalMediationTimeout int                                  `json:"conditional_mediation_timeout"`
+	UserVerification            protocol.UserVerificationRequirement `json:"user_verification"`
+	Attachment                  *protocol.AuthenticatorAttachment    `json:"attachment,omitempty"`
+	AttestationPreference       protocol.ConveyancePreference        `json:"attestation_preference"`
+	ResidentKeyRequirement      protocol.ResidentKeyRequirement      `json:"resident_key_requirement"`
+	SignCounterPolicy           models.SignCounterPolicy             `json:"sign_counter_policy"`
+	RequireTrustedAttestation   bool                                 `json:"require_trusted_attestation"`
+	RejectRevokedAuthenticators bool                                 `json:"reject_revoked_authenticators"`
+	BackupEligibilityPolicy     models.BackupEligibilityPolicy       `json:"backup_eligibility_policy"`
+	Ceremonies                  GetCeremoniesResponse                `json:"ceremonies"`
+	PreventUserEnumeration      bool                                 `json:"prevent_user_enumeration"`
+	RegistrationOverrides       GetRegistrationOverridesResponse     `json:"registration_overrides"`
 }
 
-func ToGetWebauthnResponse(webauthn *models.WebauthnConfig) GetWebauthnResponse {
+func ToGetWebauthnResponse(webauthn *models.WebauthnConfig, ceremonyConfigs models.WebauthnCeremonyConfigs, registrationOverrides models.RegistrationOverrides) GetWebauthnResponse {
 	return GetWebauthnResponse{
-		RelyingParty:           ToGetRelyingPartyResponse(&webauthn.RelyingParty),
-		Timeout:                webauthn.Timeout,
-		UserVerification:       webauthn.UserVerification,
-		Attachment:             webauthn.Attachment,
-		AttestationPreference:  webauthn.AttestationPreference,
-		ResidentKeyRequirement: webauthn.ResidentKeyRequirement,
+		RelyingParty:                ToGetRelyingPartyResponse(&webauthn.RelyingParty),
+		Timeout:                     webauthn.Timeout,
+		ConditionalMediationTimeout: webauthn.ConditionalMediationTimeout,
+		UserVerification:            webauthn.UserVerification,
+		Attachment:                  webauthn.Attachment,
+		AttestationPreference:       webauthn.AttestationPreference,
+		ResidentKeyRequirement:      webauthn.ResidentKeyRequirement,
+		SignCounterPolicy:           webauthn.SignCounterPolicy,
+		RequireTrustedAttestation:   webauthn.RequireTrustedAttestation,
+		RejectRevokedAuthenticators: webauthn.RejectRevokedAuthenticators,
+		BackupEligibilityPolicy:     webauthn.BackupEligibilityPolicy,
+		Ceremonies:                  ToGetCeremoniesResponse(ceremonyConfigs, webauthn),
+		PreventUserEnumeration:      webauthn.PreventUserEnumeration,
+		RegistrationOverrides:       ToGetRegistrationOverridesResponse(registrationOverrides),
 	}
 }
@@ -0,0 +1,91 @@
+package response
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/teamhanko/passkey-server/persistence/models"
+)
+
+type WebhookResponseDto struct {
+	Id        uuid.UUID             `json:"id"`
+	Url       string                `json:"url"`
+	Enabled   bool                  `json:"enabled"`
+	Events    []models.AuditLogType `json:"events"`
+	Secret    string                `json:"secret,omitempty"`
+	CreatedAt time.Time             `json:"created_at"`
+	UpdatedAt time.Time             `json:"updated_at"`
+}
+
+type WebhookResponseListDto = []WebhookResponseDto
+
+// ToWebhookResponse maps a stored webhook. The signing secret is only returned on creation.
+func ToWebhookResponse(webhook *models.Webhook) *WebhookResponseDto {
+	if webhook == nil {
+		return nil
+	}
+
+	return &WebhookResponseDto{
+		Id:        webhook.ID,
+		Url:       webhook.Url,
+		Enabled:   webhook.Enabled,
+		Events:    webhook.Events.GetValues(),
+		CreatedAt: webhook.CreatedAt,
+		UpdatedAt: webhook.UpdatedAt,
+	}
+}
+
+type WebhookDeliveryAttemptDto struct {
+	StatusCode *int      `json:"status_code,omitempty"`
+	Error      *string   `json:"error,omitempty"`
+	Duration   int       `json:"duration"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type WebhookDeliveryResponseDto struct {
+	Id            uuid.UUID                    `json:"id"`
+	Event         models.AuditLogType          `json:"event"`
+	Status        models.WebhookDeliveryStatus `json:"status"`
+	Attempts      int                          `json:"attempts"`
+	NextAttemptAt *time.Time                   `json:"next_attempt_at,omitempty"`
+	Payload       json.RawMessage              `json:"payload"`
+	AttemptLog    []WebhookDeliveryAttemptDto  `json:"attempt_log"`
+	CreatedAt     time.Time                    `json:"created_at"`
+	UpdatedAt     time.Time                    `json:"updated_at"`
+}
+
+type WebhookDeliveryResponseListDto = []WebhookDeliveryResponseDto
+
+func ToWebhookDeliveryResponse(delivery *models.WebhookDelivery) *WebhookDeliveryResponseDto {
+	if delivery == nil {
+		return nil
+	}
+
+	dto := &WebhookDeliveryResponseDto{
+		Id:         delivery.ID,
+		Event:      delivery.Event,
+		Status:     delivery.Status,
+		Attempts:   delivery.Attempts,
+		Payload:    json.RawMessage(delivery.Payload),
+		AttemptLog: make([]WebhookDeliveryAttemptDto, 0, len(delivery.AttemptLog)),
+		CreatedAt:  delivery.CreatedAt,
+		UpdatedAt:  delivery.UpdatedAt,
+	}
+
+	if delivery.Status == models.WebhookDeliveryStatusPending {
+		nextAttemptAt := delivery.NextAttemptAt
+		dto.NextAttemptAt = &nextAttemptAt
+	}
+
+	for _, attempt := range delivery.AttemptLog {
+		dto.AttemptLog = append(dto.AttemptLog, WebhookDeliveryAttemptDto{
+			StatusCode: attempt.StatusCode,
+			Error:      attempt.Error,
+			Duration:   attempt.Duration,
+			CreatedAt:  attempt.CreatedAt,
+		})
+	}
+
+	return dto
+}
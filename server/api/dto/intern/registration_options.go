@@ -0,0 +1,21 @@
+package intern
+
+import "github.com/go-webauthn/webauthn/protocol"
+
+// RegistrationOptions override the authenticator selection of the tenant for a single registration
+type RegistrationOptions struct {
+	AuthenticatorAttachment *protocol.AuthenticatorAttachment
+	ResidentKey             *protocol.ResidentKeyRequirement
+	UserVerification        *protocol.UserVerificationRequirement
+	Attestation             *protocol.ConveyancePreference
+	Hints                   []string
+}
+
+// IsEmpty returns true when no option is overridden
+func (options *RegistrationOptions) IsEmpty() bool {
+	return options.AuthenticatorAttachment == nil &&
+		options.ResidentKey == nil &&
+		options.UserVerification == nil &&
+		options.Attestation == nil &&
+		len(options.Hints) == 0
+}
@@ -5,7 +5,9 @@ import (
 	"strings"
 	"time"
 
+	"github.com/go-webauthn/webauthn/protocol"
 	"github.com/gofrs/uuid"
+	"github.com/teamhanko/passkey-server/api/dto/intern"
 	"github.com/teamhanko/passkey-server/persistence/models"
 )
 
@@ -46,6 +48,22 @@ type InitRegistrationDto struct {
 	Username    string  `json:"username" validate:"required,max=128"`
 	DisplayName *string `json:"display_name" validate:"omitempty,max=128"`
 	Icon        *string `json:"icon" validate:"omitempty,url"`
+	// the following options override the tenant config and have to be allowed by its registration overrides
+	AuthenticatorAttachment *protocol.AuthenticatorAttachment     `json:"authenticator_attachment" validate:"omitempty,oneof=platform cross-platform"`
+	ResidentKey             *protocol.ResidentKeyRequirement      `json:"resident_key" validate:"omitempty,oneof=discouraged preferred required"`
+	UserVerification        *protocol.UserVerificationRequirement `json:"user_verification" validate:"omitempty,oneof=required preferred discouraged"`
+	Attestation             *protocol.ConveyancePreference        `json:"attestation" validate:"omitempty,oneof=none indirect direct enterprise"`
+	Hints                   []string                              `json:"hints" validate:"omitempty,unique,dive,oneof=security-key client-device hybrid"`
+}
+
+func (initRegistration *InitRegistrationDto) ToRegistrationOptions() *intern.RegistrationOptions {
+	return &intern.RegistrationOptions{
+		AuthenticatorAttachment: initRegistration.AuthenticatorAttachment,
+		ResidentKey:             initRegistration.ResidentKey,
+		UserVerification:        initRegistration.UserVerification,
+		Attestation:             initRegistration.Attestation,
+		Hints:                   initRegistration.Hints,
+	}
 }
 
 func (initRegistration *InitRegistrationDto) ToModel() *models.WebauthnUser {
@@ -102,6 +120,50 @@ func (initTransaction *InitTransactionDto) ToModel() (*models.Transaction, error
 
 type InitLoginDto struct {
 	UserId *string `json:"user_id" validate:"omitempty,min=1"`
+	// Username is resolved to the user of the tenant and requires an api key like UserId
+	Username *string `json:"username" validate:"omitempty,min=1,max=128,excluded_with=UserId"`
+	// Mediation is set to 'conditional' when the credential is requested via autofill
+	Mediation *string `json:"mediation" validate:"omitempty,oneof=optional conditional"`
+}
+
+// IsConditional returns true when the login uses conditional mediation
+func (dto *InitLoginDto) IsConditional() bool {
+	return dto.Mediation != nil && *dto.Mediation == "conditional"
+}
+
+type TokenRequests interface {
+	IntrospectTokenDto
+}
+
+type IntrospectTokenDto struct {
+	Token string `json:"token" validate:"required"`
+	// Redeem marks the token as consumed, so it can only be redeemed once
+	Redeem bool `json:"redeem"`
+}
+
+type OidcRequests interface {
+	OidcAuthorizationRequestDto | OidcTokenRequestDto
+}
+
+// OidcAuthorizationRequestDto contains the parameters of an OpenID Connect authorization request. Only client_id and
+// redirect_uri are validated here, all other parameters are checked by the service, so errors can be sent back to the client.
+type OidcAuthorizationRequestDto struct {
+	ResponseType        string  `query:"response_type"`
+	ClientId            string  `query:"client_id" validate:"required"`
+	RedirectUri         string  `query:"redirect_uri" validate:"required,url"`
+	Scope               string  `query:"scope"`
+	State               *string `query:"state"`
+	Nonce               *string `query:"nonce"`
+	CodeChallenge       string  `query:"code_challenge"`
+	CodeChallengeMethod string  `query:"code_challenge_method"`
+}
+
+type OidcTokenRequestDto struct {
+	GrantType    string `form:"grant_type"`
+	Code         string `form:"code"`
+	RedirectUri  string `form:"redirect_uri"`
+	ClientId     string `form:"client_id"`
+	CodeVerifier string `form:"code_verifier"`
 }
 
 type InitMfaLoginDto struct {
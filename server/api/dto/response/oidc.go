@@ -0,0 +1,57 @@
+package response
+
+import (
+	"fmt"
+
+	"github.com/lestrrat-go/jwx/v2/jwa"
+)
+
+type OidcDiscoveryDto struct {
+	Issuer                            string                   `json:"issuer"`
+	AuthorizationEndpoint             string                   `json:"authorization_endpoint"`
+	TokenEndpoint                     string                   `json:"token_endpoint"`
+	JwksUri                           string                   `json:"jwks_uri"`
+	ResponseTypesSupported            []string                 `json:"response_types_supported"`
+	SubjectTypesSupported             []string                 `json:"subject_types_supported"`
+	IdTokenSigningAlgValuesSupported  []jwa.SignatureAlgorithm `json:"id_token_signing_alg_values_supported"`
+	ScopesSupported                   []string                 `json:"scopes_supported"`
+	TokenEndpointAuthMethodsSupported []string                 `json:"token_endpoint_auth_methods_supported"`
+	GrantTypesSupported               []string                 `json:"grant_types_supported"`
+	CodeChallengeMethodsSupported     []string                 `json:"code_challenge_methods_supported"`
+	ClaimsSupported                   []string                 `json:"claims_supported"`
+}
+
+// NewOidcDiscoveryDto creates the discovery document. The endpoints are built from the url of the tenant, as the
+// issuer can be any configured url.
+func NewOidcDiscoveryDto(issuer string, tenantUrl string, signingAlgorithm jwa.SignatureAlgorithm) OidcDiscoveryDto {
+	return OidcDiscoveryDto{
+		Issuer:                            issuer,
+		AuthorizationEndpoint:             fmt.Sprintf("%s/oidc/authorize", tenantUrl),
+		TokenEndpoint:                     fmt.Sprintf("%s/oidc/token", tenantUrl),
+		JwksUri:                           fmt.Sprintf("%s/.well-known/jwks.json", tenantUrl),
+		ResponseTypesSupported:            []string{"code"},
+		SubjectTypesSupported:             []string{"public"},
+		IdTokenSigningAlgValuesSupported:  []jwa.SignatureAlgorithm{signingAlgorithm},
+		ScopesSupported:                   []string{"openid"},
+		TokenEndpointAuthMethodsSupported: []string{"none"},
+		GrantTypesSupported:               []string{"authorization_code"},
+		CodeChallengeMethodsSupported:     []string{"S256"},
+		ClaimsSupported:                   []string{"iss", "sub", "aud", "exp", "iat", "auth_time", "nonce", "amr"},
+	}
+}
+
+type OidcTokenDto struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int    `json:"expires_in"`
+	IdToken     string `json:"id_token"`
+}
+
+type OidcAuthorizationDto struct {
+	RedirectTo string `json:"redirect_to"`
+}
+
+type OidcErrorDto struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description,omitempty"`
+}
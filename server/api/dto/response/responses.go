@@ -3,7 +3,9 @@ package response
 import (
 	"time"
 
+	"github.com/go-webauthn/webauthn/protocol"
 	"github.com/gofrs/uuid"
+	"github.com/teamhanko/passkey-server/mapper"
 	"github.com/teamhanko/passkey-server/persistence/models"
 )
 
@@ -20,15 +22,73 @@ type CredentialDto struct {
 	BackupState     bool       `json:"backup_state"`
 	IsMFA           bool       `json:"is_mfa"`
 	UserID          string     `json:"user_id"`
+	FlaggedAt       *time.Time `json:"flagged_at,omitempty"`
+	FlagReason      *string    `json:"flag_reason,omitempty"`
+	// Authenticator is resolved from the authenticator metadata on every request
+	Authenticator *AuthenticatorDto `json:"authenticator,omitempty"`
 }
 
 type CredentialDtoList []CredentialDto
 
+// CredentialCreationDto adds the hints of WebAuthn Level 3, which are not supported by go-webauthn, to the options of
+// a registration
+type CredentialCreationDto struct {
+	Response CredentialCreationOptionsDto `json:"publicKey"`
+}
+
+type CredentialCreationOptionsDto struct {
+	protocol.PublicKeyCredentialCreationOptions
+	Hints []string `json:"hints,omitempty"`
+}
+
+func CredentialCreationDtoFromCreation(credentialCreation *protocol.CredentialCreation, hints []string) CredentialCreationDto {
+	return CredentialCreationDto{
+		Response: CredentialCreationOptionsDto{
+			PublicKeyCredentialCreationOptions: credentialCreation.Response,
+			Hints:                              hints,
+		},
+	}
+}
+
+// RelatedOriginsDto is the document served at /.well-known/webauthn
+type RelatedOriginsDto struct {
+	Origins []string `json:"origins"`
+}
+
 type TokenDto struct {
 	Token string `json:"token"`
 }
 
-func CredentialDtoFromModel(credential models.WebauthnCredential) CredentialDto {
+type TokenIntrospectionDto struct {
+	Active bool                   `json:"active"`
+	Claims map[string]interface{} `json:"claims,omitempty"`
+}
+
+type AuthenticatorDto struct {
+	AAGUID    uuid.UUID `json:"aaguid"`
+	Name      string    `json:"name"`
+	IconLight string    `json:"icon_light,omitempty"`
+	IconDark  string    `json:"icon_dark,omitempty"`
+}
+
+type AuthenticatorDtoList []AuthenticatorDto
+
+func AuthenticatorDtoFromMetadata(aaguid uuid.UUID, authenticator mapper.Authenticator) AuthenticatorDto {
+	return AuthenticatorDto{
+		AAGUID:    aaguid,
+		Name:      authenticator.Name,
+		IconLight: authenticator.IconLight,
+		IconDark:  authenticator.IconDark,
+	}
+}
+
+func CredentialDtoFromModel(credential models.WebauthnCredential, authenticatorMetadata mapper.AuthenticatorMetadata) CredentialDto {
+	var authenticatorDto *AuthenticatorDto
+	if authenticator := authenticatorMetadata.GetForAaguid(credential.AAGUID); authenticator != nil {
+		dto := AuthenticatorDtoFromMetadata(credential.AAGUID, *authenticator)
+		authenticatorDto = &dto
+	}
+
 	return CredentialDto{
 		ID:              credential.ID,
 		Name:            credential.Name,
@@ -42,6 +102,9 @@ func CredentialDtoFromModel(credential models.WebauthnCredential) CredentialDto
 		BackupState:     credential.BackupState,
 		IsMFA:           credential.IsMFA,
 		UserID:          credential.UserId,
+		FlaggedAt:       credential.FlaggedAt,
+		FlagReason:      credential.FlagReason,
+		Authenticator:   authenticatorDto,
 	}
 }
 
@@ -0,0 +1,75 @@
+package response
+
+import (
+	"testing"
+
+	"github.com/gofrs/uuid"
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/stretchr/testify/assert"
+	"github.com/teamhanko/passkey-server/mapper"
+	"github.com/teamhanko/passkey-server/persistence/models"
+)
+
+var testAaguid = uuid.FromStringOrNil("cb69481e-8ff7-4039-93ec-0a2729a154a8")
+
+func TestCredentialDtoFromModelResolvesAuthenticator(t *testing.T) {
+	// given
+	credential := models.WebauthnCredential{ID: "credential", AAGUID: testAaguid}
+	authenticatorMetadata := mapper.AuthenticatorMetadata{
+		testAaguid.String(): {Name: "Security Key", IconLight: "data:image/svg+xml;base64,light", IconDark: "data:image/svg+xml;base64,dark"},
+	}
+
+	// when
+	dto := CredentialDtoFromModel(credential, authenticatorMetadata)
+
+	// then
+	assert.Equal(t, "credential", dto.ID)
+	assert.Equal(t, testAaguid, dto.AAGUID)
+	assert.Equal(t, &AuthenticatorDto{
+		AAGUID:    testAaguid,
+		Name:      "Security Key",
+		IconLight: "data:image/svg+xml;base64,light",
+		IconDark:  "data:image/svg+xml;base64,dark",
+	}, dto.Authenticator)
+}
+
+func TestCredentialDtoFromModelWithUnknownAuthenticator(t *testing.T) {
+	// given
+	credential := models.WebauthnCredential{ID: "credential", AAGUID: uuid.Nil}
+	authenticatorMetadata := mapper.AuthenticatorMetadata{
+		testAaguid.String(): {Name: "Security Key"},
+	}
+
+	// when
+	dto := CredentialDtoFromModel(credential, authenticatorMetadata)
+
+	// then
+	assert.Equal(t, uuid.Nil, dto.AAGUID)
+	assert.Nil(t, dto.Authenticator)
+}
+
+func TestCredentialDtoFromModelWithoutMetadata(t *testing.T) {
+	// given
+	credential := models.WebauthnCredential{ID: "credential", AAGUID: testAaguid}
+
+	// when
+	dto := CredentialDtoFromModel(credential, nil)
+
+	// then
+	assert.Nil(t, dto.Authenticator)
+}
+
+func TestOidcDiscoveryEndpointsUseTenantUrl(t *testing.T) {
+	// given
+	issuer := "https://login.example.com"
+	tenantUrl := "https://passkeys.example.com/0f3bf1c8-32b0-4a64-8f6a-3f0dbbd4a4c5"
+
+	// when
+	discovery := NewOidcDiscoveryDto(issuer, tenantUrl, jwa.ES256)
+
+	// then
+	assert.Equal(t, issuer, discovery.Issuer)
+	assert.Equal(t, tenantUrl+"/oidc/authorize", discovery.AuthorizationEndpoint)
+	assert.Equal(t, tenantUrl+"/oidc/token", discovery.TokenEndpoint)
+	assert.Equal(t, tenantUrl+"/.well-known/jwks.json", discovery.JwksUri)
+}
@@ -0,0 +1,69 @@
+package admin
+
+import (
+	"github.com/gobuffalo/pop/v6"
+	"github.com/labstack/echo/v4"
+	"github.com/teamhanko/passkey-server/api/dto/admin/request"
+	"github.com/teamhanko/passkey-server/api/helper"
+	"github.com/teamhanko/passkey-server/api/services/admin"
+	"github.com/teamhanko/passkey-server/persistence"
+	"net/http"
+)
+
+type JwkHandler struct {
+	persister persistence.Persister
+}
+
+func NewJwkHandler(persister persistence.Persister) *JwkHandler {
+	return &JwkHandler{
+		persister: persister,
+	}
+}
+
+func (jh *JwkHandler) List(ctx echo.Context) error {
+	h, err := helper.GetHandlerContext(ctx)
+	if err != nil {
+		ctx.Logger().Error(err)
+		return err
+	}
+
+	service := admin.NewJwkService(ctx, *h.Tenant, jh.persister.GetJwkPersister(nil))
+	jwks, err := service.List()
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, jwks)
+}
+
+func (jh *JwkHandler) Rotate(ctx echo.Context) error {
+	var dto request.RotateJwkDto
+	err := ctx.Bind(&dto)
+	if err != nil {
+		ctx.Logger().Error(err)
+		return echo.NewHTTPError(http.StatusBadRequest, "unable to rotate jwks").SetInternal(err)
+	}
+
+	h, err := helper.GetHandlerContext(ctx)
+	if err != nil {
+		ctx.Logger().Error(err)
+		return err
+	}
+
+	return jh.persister.Transaction(func(tx *pop.Connection) error {
+		// the key rotator locks the tenant as well, so the keys are not changed by both at the same time
+		err := jh.persister.GetTenantPersister(tx).Lock(h.Tenant.ID)
+		if err != nil {
+			ctx.Logger().Error(err)
+			return err
+		}
+
+		service := admin.NewJwkService(ctx, *h.Tenant, jh.persister.GetJwkPersister(tx))
+		jwk, err := service.Rotate(dto)
+		if err != nil {
+			return err
+		}
+
+		return ctx.JSON(http.StatusCreated, jwk)
+	})
+}
@@ -10,9 +10,11 @@ import (
 	"github.com/teamhanko/passkey-server/api/pagination"
 	"github.com/teamhanko/passkey-server/api/services/admin"
 	"github.com/teamhanko/passkey-server/persistence"
+	"io"
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 )
 
 type TenantHandler struct {
@@ -26,6 +28,11 @@ func NewTenantHandler(persister persistence.Persister) *TenantHandler {
 }
 
 func (th *TenantHandler) List(ctx echo.Context) error {
+	principal, err := helper.GetAdminPrincipal(ctx)
+	if err != nil {
+		return err
+	}
+
 	service := admin.NewTenantService(admin.CreateTenantServiceParams{
 		Ctx:             ctx,
 		TenantPersister: th.persister.GetTenantPersister(nil),
@@ -36,12 +43,28 @@ func (th *TenantHandler) List(ctx echo.Context) error {
 		return err
 	}
 
-	return ctx.JSON(http.StatusOK, tenantList)
+	accessibleTenants := make(response.ListTenantResponses, 0)
+	for _, tenant := range *tenantList {
+		if principal.CanAccessTenant(tenant.Id) {
+			accessibleTenants = append(accessibleTenants, tenant)
+		}
+	}
+
+	return ctx.JSON(http.StatusOK, accessibleTenants)
 }
 
 func (th *TenantHandler) Create(ctx echo.Context) error {
+	principal, err := helper.GetAdminPrincipal(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !principal.AllTenants {
+		return echo.NewHTTPError(http.StatusForbidden, "admin credentials are restricted to specific tenants")
+	}
+
 	var dto request.CreateTenantDto
-	err := ctx.Bind(&dto)
+	err = ctx.Bind(&dto)
 	if err != nil {
 		ctx.Logger().Error(err)
 		return echo.NewHTTPError(http.StatusBadRequest, "unable to create tenant").SetInternal(err)
@@ -55,17 +78,25 @@ func (th *TenantHandler) Create(ctx echo.Context) error {
 
 	return th.persister.Transaction(func(tx *pop.Connection) error {
 		service := admin.NewTenantService(admin.CreateTenantServiceParams{
-			Ctx: ctx,
-
-			TenantPersister:         th.persister.GetTenantPersister(tx),
-			ConfigPersister:         th.persister.GetConfigPersister(tx),
-			CorsPersister:           th.persister.GetCorsPersister(tx),
-			WebauthnConfigPersister: th.persister.GetWebauthnConfigPersister(tx),
-			RelyingPartyPerister:    th.persister.GetWebauthnRelyingPartyPersister(tx),
-			AuditConfigPersister:    th.persister.GetAuditLogConfigPersister(tx),
-			SecretPersister:         th.persister.GetSecretsPersister(tx),
-			JwkPersister:            th.persister.GetJwkPersister(tx),
-			MFAConfigPersister:      th.persister.GetMFAConfigPersister(tx),
+			Ctx:   ctx,
+			Actor: principal.Subject,
+
+			TenantPersister:               th.persister.GetTenantPersister(tx),
+			ConfigPersister:               th.persister.GetConfigPersister(tx),
+			CorsPersister:                 th.persister.GetCorsPersister(tx),
+			WebauthnConfigPersister:       th.persister.GetWebauthnConfigPersister(tx),
+			RelyingPartyPerister:          th.persister.GetWebauthnRelyingPartyPersister(tx),
+			AuditConfigPersister:          th.persister.GetAuditLogConfigPersister(tx),
+			SecretPersister:               th.persister.GetSecretsPersister(tx),
+			JwkPersister:                  th.persister.GetJwkPersister(tx),
+			MFAConfigPersister:            th.persister.GetMFAConfigPersister(tx),
+			JwtConfigPersister:            th.persister.GetJwtConfigPersister(tx),
+			OidcClientPersister:           th.persister.GetOidcClientPersister(tx),
+			AaguidPolicyPersister:         th.persister.GetAaguidPolicyPersister(tx),
+			BackupPolicyPersister:         th.persister.GetBackupPolicyPersister(tx),
+			CeremonyConfigPersister:       th.persister.GetWebauthnCeremonyConfigPersister(tx),
+			RegistrationOverridePersister: th.persister.GetRegistrationOverridePersister(tx),
+			ConfigVersionPersister:        th.persister.GetConfigVersionPersister(tx),
 		})
 
 		createResponse, err := service.Create(dto)
@@ -150,35 +181,212 @@ func (th *TenantHandler) UpdateConfig(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, "unable to update tenant config").SetInternal(err)
 	}
 
+	return th.persister.GetConnection().Transaction(func(tx *pop.Connection) error {
+		service, err := th.createConfigService(ctx, tx)
+		if err != nil {
+			return err
+		}
+
+		eTag, err := service.UpdateConfig(dto, ctx.Request().Header.Get("If-Match"))
+		if err != nil {
+			return err
+		}
+
+		ctx.Response().Header().Set("ETag", eTag)
+
+		return ctx.NoContent(http.StatusNoContent)
+	})
+}
+
+func (th *TenantHandler) GetConfig(ctx echo.Context) error {
+	service, err := th.createConfigService(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	config, eTag, err := service.GetConfig()
+	if err != nil {
+		return err
+	}
+
+	ctx.Response().Header().Set("ETag", eTag)
+
+	return ctx.JSON(http.StatusOK, config)
+}
+
+// PatchConfig applies a JSON merge patch to the config. Like all config changes it requires the If-Match header, so
+// changes of other admins are not overwritten.
+func (th *TenantHandler) PatchConfig(ctx echo.Context) error {
+	ifMatch := ctx.Request().Header.Get("If-Match")
+	contentType := ctx.Request().Header.Get(echo.HeaderContentType)
+	if !strings.HasPrefix(contentType, "application/merge-patch+json") && !strings.HasPrefix(contentType, echo.MIMEApplicationJSON) {
+		return echo.NewHTTPError(http.StatusUnsupportedMediaType, "the patch must be of type application/merge-patch+json")
+	}
+
+	patch, err := io.ReadAll(ctx.Request().Body)
+	if err != nil {
+		ctx.Logger().Error(err)
+		return echo.NewHTTPError(http.StatusBadRequest, "unable to patch tenant config").SetInternal(err)
+	}
+
+	return th.persister.GetConnection().Transaction(func(tx *pop.Connection) error {
+		service, err := th.createConfigService(ctx, tx)
+		if err != nil {
+			return err
+		}
+
+		eTag, err := service.PatchConfig(patch, ifMatch)
+		if err != nil {
+			return err
+		}
+
+		ctx.Response().Header().Set("ETag", eTag)
+
+		return ctx.NoContent(http.StatusNoContent)
+	})
+}
+
+func (th *TenantHandler) ValidateConfig(ctx echo.Context) error {
+	var dto request.UpdateConfigDto
+	err := ctx.Bind(&dto)
+	if err != nil {
+		ctx.Logger().Error(err)
+		return echo.NewHTTPError(http.StatusBadRequest, "unable to validate tenant config").SetInternal(err)
+	}
+
+	err = ctx.Validate(&dto)
+	if err != nil {
+		ctx.Logger().Error(err)
+		return echo.NewHTTPError(http.StatusBadRequest, "unable to validate tenant config").SetInternal(err)
+	}
+
 	h, err := helper.GetHandlerContext(ctx)
 	if err != nil {
 		ctx.Logger().Error(err)
 		return err
 	}
 
+	service := admin.NewTenantService(admin.CreateTenantServiceParams{
+		Ctx:    ctx,
+		Tenant: h.Tenant,
+	})
+
+	return ctx.JSON(http.StatusOK, service.ValidateConfig(dto))
+}
+
+func (th *TenantHandler) ListConfigVersions(ctx echo.Context) error {
+	service, err := th.createConfigService(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	versions, err := service.ListConfigVersions()
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, versions)
+}
+
+func (th *TenantHandler) GetConfigVersion(ctx echo.Context) error {
+	var dto request.GetConfigVersionDto
+	err := bindAndValidate(ctx, &dto, "unable to get config version")
+	if err != nil {
+		return err
+	}
+
+	service, err := th.createConfigService(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	version, err := service.GetConfigVersion(dto)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, version)
+}
+
+func (th *TenantHandler) DiffConfigVersions(ctx echo.Context) error {
+	var dto request.DiffConfigVersionsDto
+	err := bindAndValidate(ctx, &dto, "unable to diff config versions")
+	if err != nil {
+		return err
+	}
+
+	service, err := th.createConfigService(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	diff, err := service.DiffConfigVersions(dto)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, diff)
+}
+
+func (th *TenantHandler) RollbackConfig(ctx echo.Context) error {
+	var dto request.GetConfigVersionDto
+	err := bindAndValidate(ctx, &dto, "unable to roll back tenant config")
+	if err != nil {
+		return err
+	}
+
 	return th.persister.GetConnection().Transaction(func(tx *pop.Connection) error {
-		service := admin.NewTenantService(admin.CreateTenantServiceParams{
-			Ctx:    ctx,
-			Tenant: h.Tenant,
-
-			ConfigPersister:         th.persister.GetConfigPersister(tx),
-			CorsPersister:           th.persister.GetCorsPersister(tx),
-			WebauthnConfigPersister: th.persister.GetWebauthnConfigPersister(tx),
-			RelyingPartyPerister:    th.persister.GetWebauthnRelyingPartyPersister(tx),
-			AuditConfigPersister:    th.persister.GetAuditLogConfigPersister(tx),
-			SecretPersister:         th.persister.GetSecretsPersister(tx),
-			MFAConfigPersister:      th.persister.GetMFAConfigPersister(tx),
-		})
+		service, err := th.createConfigService(ctx, tx)
+		if err != nil {
+			return err
+		}
 
-		err := service.UpdateConfig(dto)
+		eTag, err := service.RollbackConfig(dto, ctx.Request().Header.Get("If-Match"))
 		if err != nil {
 			return err
 		}
 
+		ctx.Response().Header().Set("ETag", eTag)
+
 		return ctx.NoContent(http.StatusNoContent)
 	})
 }
 
+// createConfigService creates a tenant service which is able to change the config of the tenant in the context
+func (th *TenantHandler) createConfigService(ctx echo.Context, tx *pop.Connection) (admin.TenantService, error) {
+	principal, err := helper.GetAdminPrincipal(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	h, err := helper.GetHandlerContext(ctx)
+	if err != nil {
+		ctx.Logger().Error(err)
+		return nil, err
+	}
+
+	return admin.NewTenantService(admin.CreateTenantServiceParams{
+		Ctx:    ctx,
+		Tenant: h.Tenant,
+		Actor:  principal.Subject,
+
+		ConfigPersister:               th.persister.GetConfigPersister(tx),
+		CorsPersister:                 th.persister.GetCorsPersister(tx),
+		WebauthnConfigPersister:       th.persister.GetWebauthnConfigPersister(tx),
+		RelyingPartyPerister:          th.persister.GetWebauthnRelyingPartyPersister(tx),
+		AuditConfigPersister:          th.persister.GetAuditLogConfigPersister(tx),
+		SecretPersister:               th.persister.GetSecretsPersister(tx),
+		MFAConfigPersister:            th.persister.GetMFAConfigPersister(tx),
+		JwtConfigPersister:            th.persister.GetJwtConfigPersister(tx),
+		OidcClientPersister:           th.persister.GetOidcClientPersister(tx),
+		AaguidPolicyPersister:         th.persister.GetAaguidPolicyPersister(tx),
+		BackupPolicyPersister:         th.persister.GetBackupPolicyPersister(tx),
+		CeremonyConfigPersister:       th.persister.GetWebauthnCeremonyConfigPersister(tx),
+		RegistrationOverridePersister: th.persister.GetRegistrationOverridePersister(tx),
+		ConfigVersionPersister:        th.persister.GetConfigVersionPersister(tx),
+	}), nil
+}
+
 func (th *TenantHandler) ListAuditLog(ctx echo.Context) error {
 	var dto request.ListAuditLogDto
 	err := ctx.Bind(&dto)
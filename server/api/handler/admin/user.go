@@ -9,6 +9,7 @@ import (
 	"github.com/teamhanko/passkey-server/api/helper"
 	"github.com/teamhanko/passkey-server/api/pagination"
 	"github.com/teamhanko/passkey-server/api/services/admin"
+	"github.com/teamhanko/passkey-server/mapper"
 	"github.com/teamhanko/passkey-server/persistence"
 	"net/http"
 	"net/url"
@@ -23,11 +24,12 @@ type UserHandler interface {
 }
 
 type userHandler struct {
-	persister persistence.Persister
+	persister             persistence.Persister
+	authenticatorMetadata mapper.AuthenticatorMetadata
 }
 
-func NewUserHandler(persister persistence.Persister) UserHandler {
-	return &userHandler{persister: persister}
+func NewUserHandler(persister persistence.Persister, authenticatorMetadata mapper.AuthenticatorMetadata) UserHandler {
+	return &userHandler{persister: persister, authenticatorMetadata: authenticatorMetadata}
 }
 
 func (uh *userHandler) List(ctx echo.Context) error {
@@ -106,6 +108,8 @@ func (uh *userHandler) Get(ctx echo.Context) error {
 			Ctx:           ctx,
 			Tenant:        *h.Tenant,
 			UserPersister: userPersister,
+
+			AuthenticatorMetadata: uh.authenticatorMetadata,
 		})
 
 		user, err := userService.Get(userId)
@@ -0,0 +1,183 @@
+package admin
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/teamhanko/passkey-server/api/dto/admin/request"
+	"github.com/teamhanko/passkey-server/api/helper"
+	"github.com/teamhanko/passkey-server/api/pagination"
+	"github.com/teamhanko/passkey-server/api/services/admin"
+	"github.com/teamhanko/passkey-server/persistence"
+)
+
+type WebhookHandler struct {
+	persister persistence.Persister
+}
+
+func NewWebhookHandler(persister persistence.Persister) *WebhookHandler {
+	return &WebhookHandler{
+		persister: persister,
+	}
+}
+
+func (wh *WebhookHandler) List(ctx echo.Context) error {
+	service, err := wh.createService(ctx)
+	if err != nil {
+		return err
+	}
+
+	webhooks, err := service.List()
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, webhooks)
+}
+
+func (wh *WebhookHandler) Create(ctx echo.Context) error {
+	var dto request.CreateWebhookDto
+	err := bindAndValidate(ctx, &dto, "unable to create webhook")
+	if err != nil {
+		return err
+	}
+
+	service, err := wh.createService(ctx)
+	if err != nil {
+		return err
+	}
+
+	webhook, err := service.Create(dto)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusCreated, webhook)
+}
+
+func (wh *WebhookHandler) Get(ctx echo.Context) error {
+	var dto request.GetWebhookDto
+	err := bindAndValidate(ctx, &dto, "unable to get webhook")
+	if err != nil {
+		return err
+	}
+
+	service, err := wh.createService(ctx)
+	if err != nil {
+		return err
+	}
+
+	webhook, err := service.Get(dto)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, webhook)
+}
+
+func (wh *WebhookHandler) Remove(ctx echo.Context) error {
+	var dto request.GetWebhookDto
+	err := bindAndValidate(ctx, &dto, "unable to remove webhook")
+	if err != nil {
+		return err
+	}
+
+	service, err := wh.createService(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = service.Remove(dto)
+	if err != nil {
+		return err
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (wh *WebhookHandler) ListDeliveries(ctx echo.Context) error {
+	var dto request.ListWebhookDeliveriesDto
+	err := bindAndValidate(ctx, &dto, "unable to list webhook deliveries")
+	if err != nil {
+		return err
+	}
+
+	if dto.Page == 0 {
+		dto.Page = 1
+	}
+
+	if dto.PerPage == 0 {
+		dto.PerPage = 20
+	}
+
+	service, err := wh.createService(ctx)
+	if err != nil {
+		return err
+	}
+
+	deliveries, count, err := service.ListDeliveries(dto)
+	if err != nil {
+		return err
+	}
+
+	u, _ := url.Parse(fmt.Sprintf("%s://%s%s", ctx.Scheme(), ctx.Request().Host, ctx.Request().RequestURI))
+
+	ctx.Response().Header().Set("Link", pagination.CreateHeader(u, count, dto.Page, dto.PerPage))
+	ctx.Response().Header().Set("X-Total-Count", strconv.FormatInt(int64(count), 10))
+
+	return ctx.JSON(http.StatusOK, deliveries)
+}
+
+func (wh *WebhookHandler) Redeliver(ctx echo.Context) error {
+	var dto request.RedeliverWebhookDto
+	err := bindAndValidate(ctx, &dto, "unable to redeliver webhook")
+	if err != nil {
+		return err
+	}
+
+	service, err := wh.createService(ctx)
+	if err != nil {
+		return err
+	}
+
+	delivery, err := service.Redeliver(dto)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusAccepted, delivery)
+}
+
+func (wh *WebhookHandler) createService(ctx echo.Context) (admin.WebhookService, error) {
+	h, err := helper.GetHandlerContext(ctx)
+	if err != nil {
+		ctx.Logger().Error(err)
+		return nil, err
+	}
+
+	return admin.NewWebhookService(admin.CreateWebhookServiceParams{
+		Ctx:                      ctx,
+		Tenant:                   *h.Tenant,
+		WebhookPersister:         wh.persister.GetWebhookPersister(nil),
+		WebhookDeliveryPersister: wh.persister.GetWebhookDeliveryPersister(nil),
+	}), nil
+}
+
+func bindAndValidate(ctx echo.Context, dto interface{}, message string) error {
+	err := ctx.Bind(dto)
+	if err != nil {
+		ctx.Logger().Error(err)
+		return echo.NewHTTPError(http.StatusBadRequest, message).SetInternal(err)
+	}
+
+	err = ctx.Validate(dto)
+	if err != nil {
+		ctx.Logger().Error(err)
+		return echo.NewHTTPError(http.StatusBadRequest, message).SetInternal(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/gofrs/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/teamhanko/passkey-server/api/dto/response"
+	"github.com/teamhanko/passkey-server/mapper"
+)
+
+type AuthenticatorHandler struct {
+	mapper.AuthenticatorMetadata
+}
+
+func NewAuthenticatorHandler(authenticatorMetadata mapper.AuthenticatorMetadata) *AuthenticatorHandler {
+	return &AuthenticatorHandler{authenticatorMetadata}
+}
+
+// List returns the catalog of all known authenticators sorted by name
+func (h *AuthenticatorHandler) List(ctx echo.Context) error {
+	dtos := make(response.AuthenticatorDtoList, 0, len(h.AuthenticatorMetadata))
+	for rawAaguid, authenticator := range h.AuthenticatorMetadata {
+		aaguid, err := uuid.FromString(rawAaguid)
+		if err != nil {
+			continue
+		}
+
+		dtos = append(dtos, response.AuthenticatorDtoFromMetadata(aaguid, authenticator))
+	}
+
+	sort.Slice(dtos, func(i, j int) bool {
+		if dtos[i].Name == dtos[j].Name {
+			return dtos[i].AAGUID.String() < dtos[j].AAGUID.String()
+		}
+
+		return dtos[i].Name < dtos[j].Name
+	})
+
+	ctx.Response().Header().Add("Cache-Control", "max-age=600")
+	return ctx.JSON(http.StatusOK, dtos)
+}
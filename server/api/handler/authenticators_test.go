@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/teamhanko/passkey-server/api/dto/response"
+	"github.com/teamhanko/passkey-server/mapper"
+)
+
+func TestAuthenticatorHandlerListsSortedCatalog(t *testing.T) {
+	// given
+	h := NewAuthenticatorHandler(mapper.AuthenticatorMetadata{
+		"fbfc3007-154e-4ecc-8c0b-6e020557d7bd": {Name: "iCloud Keychain"},
+		"ea9b8d66-4d01-1d21-3ce4-b6b48cb575d4": {Name: "Google Password Manager"},
+		"cb69481e-8ff7-4039-93ec-0a2729a154a8": {Name: "YubiKey 5"},
+		"2fc0579f-8113-47ea-b116-bb5a8db9202a": {Name: "YubiKey 5"},
+		"not-an-aaguid":                        {Name: "Invalid"},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/authenticators", nil)
+	rec := httptest.NewRecorder()
+	ctx := echo.New().NewContext(req, rec)
+
+	// when
+	err := h.List(ctx)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "max-age=600", rec.Header().Get("Cache-Control"))
+
+	var dtos response.AuthenticatorDtoList
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &dtos))
+
+	var entries []string
+	for _, dto := range dtos {
+		entries = append(entries, dto.Name+" "+dto.AAGUID.String())
+	}
+
+	assert.Equal(t, []string{
+		"Google Password Manager ea9b8d66-4d01-1d21-3ce4-b6b48cb575d4",
+		"YubiKey 5 2fc0579f-8113-47ea-b116-bb5a8db9202a",
+		"YubiKey 5 cb69481e-8ff7-4039-93ec-0a2729a154a8",
+		"iCloud Keychain fbfc3007-154e-4ecc-8c0b-6e020557d7bd",
+	}, entries)
+}
+
+func TestAuthenticatorHandlerListsEmptyCatalog(t *testing.T) {
+	// given
+	h := NewAuthenticatorHandler(nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/authenticators", nil)
+	rec := httptest.NewRecorder()
+	ctx := echo.New().NewContext(req, rec)
+
+	// when
+	err := h.List(ctx)
+
+	// then
+	assert.NoError(t, err)
+	assert.JSONEq(t, "[]", rec.Body.String())
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/teamhanko/passkey-server/api/helper"
 	"github.com/teamhanko/passkey-server/api/pagination"
 	"github.com/teamhanko/passkey-server/api/services"
+	"github.com/teamhanko/passkey-server/mapper"
 	"github.com/teamhanko/passkey-server/persistence"
 	"github.com/teamhanko/passkey-server/persistence/models"
 )
@@ -26,13 +27,15 @@ type CredentialsHandler interface {
 
 type credentialsHandler struct {
 	*webauthnHandler
+	mapper.AuthenticatorMetadata
 }
 
-func NewCredentialsHandler(persister persistence.Persister) CredentialsHandler {
+func NewCredentialsHandler(persister persistence.Persister, authenticatorMetadata mapper.AuthenticatorMetadata) CredentialsHandler {
 	webauthnHandler := newWebAuthnHandler(persister, false)
 
 	return &credentialsHandler{
 		webauthnHandler,
+		authenticatorMetadata,
 	}
 }
 
@@ -57,7 +60,7 @@ func (credHandler *credentialsHandler) List(ctx echo.Context) error {
 		return err
 	}
 
-	service := services.NewCredentialService(ctx, *h.Tenant, credHandler.persister.GetWebauthnCredentialPersister(nil))
+	service := services.NewCredentialService(ctx, *h.Tenant, credHandler.persister.GetWebauthnCredentialPersister(nil), credHandler.AuthenticatorMetadata)
 	dtos, credentialsCount, err := service.List(*requestDto)
 	if err != nil {
 		return err
@@ -84,13 +87,13 @@ func (credHandler *credentialsHandler) Get(ctx echo.Context) error {
 		return err
 	}
 
-	service := services.NewCredentialService(ctx, *h.Tenant, credHandler.persister.GetWebauthnCredentialPersister(nil))
+	service := services.NewCredentialService(ctx, *h.Tenant, credHandler.persister.GetWebauthnCredentialPersister(nil), credHandler.AuthenticatorMetadata)
 	credential, err := service.Get(*requestDto)
 	if err != nil {
 		return err
 	}
 
-	credentialDto := response.CredentialDtoFromModel(*credential)
+	credentialDto := response.CredentialDtoFromModel(*credential, credHandler.AuthenticatorMetadata)
 
 	return ctx.JSON(http.StatusOK, credentialDto)
 }
@@ -110,7 +113,7 @@ func (credHandler *credentialsHandler) Update(ctx echo.Context) error {
 	return credHandler.persister.Transaction(func(tx *pop.Connection) error {
 		credentialPersister := credHandler.persister.GetWebauthnCredentialPersister(tx)
 
-		service := services.NewCredentialService(ctx, *h.Tenant, credentialPersister)
+		service := services.NewCredentialService(ctx, *h.Tenant, credentialPersister, credHandler.AuthenticatorMetadata)
 		credential, err := service.Update(*requestDto)
 		if err != nil {
 			return err
@@ -141,7 +144,7 @@ func (credHandler *credentialsHandler) Delete(ctx echo.Context) error {
 	return credHandler.persister.Transaction(func(tx *pop.Connection) error {
 		credentialPersister := credHandler.persister.GetWebauthnCredentialPersister(tx)
 
-		service := services.NewCredentialService(ctx, *h.Tenant, credentialPersister)
+		service := services.NewCredentialService(ctx, *h.Tenant, credentialPersister, credHandler.AuthenticatorMetadata)
 		err := service.Delete(*requestDto)
 		if err != nil {
 			return err
@@ -40,16 +40,23 @@ func (lh *loginHandler) Init(ctx echo.Context) error {
 		return err
 	}
 
+	// usernames require an api key as well, otherwise the endpoint reveals which usernames exist
 	apiKey := ctx.Request().Header.Get("apiKey")
-	if dto.UserId != nil {
+	if dto.UserId != nil || dto.Username != nil {
 		if strings.TrimSpace(apiKey) == "" {
 			return echo.NewHTTPError(http.StatusUnauthorized, "api key is missing")
 		}
 
-		err = helper.CheckApiKey(h.Config.Secrets, apiKey)
+		secret, err := helper.CheckApiKey(h.Config.Secrets, apiKey, models.ApiKeyScopeLoginInit)
 		if err != nil {
 			return err
 		}
+
+		err = helper.UpdateApiKeyUsage(lh.persister, secret)
+		if err != nil {
+			ctx.Logger().Error(err)
+			return err
+		}
 	}
 
 	return lh.persister.GetConnection().Transaction(func(tx *pop.Connection) error {
@@ -58,22 +65,32 @@ func (lh *loginHandler) Init(ctx echo.Context) error {
 		credentialPersister := lh.persister.GetWebauthnCredentialPersister(tx)
 
 		service := services.NewLoginService(services.WebauthnServiceCreateParams{
-			Ctx:                 ctx,
-			Tenant:              *h.Tenant,
-			WebauthnClient:      *h.WebauthnClient,
-			UserId:              dto.UserId,
-			UserPersister:       userPersister,
-			SessionPersister:    sessionPersister,
-			CredentialPersister: credentialPersister,
+			Ctx:                  ctx,
+			Tenant:               *h.Tenant,
+			WebauthnClient:       *h.WebauthnClient,
+			AuditLog:             h.AuditLog,
+			Tx:                   tx,
+			UserId:               dto.UserId,
+			Username:             dto.Username,
+			ConditionalMediation: dto.IsConditional(),
+			UserPersister:        userPersister,
+			SessionPersister:     sessionPersister,
+			CredentialPersister:  credentialPersister,
+			TenantPersister:      lh.persister.GetTenantPersister(tx),
 		})
 
+		failedType, succeededType := models.AuditLogWebAuthnAuthenticationInitFailed, models.AuditLogWebAuthnAuthenticationInitSucceeded
+		if dto.IsConditional() {
+			failedType, succeededType = models.AuditLogWebAuthnConditionalAuthenticationInitFailed, models.AuditLogWebAuthnConditionalAuthenticationInitSucceeded
+		}
+
 		credentialAssertion, err := service.Initialize()
-		err = lh.handleError(h.AuditLog, models.AuditLogWebAuthnAuthenticationInitFailed, tx, ctx, dto.UserId, nil, err)
+		err = lh.handleError(h.AuditLog, failedType, tx, ctx, dto.UserId, nil, err)
 		if err != nil {
 			return err
 		}
 
-		auditErr := h.AuditLog.CreateWithConnection(tx, models.AuditLogWebAuthnAuthenticationInitSucceeded, dto.UserId, nil, nil)
+		auditErr := h.AuditLog.CreateWithConnection(tx, succeededType, dto.UserId, nil, nil)
 		if auditErr != nil {
 			ctx.Logger().Error(auditErr)
 			return fmt.Errorf(auditlog.CreationFailureFormat, auditErr)
@@ -105,19 +122,29 @@ func (lh *loginHandler) Finish(ctx echo.Context) error {
 			Ctx:                 ctx,
 			Tenant:              *h.Tenant,
 			WebauthnClient:      *h.WebauthnClient,
+			AuditLog:            h.AuditLog,
+			Tx:                  tx,
 			UserPersister:       userPersister,
 			SessionPersister:    sessionPersister,
 			CredentialPersister: credentialPersister,
 			Generator:           h.Generator,
+
+			CredentialHistoryPersister: lh.persister.GetWebauthnCredentialHistoryPersister(tx),
 		})
 
 		token, userId, err := service.Finalize(parsedRequest)
-		err = lh.handleError(h.AuditLog, models.AuditLogWebAuthnAuthenticationFinalFailed, tx, ctx, &userId, nil, err)
+
+		failedType, succeededType := models.AuditLogWebAuthnAuthenticationFinalFailed, models.AuditLogWebAuthnAuthenticationFinalSucceeded
+		if service.UsedConditionalMediation() {
+			failedType, succeededType = models.AuditLogWebAuthnConditionalAuthenticationFinalFailed, models.AuditLogWebAuthnConditionalAuthenticationFinalSucceeded
+		}
+
+		err = lh.handleError(h.AuditLog, failedType, tx, ctx, &userId, nil, err)
 		if err != nil {
 			return err
 		}
 
-		auditErr := h.AuditLog.CreateWithConnection(tx, models.AuditLogWebAuthnAuthenticationFinalSucceeded, &userId, nil, nil)
+		auditErr := h.AuditLog.CreateWithConnection(tx, succeededType, &userId, nil, nil)
 		if auditErr != nil {
 			ctx.Logger().Error(auditErr)
 			return fmt.Errorf(auditlog.CreationFailureFormat, auditErr)
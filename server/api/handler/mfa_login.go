@@ -48,6 +48,8 @@ func (lh *mfaLoginHandler) Init(ctx echo.Context) error {
 			Ctx:                 ctx,
 			Tenant:              *h.Tenant,
 			WebauthnClient:      *h.WebauthnClient,
+			AuditLog:            h.AuditLog,
+			Tx:                  tx,
 			UserId:              dto.UserId,
 			UserPersister:       userPersister,
 			SessionPersister:    sessionPersister,
@@ -93,11 +95,15 @@ func (lh *mfaLoginHandler) Finish(ctx echo.Context) error {
 			Ctx:                 ctx,
 			Tenant:              *h.Tenant,
 			WebauthnClient:      *h.WebauthnClient,
+			AuditLog:            h.AuditLog,
+			Tx:                  tx,
 			UserPersister:       userPersister,
 			SessionPersister:    sessionPersister,
 			CredentialPersister: credentialPersister,
 			Generator:           h.Generator,
 			UseMFA:              true,
+
+			CredentialHistoryPersister: lh.persister.GetWebauthnCredentialHistoryPersister(tx),
 		})
 
 		token, userId, err := service.Finalize(parsedRequest)
@@ -0,0 +1,292 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/gobuffalo/pop/v6"
+	"github.com/labstack/echo/v4"
+	"github.com/teamhanko/passkey-server/api/dto/request"
+	"github.com/teamhanko/passkey-server/api/dto/response"
+	"github.com/teamhanko/passkey-server/api/helper"
+	"github.com/teamhanko/passkey-server/api/services"
+	auditlog "github.com/teamhanko/passkey-server/audit_log"
+	"github.com/teamhanko/passkey-server/persistence"
+	"github.com/teamhanko/passkey-server/persistence/models"
+)
+
+type OidcHandler interface {
+	Authorize(ctx echo.Context) error
+	AuthorizeInit(ctx echo.Context) error
+	AuthorizeFinish(ctx echo.Context) error
+	Token(ctx echo.Context) error
+}
+
+type oidcHandler struct {
+	*webauthnHandler
+	publicUrl string
+}
+
+func NewOidcHandler(persister persistence.Persister, publicUrl string) OidcHandler {
+	webauthnHandler := newWebAuthnHandler(persister, false)
+
+	return &oidcHandler{
+		webauthnHandler: webauthnHandler,
+		publicUrl:       publicUrl,
+	}
+}
+
+// Authorize validates the authorization request and renders the page which performs the passkey login
+func (oh *oidcHandler) Authorize(ctx echo.Context) error {
+	dto, err := BindAndValidateRequest[request.OidcAuthorizationRequestDto](ctx)
+	if err != nil {
+		return err
+	}
+
+	h, err := helper.GetHandlerContext(ctx)
+	if err != nil {
+		ctx.Logger().Error(err)
+		return err
+	}
+
+	issuer, err := oh.getIssuer(ctx, h.Tenant)
+	if err != nil {
+		return err
+	}
+
+	service := services.NewOidcService(ctx, *h.Tenant, issuer, h.Generator, oh.persister.GetOidcAuthorizationCodePersister(nil))
+	err = service.ValidateAuthorizationRequest(*dto)
+
+	var oidcError *services.OidcError
+	if errors.As(err, &oidcError) {
+		params := url.Values{}
+		params.Set("error", oidcError.Code)
+		params.Set("error_description", oidcError.Description)
+		if dto.State != nil {
+			params.Set("state", *dto.State)
+		}
+
+		redirectTo, err := services.BuildOidcRedirectUri(dto.RedirectUri, params)
+		if err != nil {
+			ctx.Logger().Error(err)
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid redirect_uri").SetInternal(err)
+		}
+
+		return ctx.Redirect(http.StatusFound, redirectTo)
+	} else if err != nil {
+		return err
+	}
+
+	query := ctx.Request().URL.RawQuery
+	return ctx.Render(http.StatusOK, "oidc_authorize", map[string]string{
+		"initializeUrl": fmt.Sprintf("%s%s?%s", ctx.Request().URL.Path, "/initialize", query),
+		"finalizeUrl":   fmt.Sprintf("%s%s?%s", ctx.Request().URL.Path, "/finalize", query),
+	})
+}
+
+// AuthorizeInit creates the options for a discoverable login on the authorization page
+func (oh *oidcHandler) AuthorizeInit(ctx echo.Context) error {
+	h, err := helper.GetHandlerContext(ctx)
+	if err != nil {
+		ctx.Logger().Error(err)
+		return err
+	}
+
+	_, err = oh.bindAuthorizationRequest(ctx, h)
+	if err != nil {
+		return err
+	}
+
+	return oh.persister.Transaction(func(tx *pop.Connection) error {
+		service := services.NewLoginService(services.WebauthnServiceCreateParams{
+			Ctx:                 ctx,
+			Tenant:              *h.Tenant,
+			WebauthnClient:      *h.WebauthnClient,
+			AuditLog:            h.AuditLog,
+			Tx:                  tx,
+			UserPersister:       oh.persister.GetWebauthnUserPersister(tx),
+			SessionPersister:    oh.persister.GetWebauthnSessionDataPersister(tx),
+			CredentialPersister: oh.persister.GetWebauthnCredentialPersister(tx),
+		})
+
+		credentialAssertion, err := service.Initialize()
+		err = oh.handleError(h.AuditLog, models.AuditLogWebAuthnAuthenticationInitFailed, tx, ctx, nil, nil, err)
+		if err != nil {
+			return err
+		}
+
+		auditErr := h.AuditLog.CreateWithConnection(tx, models.AuditLogWebAuthnAuthenticationInitSucceeded, nil, nil, nil)
+		if auditErr != nil {
+			ctx.Logger().Error(auditErr)
+			return fmt.Errorf(auditlog.CreationFailureFormat, auditErr)
+		}
+
+		return ctx.JSON(http.StatusOK, credentialAssertion)
+	})
+}
+
+// AuthorizeFinish validates the assertion and returns the redirect uri containing the authorization code
+func (oh *oidcHandler) AuthorizeFinish(ctx echo.Context) error {
+	h, err := helper.GetHandlerContext(ctx)
+	if err != nil {
+		ctx.Logger().Error(err)
+		return err
+	}
+
+	issuer, err := oh.getIssuer(ctx, h.Tenant)
+	if err != nil {
+		return err
+	}
+
+	dto, err := oh.bindAuthorizationRequest(ctx, h)
+	if err != nil {
+		return err
+	}
+
+	parsedRequest, err := protocol.ParseCredentialRequestResponse(ctx.Request())
+	if err != nil {
+		ctx.Logger().Error(err)
+		return echo.NewHTTPError(http.StatusBadRequest, "unable to finish login").SetInternal(err)
+	}
+
+	return oh.persister.Transaction(func(tx *pop.Connection) error {
+		loginService := services.NewLoginService(services.WebauthnServiceCreateParams{
+			Ctx:                 ctx,
+			Tenant:              *h.Tenant,
+			WebauthnClient:      *h.WebauthnClient,
+			AuditLog:            h.AuditLog,
+			Tx:                  tx,
+			UserPersister:       oh.persister.GetWebauthnUserPersister(tx),
+			SessionPersister:    oh.persister.GetWebauthnSessionDataPersister(tx),
+			CredentialPersister: oh.persister.GetWebauthnCredentialPersister(tx),
+
+			CredentialHistoryPersister: oh.persister.GetWebauthnCredentialHistoryPersister(tx),
+		})
+
+		credential, userId, err := loginService.Authenticate(parsedRequest)
+		if err == nil {
+			oidcService := services.NewOidcService(ctx, *h.Tenant, issuer, h.Generator, oh.persister.GetOidcAuthorizationCodePersister(tx))
+
+			var redirectTo string
+			redirectTo, err = oidcService.CreateAuthorizationCode(*dto, credential)
+			if err == nil {
+				auditErr := h.AuditLog.CreateWithConnection(tx, models.AuditLogWebAuthnAuthenticationFinalSucceeded, &userId, nil, nil)
+				if auditErr != nil {
+					ctx.Logger().Error(auditErr)
+					return fmt.Errorf(auditlog.CreationFailureFormat, auditErr)
+				}
+
+				return ctx.JSON(http.StatusOK, &response.OidcAuthorizationDto{RedirectTo: redirectTo})
+			}
+		}
+
+		return oh.handleError(h.AuditLog, models.AuditLogWebAuthnAuthenticationFinalFailed, tx, ctx, &userId, nil, err)
+	})
+}
+
+// Token exchanges an authorization code for an id_token and an access token
+func (oh *oidcHandler) Token(ctx echo.Context) error {
+	ctx.Response().Header().Set("Cache-Control", "no-store")
+	ctx.Response().Header().Set("Pragma", "no-cache")
+
+	dto, err := BindAndValidateRequest[request.OidcTokenRequestDto](ctx)
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, &response.OidcErrorDto{
+			Error:            services.OidcErrorInvalidRequest,
+			ErrorDescription: "unable to parse token request",
+		})
+	}
+
+	h, err := helper.GetHandlerContext(ctx)
+	if err != nil {
+		ctx.Logger().Error(err)
+		return err
+	}
+
+	issuer, err := oh.getIssuer(ctx, h.Tenant)
+	if err != nil {
+		return err
+	}
+
+	return oh.persister.Transaction(func(tx *pop.Connection) error {
+		service := services.NewOidcService(ctx, *h.Tenant, issuer, h.Generator, oh.persister.GetOidcAuthorizationCodePersister(tx))
+
+		tokens, userId, err := service.ExchangeCode(*dto)
+
+		var oidcError *services.OidcError
+		if errors.As(err, &oidcError) {
+			auditErr := h.AuditLog.CreateWithConnection(tx, models.AuditLogOidcTokenExchangeFailed, userId, nil, err)
+			if auditErr != nil {
+				ctx.Logger().Error(auditErr)
+				return fmt.Errorf(auditlog.CreationFailureFormat, auditErr)
+			}
+
+			// the transaction is committed so the authorization code can not be used again
+			return ctx.JSON(http.StatusBadRequest, &response.OidcErrorDto{
+				Error:            oidcError.Code,
+				ErrorDescription: oidcError.Description,
+			})
+		}
+
+		err = oh.handleError(h.AuditLog, models.AuditLogOidcTokenExchangeFailed, tx, ctx, userId, nil, err)
+		if err != nil {
+			return err
+		}
+
+		auditErr := h.AuditLog.CreateWithConnection(tx, models.AuditLogOidcTokenExchangeSucceeded, userId, nil, nil)
+		if auditErr != nil {
+			ctx.Logger().Error(auditErr)
+			return fmt.Errorf(auditlog.CreationFailureFormat, auditErr)
+		}
+
+		return ctx.JSON(http.StatusOK, tokens)
+	})
+}
+
+// bindAuthorizationRequest reads the authorization request from the query, as the body contains the webauthn data
+func (oh *oidcHandler) bindAuthorizationRequest(ctx echo.Context, h *helper.WebauthnContext) (*request.OidcAuthorizationRequestDto, error) {
+	var dto request.OidcAuthorizationRequestDto
+	err := (&echo.DefaultBinder{}).BindQueryParams(ctx, &dto)
+	if err != nil {
+		ctx.Logger().Error(err)
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "unable to process request").SetInternal(err)
+	}
+
+	err = ctx.Validate(&dto)
+	if err != nil {
+		ctx.Logger().Error(err)
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "unable to validate request").SetInternal(err)
+	}
+
+	issuer, err := oh.getIssuer(ctx, h.Tenant)
+	if err != nil {
+		return nil, err
+	}
+
+	service := services.NewOidcService(ctx, *h.Tenant, issuer, h.Generator, oh.persister.GetOidcAuthorizationCodePersister(nil))
+	err = service.ValidateAuthorizationRequest(dto)
+
+	var oidcError *services.OidcError
+	if errors.As(err, &oidcError) {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, oidcError.Description).SetInternal(err)
+	} else if err != nil {
+		return nil, err
+	}
+
+	return &dto, nil
+}
+
+// getIssuer returns the issuer of the id tokens. OpenID Connect is not available when neither an issuer nor the public
+// url of the server is configured.
+func (oh *oidcHandler) getIssuer(ctx echo.Context, tenant *models.Tenant) (string, error) {
+	issuer, err := helper.GetOidcIssuer(oh.publicUrl, tenant)
+	if err != nil {
+		ctx.Logger().Error(err)
+		return "", echo.NewHTTPError(http.StatusInternalServerError, "OpenID Connect is not configured").SetInternal(err)
+	}
+
+	return issuer, nil
+}
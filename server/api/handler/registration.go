@@ -57,13 +57,15 @@ func (r *registrationHandler) Init(ctx echo.Context) error {
 			Ctx:                 ctx,
 			Tenant:              *h.Tenant,
 			WebauthnClient:      *h.WebauthnClient,
+			AuditLog:            h.AuditLog,
+			Tx:                  tx,
 			UserPersister:       userPersister,
 			SessionPersister:    sessionPersister,
 			CredentialPersister: credentialPersister,
 			UseMFA:              r.UseMFAClient,
 		})
 
-		credentialCreation, userId, err := service.Initialize(webauthnUser)
+		credentialCreation, userId, err := service.Initialize(webauthnUser, dto.ToRegistrationOptions())
 
 		if r.UseMFAClient {
 			err = r.handleError(h.AuditLog, models.AuditLogMfaRegistrationInitFailed, tx, ctx, &userId, nil, err)
@@ -84,7 +86,7 @@ func (r *registrationHandler) Init(ctx echo.Context) error {
 			return err
 		}
 
-		return ctx.JSON(http.StatusOK, credentialCreation)
+		return ctx.JSON(http.StatusOK, response.CredentialCreationDtoFromCreation(credentialCreation, dto.Hints))
 	})
 }
 
@@ -117,6 +119,8 @@ func (r *registrationHandler) Finish(ctx echo.Context) error {
 			Ctx:                   ctx,
 			Tenant:                *h.Tenant,
 			WebauthnClient:        *h.WebauthnClient,
+			AuditLog:              h.AuditLog,
+			Tx:                    tx,
 			UserPersister:         userPersister,
 			SessionPersister:      sessionPersister,
 			CredentialPersister:   credentialPersister,
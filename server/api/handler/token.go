@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gobuffalo/pop/v6"
+	"github.com/labstack/echo/v4"
+	"github.com/teamhanko/passkey-server/api/dto/request"
+	"github.com/teamhanko/passkey-server/api/helper"
+	"github.com/teamhanko/passkey-server/api/services"
+	auditlog "github.com/teamhanko/passkey-server/audit_log"
+	"github.com/teamhanko/passkey-server/persistence"
+	"github.com/teamhanko/passkey-server/persistence/models"
+)
+
+type TokenHandler interface {
+	Introspect(ctx echo.Context) error
+}
+
+type tokenHandler struct {
+	*webauthnHandler
+}
+
+func NewTokenHandler(persister persistence.Persister) TokenHandler {
+	webauthnHandler := newWebAuthnHandler(persister, false)
+
+	return &tokenHandler{
+		webauthnHandler,
+	}
+}
+
+func (th *tokenHandler) Introspect(ctx echo.Context) error {
+	dto, err := BindAndValidateRequest[request.IntrospectTokenDto](ctx)
+	if err != nil {
+		return err
+	}
+
+	h, err := helper.GetHandlerContext(ctx)
+	if err != nil {
+		ctx.Logger().Error(err)
+		return err
+	}
+
+	return th.persister.Transaction(func(tx *pop.Connection) error {
+		service := services.NewTokenService(ctx, *h.Tenant, h.Generator, th.persister.GetRedeemedTokenPersister(tx))
+
+		introspection, userId, err := service.Introspect(*dto)
+		if !dto.Redeem {
+			if err != nil {
+				return err
+			}
+
+			return ctx.JSON(http.StatusOK, introspection)
+		}
+
+		err = th.handleError(h.AuditLog, models.AuditLogTokenRedeemFailed, tx, ctx, userId, nil, err)
+		if err != nil {
+			return err
+		}
+
+		var auditErr error
+		if introspection.Active {
+			auditErr = h.AuditLog.CreateWithConnection(tx, models.AuditLogTokenRedeemSucceeded, userId, nil, nil)
+		} else {
+			auditErr = h.AuditLog.CreateWithConnection(tx, models.AuditLogTokenRedeemFailed, userId, nil, errors.New("token is not active"))
+		}
+
+		if auditErr != nil {
+			ctx.Logger().Error(auditErr)
+			return fmt.Errorf(auditlog.CreationFailureFormat, auditErr)
+		}
+
+		return ctx.JSON(http.StatusOK, introspection)
+	})
+}
@@ -62,6 +62,8 @@ func (t *transactionHandler) Init(ctx echo.Context) error {
 				Ctx:              ctx,
 				Tenant:           *h.Tenant,
 				WebauthnClient:   *h.WebauthnClient,
+				AuditLog:         h.AuditLog,
+				Tx:               tx,
 				UserPersister:    webauthnUserPersister,
 				SessionPersister: sessionDataPersister,
 			},
@@ -109,10 +111,14 @@ func (t *transactionHandler) Finish(ctx echo.Context) error {
 				Ctx:                 ctx,
 				Tenant:              *h.Tenant,
 				WebauthnClient:      *h.WebauthnClient,
+				AuditLog:            h.AuditLog,
+				Tx:                  tx,
 				UserPersister:       webauthnUserPersister,
 				SessionPersister:    sessionDataPersister,
 				CredentialPersister: credentialPersister,
 				Generator:           h.Generator,
+
+				CredentialHistoryPersister: t.persister.GetWebauthnCredentialHistoryPersister(tx),
 			},
 			TransactionPersister: transactionPersister,
 		})
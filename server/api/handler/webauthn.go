@@ -49,7 +49,7 @@ func (w *webauthnHandler) handleError(logger auditlog.Logger, logType models.Aud
 	return nil
 }
 
-func BindAndValidateRequest[I request.CredentialRequests | request.WebauthnRequests](ctx echo.Context) (*I, error) {
+func BindAndValidateRequest[I request.CredentialRequests | request.WebauthnRequests | request.TokenRequests | request.OidcRequests](ctx echo.Context) (*I, error) {
 	var requestDto I
 
 	if ctx.Request().ContentLength <= 0 {
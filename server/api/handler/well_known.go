@@ -1,16 +1,23 @@
 package handler
 
 import (
+	"github.com/gofrs/uuid"
 	"github.com/labstack/echo/v4"
+	"github.com/teamhanko/passkey-server/api/dto/response"
+	"github.com/teamhanko/passkey-server/api/helper"
 	hankoJwk "github.com/teamhanko/passkey-server/crypto/jwk"
 	"github.com/teamhanko/passkey-server/persistence/models"
 	"net/http"
 )
 
-type WellKnownHandler struct{}
+type WellKnownHandler struct {
+	publicUrl string
+}
 
-func NewWellKnownHandler() *WellKnownHandler {
-	return &WellKnownHandler{}
+func NewWellKnownHandler(publicUrl string) *WellKnownHandler {
+	return &WellKnownHandler{
+		publicUrl: publicUrl,
+	}
 }
 
 func (h *WellKnownHandler) GetPublicKeys(ctx echo.Context) error {
@@ -29,3 +36,49 @@ func (h *WellKnownHandler) GetPublicKeys(ctx echo.Context) error {
 	ctx.Response().Header().Add("Cache-Control", "max-age=600")
 	return ctx.JSON(http.StatusOK, keys)
 }
+
+// GetOpenIdConfiguration returns the OpenID Connect discovery document. It is only available for tenants with
+// configured OIDC clients.
+func (h *WellKnownHandler) GetOpenIdConfiguration(ctx echo.Context) error {
+	tenant := ctx.Get("tenant").(*models.Tenant)
+	if tenant == nil || len(tenant.Config.OidcClients) == 0 {
+		return echo.NewHTTPError(http.StatusNotFound, "OpenID Connect is not enabled for this tenant")
+	}
+
+	signingAlgorithm := models.DefaultJwtSigningAlgorithm
+	if jwtConfig := tenant.Config.JwtConfig; jwtConfig != nil && jwtConfig.ID != uuid.Nil {
+		signingAlgorithm = jwtConfig.SigningAlgorithm
+	}
+
+	issuer, err := helper.GetOidcIssuer(h.publicUrl, tenant)
+	if err != nil {
+		ctx.Logger().Error(err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "OpenID Connect is not configured").SetInternal(err)
+	}
+
+	tenantUrl, err := helper.GetTenantUrl(h.publicUrl, tenant)
+	if err != nil {
+		ctx.Logger().Error(err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "OpenID Connect is not configured").SetInternal(err)
+	}
+
+	ctx.Response().Header().Add("Cache-Control", "max-age=600")
+	return ctx.JSON(http.StatusOK, response.NewOidcDiscoveryDto(issuer, tenantUrl, signingAlgorithm))
+}
+
+// GetRelatedOrigins returns the related origins document of WebAuthn Level 3. It has to be served at
+// https://<RP ID>/.well-known/webauthn, so browsers accept the RP ID on the related origins.
+func (h *WellKnownHandler) GetRelatedOrigins(ctx echo.Context) error {
+	tenant := ctx.Get("tenant").(*models.Tenant)
+	if tenant == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "unable to find tenant")
+	}
+
+	relatedOrigins := tenant.Config.WebauthnConfig.RelyingParty.GetRelatedOrigins()
+	if len(relatedOrigins) == 0 {
+		return echo.NewHTTPError(http.StatusNotFound, "no related origins are configured for this tenant")
+	}
+
+	ctx.Response().Header().Add("Cache-Control", "max-age=600")
+	return ctx.JSON(http.StatusOK, response.RelatedOriginsDto{Origins: relatedOrigins})
+}
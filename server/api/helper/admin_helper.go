@@ -0,0 +1,41 @@
+package helper
+
+import (
+	"net/http"
+
+	"github.com/gofrs/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+const AdminPrincipalContextKey = "admin_principal"
+
+// AdminPrincipal is the authenticated caller of the admin API
+type AdminPrincipal struct {
+	// Subject is the name of the static token or the subject of the JWT
+	Subject    string
+	AllTenants bool
+	Tenants    []uuid.UUID
+}
+
+func (p *AdminPrincipal) CanAccessTenant(tenantId uuid.UUID) bool {
+	if p.AllTenants {
+		return true
+	}
+
+	for _, id := range p.Tenants {
+		if id == tenantId {
+			return true
+		}
+	}
+
+	return false
+}
+
+func GetAdminPrincipal(ctx echo.Context) (*AdminPrincipal, error) {
+	ctxPrincipal := ctx.Get(AdminPrincipalContextKey)
+	if ctxPrincipal == nil {
+		return nil, echo.NewHTTPError(http.StatusUnauthorized, "missing admin credentials")
+	}
+
+	return ctxPrincipal.(*AdminPrincipal), nil
+}
@@ -0,0 +1,32 @@
+package helper
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gofrs/uuid"
+	"github.com/teamhanko/passkey-server/persistence/models"
+)
+
+var ErrMissingPublicUrl = errors.New("the public_url of the server is required for OpenID Connect")
+
+// GetTenantUrl returns the public url of the tenant, under which its endpoints are served
+func GetTenantUrl(publicUrl string, tenant *models.Tenant) (string, error) {
+	if publicUrl == "" {
+		return "", ErrMissingPublicUrl
+	}
+
+	return fmt.Sprintf("%s/%s", strings.TrimSuffix(publicUrl, "/"), tenant.ID), nil
+}
+
+// GetOidcIssuer returns the configured issuer of the tenant. When no issuer is configured, the public url of the
+// tenant is used. The issuer is never derived from the request, as its host header is controlled by the client.
+func GetOidcIssuer(publicUrl string, tenant *models.Tenant) (string, error) {
+	jwtConfig := tenant.Config.JwtConfig
+	if jwtConfig != nil && jwtConfig.ID != uuid.Nil && jwtConfig.Issuer != nil && *jwtConfig.Issuer != "" {
+		return *jwtConfig.Issuer, nil
+	}
+
+	return GetTenantUrl(publicUrl, tenant)
+}
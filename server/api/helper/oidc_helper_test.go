@@ -0,0 +1,49 @@
+package helper
+
+import (
+	"testing"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/teamhanko/passkey-server/persistence/models"
+)
+
+var testTenantId = uuid.FromStringOrNil("0f3bf1c8-32b0-4a64-8f6a-3f0dbbd4a4c5")
+
+func TestGetOidcIssuerUsesConfiguredIssuer(t *testing.T) {
+	// given
+	issuer := "https://login.example.com"
+	tenant := &models.Tenant{ID: testTenantId, Config: models.Config{
+		JwtConfig: &models.JwtConfig{ID: uuid.Must(uuid.NewV4()), Issuer: &issuer},
+	}}
+
+	// when
+	result, err := GetOidcIssuer("https://passkeys.example.com", tenant)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, issuer, result)
+}
+
+func TestGetOidcIssuerFallsBackToTenantUrl(t *testing.T) {
+	// given
+	tenant := &models.Tenant{ID: testTenantId}
+
+	// when
+	result, err := GetOidcIssuer("https://passkeys.example.com/", tenant)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, "https://passkeys.example.com/0f3bf1c8-32b0-4a64-8f6a-3f0dbbd4a4c5", result)
+}
+
+func TestGetOidcIssuerRequiresIssuerOrPublicUrl(t *testing.T) {
+	// given
+	tenant := &models.Tenant{ID: testTenantId}
+
+	// when
+	_, err := GetOidcIssuer("", tenant)
+
+	// then
+	assert.ErrorIs(t, err, ErrMissingPublicUrl)
+}
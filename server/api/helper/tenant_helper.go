@@ -1,32 +1,60 @@
 package helper
 
 import (
+	"crypto/subtle"
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/labstack/echo/v4"
+	"github.com/teamhanko/passkey-server/crypto"
+	"github.com/teamhanko/passkey-server/persistence"
 	"github.com/teamhanko/passkey-server/persistence/models"
 )
 
-func CheckApiKey(keys []models.Secret, apiKey string) error {
+func CheckApiKey(keys []models.Secret, apiKey string, scope models.ApiKeyScope) (*models.Secret, error) {
+	hashedApiKey := []byte(crypto.HashSecret(strings.TrimSpace(apiKey)))
+
+	// compare against all keys to not leak the position of a matching key through timing
 	var foundKey *models.Secret
 	for _, key := range keys {
-		if strings.TrimSpace(apiKey) == key.Key {
-			foundKey = &key
-			break
+		if key.IsAPISecret && subtle.ConstantTimeCompare(hashedApiKey, []byte(key.Key)) == 1 {
+			k := key
+			foundKey = &k
 		}
 	}
 
 	if foundKey == nil {
-		return echo.NewHTTPError(http.StatusUnauthorized, "The api key is invalid").
+		return nil, echo.NewHTTPError(http.StatusUnauthorized, "The api key is invalid").
 			SetInternal(fmt.Errorf("api keys needs to be an apiKey Header and 32 byte long"))
 	}
 
-	if !foundKey.IsAPISecret {
-		return echo.NewHTTPError(http.StatusUnauthorized, "The api key is invalid").
-			SetInternal(fmt.Errorf("provided key is not an api key"))
+	if foundKey.IsExpired(time.Now().UTC()) {
+		return nil, echo.NewHTTPError(http.StatusUnauthorized, "The api key is invalid").
+			SetInternal(fmt.Errorf("api key '%s' is expired", foundKey.ID))
+	}
+
+	if !foundKey.Scopes.Contains(scope) {
+		return nil, echo.NewHTTPError(http.StatusForbidden, "The api key is not allowed to access this resource").
+			SetInternal(fmt.Errorf("api key '%s' is missing scope '%s'", foundKey.ID, scope))
 	}
 
-	return nil
+	return foundKey, nil
+}
+
+// apiKeyUsageInterval is the minimum time between two updates of the last usage of an api key
+const apiKeyUsageInterval = time.Minute
+
+// UpdateApiKeyUsage stores the current time as last usage of the api key. To keep writes off the hot path, the last
+// usage is only updated when the stored one is older than apiKeyUsageInterval.
+func UpdateApiKeyUsage(persister persistence.Persister, secret *models.Secret) error {
+	now := time.Now().UTC()
+	if secret.LastUsedAt != nil && now.Sub(*secret.LastUsedAt) < apiKeyUsageInterval {
+		return nil
+	}
+
+	secret.LastUsedAt = &now
+
+	return persister.GetSecretsPersister(nil).UpdateLastUsedAt(secret)
 }
@@ -0,0 +1,88 @@
+package helper
+
+import (
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/teamhanko/passkey-server/persistence/models"
+)
+
+type WebauthnClientParams struct {
+	RP                     models.RelyingParty
+	RegistrationTimeout    int
+	LoginTimeout           int
+	UserVerification       protocol.UserVerificationRequirement
+	Attachment             *protocol.AuthenticatorAttachment
+	AttestationPreference  protocol.ConveyancePreference
+	ResidentKeyRequirement protocol.ResidentKeyRequirement
+}
+
+// NewPasskeyClientParams returns the params of the passkey client with the registration settings. Login and
+// transaction ceremonies override the user verification and timeout with their own settings when they are initialized.
+func NewPasskeyClientParams(cfg models.WebauthnConfig, ceremonyConfigs models.WebauthnCeremonyConfigs) WebauthnClientParams {
+	registrationSettings := ceremonyConfigs.GetSettings(models.WebauthnCeremonyRegistration, cfg)
+	loginSettings := ceremonyConfigs.GetSettings(models.WebauthnCeremonyLogin, cfg)
+
+	return WebauthnClientParams{
+		RP:                     cfg.RelyingParty,
+		RegistrationTimeout:    registrationSettings.Timeout,
+		LoginTimeout:           loginSettings.Timeout,
+		UserVerification:       registrationSettings.UserVerification,
+		Attachment:             registrationSettings.Attachment,
+		AttestationPreference:  cfg.AttestationPreference,
+		ResidentKeyRequirement: cfg.ResidentKeyRequirement,
+	}
+}
+
+// NewMFAClientParams returns the params of the MFA client
+func NewMFAClientParams(cfg models.MfaConfig, rp models.RelyingParty) WebauthnClientParams {
+	return WebauthnClientParams{
+		RP:                     rp,
+		RegistrationTimeout:    cfg.Timeout,
+		LoginTimeout:           cfg.Timeout,
+		UserVerification:       cfg.UserVerification,
+		Attachment:             &cfg.Attachment,
+		AttestationPreference:  cfg.AttestationPreference,
+		ResidentKeyRequirement: cfg.ResidentKeyRequirement,
+	}
+}
+
+// NewWebauthnClient creates the client used for the ceremonies of a tenant
+func NewWebauthnClient(params WebauthnClientParams) (*webauthn.WebAuthn, error) {
+	var origins []string
+	for _, origin := range params.RP.Origins {
+		origins = append(origins, origin.Origin)
+	}
+
+	requireResidentKey := params.ResidentKeyRequirement == protocol.ResidentKeyRequirementRequired
+
+	authenticatorSelection := protocol.AuthenticatorSelection{
+		RequireResidentKey: &requireResidentKey,
+		ResidentKey:        params.ResidentKeyRequirement,
+		UserVerification:   params.UserVerification,
+	}
+
+	if params.Attachment != nil {
+		authenticatorSelection.AuthenticatorAttachment = *params.Attachment
+	}
+
+	return webauthn.New(&webauthn.Config{
+		RPDisplayName:          params.RP.DisplayName,
+		RPID:                   params.RP.RPId,
+		RPOrigins:              origins,
+		AttestationPreference:  params.AttestationPreference,
+		AuthenticatorSelection: authenticatorSelection,
+		Debug:                  false,
+		Timeouts: webauthn.TimeoutsConfig{
+			Login: webauthn.TimeoutConfig{
+				Timeout: time.Duration(params.LoginTimeout) * time.Millisecond,
+				Enforce: true,
+			},
+			Registration: webauthn.TimeoutConfig{
+				Timeout: time.Duration(params.RegistrationTimeout) * time.Millisecond,
+				Enforce: true,
+			},
+		},
+	})
+}
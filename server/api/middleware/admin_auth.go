@@ -0,0 +1,149 @@
+package middleware
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/lestrrat-go/jwx/v2/jwk"
+	"github.com/lestrrat-go/jwx/v2/jws"
+	"github.com/lestrrat-go/jwx/v2/jwt"
+	"github.com/teamhanko/passkey-server/api/helper"
+	"github.com/teamhanko/passkey-server/config"
+)
+
+const jwksMinRefreshInterval = 5 * time.Minute
+
+// AdminAuthMiddleware authenticates requests to the admin API either by a static token or by a JWT which is signed
+// by a key of the configured JWKS. Requests are rejected when no authentication method is configured.
+func AdminAuthMiddleware(cfg config.Admin) (echo.MiddlewareFunc, error) {
+	var keySet jwk.Set
+	if cfg.IsJwtEnabled() {
+		cache := jwk.NewCache(context.Background())
+		err := cache.Register(cfg.Jwt.JwksUrl, jwk.WithMinRefreshInterval(jwksMinRefreshInterval))
+		if err != nil {
+			return nil, fmt.Errorf("failed to register admin jwks url: %w", err)
+		}
+
+		keySet = jwk.NewCachedSet(cache, cfg.Jwt.JwksUrl)
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			token, found := strings.CutPrefix(ctx.Request().Header.Get(echo.HeaderAuthorization), "Bearer ")
+			token = strings.TrimSpace(token)
+			if !found || token == "" {
+				return echo.NewHTTPError(http.StatusUnauthorized, "missing admin credentials")
+			}
+
+			principal := checkAdminToken(cfg.Tokens, token)
+			if principal == nil && keySet != nil {
+				var err error
+				principal, err = checkAdminJwt(cfg.Jwt, keySet, token)
+				if err != nil {
+					ctx.Logger().Error(err)
+				}
+			}
+
+			if principal == nil {
+				return echo.NewHTTPError(http.StatusUnauthorized, "invalid admin credentials")
+			}
+
+			ctx.Set(helper.AdminPrincipalContextKey, principal)
+
+			return next(ctx)
+		}
+	}, nil
+}
+
+// AdminTenantScopeMiddleware rejects requests for tenants which are not accessible by the authenticated admin.
+func AdminTenantScopeMiddleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			principal, err := helper.GetAdminPrincipal(ctx)
+			if err != nil {
+				return err
+			}
+
+			tenantId, err := uuid.FromString(ctx.Param("tenant_id"))
+			if err != nil {
+				ctx.Logger().Error(err)
+				return echo.NewHTTPError(http.StatusBadRequest, "tenant_id must be a valid uuid4")
+			}
+
+			if !principal.CanAccessTenant(tenantId) {
+				return echo.NewHTTPError(http.StatusForbidden, "admin credentials are not valid for this tenant")
+			}
+
+			return next(ctx)
+		}
+	}
+}
+
+func checkAdminToken(tokens []config.AdminToken, token string) *helper.AdminPrincipal {
+	var principal *helper.AdminPrincipal
+
+	// compare against all tokens to not leak the position of a matching token through timing
+	for _, adminToken := range tokens {
+		if subtle.ConstantTimeCompare([]byte(adminToken.Token), []byte(token)) == 1 {
+			principal = newAdminPrincipal(adminToken.Name, adminToken.Tenants)
+		}
+	}
+
+	return principal
+}
+
+func checkAdminJwt(cfg config.AdminJwt, keySet jwk.Set, token string) (*helper.AdminPrincipal, error) {
+	parsedToken, err := jwt.ParseString(
+		token,
+		jwt.WithKeySet(keySet, jws.WithInferAlgorithmFromKey(true)),
+		jwt.WithValidate(true),
+		jwt.WithIssuer(cfg.Issuer),
+		jwt.WithAudience(cfg.Audience),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify admin jwt: %w", err)
+	}
+
+	var tenants []string
+	if claim, ok := parsedToken.Get(cfg.TenantsClaim); ok {
+		switch value := claim.(type) {
+		case string:
+			tenants = append(tenants, value)
+		case []interface{}:
+			for _, item := range value {
+				if tenant, ok := item.(string); ok {
+					tenants = append(tenants, tenant)
+				}
+			}
+		}
+	}
+
+	return newAdminPrincipal(parsedToken.Subject(), tenants), nil
+}
+
+func newAdminPrincipal(subject string, tenants []string) *helper.AdminPrincipal {
+	principal := &helper.AdminPrincipal{
+		Subject: subject,
+		Tenants: make([]uuid.UUID, 0),
+	}
+
+	for _, tenant := range tenants {
+		if tenant == config.AdminAllTenants {
+			principal.AllTenants = true
+			continue
+		}
+
+		tenantId, err := uuid.FromString(tenant)
+		if err == nil {
+			principal.Tenants = append(principal.Tenants, tenantId)
+		}
+	}
+
+	return principal
+}
@@ -3,11 +3,12 @@ package middleware
 import (
 	"github.com/labstack/echo/v4"
 	"github.com/teamhanko/passkey-server/api/helper"
+	"github.com/teamhanko/passkey-server/persistence"
 	"github.com/teamhanko/passkey-server/persistence/models"
 	"net/http"
 )
 
-func ApiKeyMiddleware() echo.MiddlewareFunc {
+func ApiKeyMiddleware(persister persistence.Persister, scope models.ApiKeyScope) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(ctx echo.Context) error {
 			apiKey := ctx.Request().Header.Get("apiKey")
@@ -18,11 +19,17 @@ func ApiKeyMiddleware() echo.MiddlewareFunc {
 				return echo.NewHTTPError(http.StatusNotFound, "tenant not found")
 			}
 
-			err := helper.CheckApiKey(tenant.Config.Secrets, apiKey)
+			secret, err := helper.CheckApiKey(tenant.Config.Secrets, apiKey, scope)
 			if err != nil {
 				return err
 			}
 
+			err = helper.UpdateApiKeyUsage(persister, secret)
+			if err != nil {
+				ctx.Logger().Error(err)
+				return err
+			}
+
 			return next(ctx)
 		}
 	}
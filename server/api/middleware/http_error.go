@@ -63,6 +63,8 @@ func NewHTTPErrorHandler(config HTTPErrorHandlerConfig) func(err error, c echo.C
 		// Send response
 		if c.Request().Method == http.MethodHead { // Issue https://github.com/labstack/echo/issues/608
 			err = c.NoContent(*httpError.Status)
+		} else if body := getErrorBody(err); body != nil {
+			err = c.JSON(*httpError.Status, body)
 		} else {
 			err = c.JSON(*httpError.Status, httpError)
 		}
@@ -71,3 +73,19 @@ func NewHTTPErrorHandler(config HTTPErrorHandlerConfig) func(err error, c echo.C
 		}
 	}
 }
+
+// getErrorBody returns the message of an echo.HTTPError if it is a structured response body, e.g. the findings of a
+// config validation. Like in the default error handler of echo, such messages are sent as they are.
+func getErrorBody(err error) interface{} {
+	var e *echo.HTTPError
+	if !errors.As(err, &e) {
+		return nil
+	}
+
+	switch e.Message.(type) {
+	case nil, string, error:
+		return nil
+	default:
+		return e.Message
+	}
+}
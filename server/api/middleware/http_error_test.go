@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/teamhanko/passkey-server/api/dto/admin/response"
+)
+
+func handleTestError(err error) *httptest.ResponseRecorder {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPut, "/config", nil)
+	rec := httptest.NewRecorder()
+
+	NewHTTPErrorHandler(HTTPErrorHandlerConfig{Logger: e.Logger})(err, e.NewContext(req, rec))
+
+	return rec
+}
+
+func TestHTTPErrorHandlerSendsStructuredBody(t *testing.T) {
+	// given
+	findings := response.ConfigFindings{
+		{Severity: response.ConfigFindingSeverityError, Field: "webauthn.relying_party.id", Message: "rp id is invalid"},
+	}
+	err := echo.NewHTTPError(http.StatusBadRequest, response.ToValidateConfigResponse(findings)).SetInternal(errors.New(findings.Errors()))
+
+	// when
+	rec := handleTestError(err)
+
+	// then
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.JSONEq(t, `{"valid":false,"findings":[{"severity":"error","field":"webauthn.relying_party.id","message":"rp id is invalid"}]}`, rec.Body.String())
+}
+
+func TestHTTPErrorHandlerSendsErrorForMessages(t *testing.T) {
+	// given
+	errs := []error{
+		echo.NewHTTPError(http.StatusNotFound, "tenant not found"),
+		echo.NewHTTPError(http.StatusNotFound, errors.New("tenant not found")),
+	}
+
+	for _, err := range errs {
+		// when
+		rec := handleTestError(err)
+
+		// then
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.JSONEq(t, `{"title":"tenant not found","details":"","status":404}`, rec.Body.String())
+	}
+}
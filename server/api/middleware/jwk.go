@@ -1,7 +1,10 @@
 package middleware
 
 import (
+	"github.com/gobuffalo/pop/v6"
+	"github.com/gofrs/uuid"
 	"github.com/labstack/echo/v4"
+	"github.com/lestrrat-go/jwx/v2/jwa"
 	hankoJwk "github.com/teamhanko/passkey-server/crypto/jwk"
 	"github.com/teamhanko/passkey-server/crypto/jwt"
 	"github.com/teamhanko/passkey-server/persistence"
@@ -37,15 +40,30 @@ func JWKMiddleware(persister persistence.Persister) echo.MiddlewareFunc {
 	}
 }
 
+// instantiateJwtGenerator reads the keys of the tenant. They are rotated by the key rotator, which also stores the
+// default jwt config of tenants without one. Only the first key is generated here, as tenants created after the last
+// run of the rotator could not sign tokens otherwise.
 func instantiateJwtGenerator(ctx echo.Context, keys []string, tenant models.Tenant, persister persistence.Persister) error {
-	jwkManager, err := hankoJwk.NewDefaultManager(keys, tenant.ID, persister.GetJwkPersister(nil))
+	jwtConfig := tenant.Config.JwtConfig
+	if jwtConfig == nil || jwtConfig.ID == uuid.Nil {
+		jwtConfig = models.NewDefaultJwtConfig(tenant.Config.ID)
+	}
+
+	err := ensureSigningKey(keys, tenant.ID, jwtConfig.SigningAlgorithm, persister)
 	if err != nil {
 		ctx.Logger().Error(err)
 		return err
 	}
+
+	jwkManager, err := hankoJwk.NewManager(keys, jwtConfig.SigningAlgorithm, persister.GetJwkPersister(nil))
+	if err != nil {
+		ctx.Logger().Error(err)
+		return err
+	}
+
 	ctx.Set("jwk_manager", jwkManager)
 
-	generator, err := jwt.NewGenerator(&tenant.Config.WebauthnConfig, jwkManager, tenant.ID)
+	generator, err := jwt.NewGenerator(&tenant.Config.WebauthnConfig, jwtConfig, jwkManager, tenant.ID)
 	if err != nil {
 		return err
 	}
@@ -54,3 +72,34 @@ func instantiateJwtGenerator(ctx echo.Context, keys []string, tenant models.Tena
 
 	return nil
 }
+
+// ensureSigningKey generates an active key if the tenant has none. The tenant is locked like in the key rotator, so
+// concurrent requests do not generate several keys.
+func ensureSigningKey(keys []string, tenantId uuid.UUID, algorithm jwa.SignatureAlgorithm, persister persistence.Persister) error {
+	hasKey, err := hankoJwk.HasSigningKey(persister.GetJwkPersister(nil), tenantId)
+	if err != nil || hasKey {
+		return err
+	}
+
+	return persister.Transaction(func(tx *pop.Connection) error {
+		err := persister.GetTenantPersister(tx).Lock(tenantId)
+		if err != nil {
+			return err
+		}
+
+		jwkPersister := persister.GetJwkPersister(tx)
+
+		hasKey, err := hankoJwk.HasSigningKey(jwkPersister, tenantId)
+		if err != nil || hasKey {
+			return err
+		}
+
+		manager, err := hankoJwk.NewManager(keys, algorithm, jwkPersister)
+		if err != nil {
+			return err
+		}
+
+		_, err = manager.GenerateKey(tenantId)
+		return err
+	})
+}
@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"testing"
+
+	"github.com/gobuffalo/pop/v6"
+	"github.com/gofrs/uuid"
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/stretchr/testify/assert"
+	"github.com/teamhanko/passkey-server/persistence"
+	"github.com/teamhanko/passkey-server/persistence/models"
+	"github.com/teamhanko/passkey-server/persistence/persisters"
+)
+
+type testJwkMiddlewarePersister struct {
+	persistence.Persister
+	jwks    *testJwkPersister
+	tenants *testLockingTenantPersister
+}
+
+func (p *testJwkMiddlewarePersister) Transaction(fn func(tx *pop.Connection) error) error {
+	return fn(nil)
+}
+
+func (p *testJwkMiddlewarePersister) GetJwkPersister(_ *pop.Connection) persisters.JwkPersister {
+	return p.jwks
+}
+
+func (p *testJwkMiddlewarePersister) GetTenantPersister(_ *pop.Connection) persisters.TenantPersister {
+	return p.tenants
+}
+
+type testLockingTenantPersister struct {
+	persisters.TenantPersister
+	locked []uuid.UUID
+}
+
+func (p *testLockingTenantPersister) Lock(id uuid.UUID) error {
+	p.locked = append(p.locked, id)
+	return nil
+}
+
+type testJwkPersister struct {
+	persisters.JwkPersister
+	keys []models.Jwk
+}
+
+func (p *testJwkPersister) GetAllForTenant(_ uuid.UUID) ([]models.Jwk, error) {
+	return p.keys, nil
+}
+
+func (p *testJwkPersister) Create(jwk *models.Jwk) error {
+	jwk.ID = len(p.keys) + 1
+	p.keys = append(p.keys, *jwk)
+	return nil
+}
+
+func newTestJwkMiddlewarePersister(keys ...models.Jwk) *testJwkMiddlewarePersister {
+	return &testJwkMiddlewarePersister{
+		jwks:    &testJwkPersister{keys: keys},
+		tenants: &testLockingTenantPersister{},
+	}
+}
+
+func TestEnsureSigningKeyGeneratesKeyForTenantWithoutKeys(t *testing.T) {
+	// given
+	persister := newTestJwkMiddlewarePersister()
+	tenantId, _ := uuid.NewV4()
+
+	// when
+	err := ensureSigningKey([]string{"a-secret-which-is-long-enough"}, tenantId, jwa.ES256, persister)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{tenantId}, persister.tenants.locked)
+	assert.Len(t, persister.jwks.keys, 1)
+	assert.Equal(t, models.JwkStateActive, persister.jwks.keys[0].State)
+	assert.Equal(t, jwa.ES256, persister.jwks.keys[0].Algorithm)
+}
+
+func TestEnsureSigningKeyKeepsExistingKey(t *testing.T) {
+	// given
+	persister := newTestJwkMiddlewarePersister(models.Jwk{ID: 1, State: models.JwkStateActive})
+	tenantId, _ := uuid.NewV4()
+
+	// when
+	err := ensureSigningKey([]string{"a-secret-which-is-long-enough"}, tenantId, jwa.ES256, persister)
+
+	// then
+	assert.NoError(t, err)
+	assert.Empty(t, persister.tenants.locked)
+	assert.Len(t, persister.jwks.keys, 1)
+}
@@ -3,24 +3,15 @@ package middleware
 import (
 	"fmt"
 	"github.com/go-webauthn/webauthn/protocol"
-	"github.com/go-webauthn/webauthn/webauthn"
 	"github.com/gofrs/uuid"
 	"github.com/labstack/echo/v4"
+	"github.com/teamhanko/passkey-server/api/helper"
 	"github.com/teamhanko/passkey-server/persistence"
 	"github.com/teamhanko/passkey-server/persistence/models"
 	"net/http"
 	"time"
 )
 
-type clientParams struct {
-	RP                     models.RelyingParty
-	Timeout                int
-	UserVerification       protocol.UserVerificationRequirement
-	Attachment             *protocol.AuthenticatorAttachment
-	AttestationPreference  protocol.ConveyancePreference
-	ResidentKeyRequirement protocol.ResidentKeyRequirement
-}
-
 func WebauthnMiddleware(persister persistence.Persister) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(ctx echo.Context) error {
@@ -44,7 +35,7 @@ func WebauthnMiddleware(persister persistence.Persister) echo.MiddlewareFunc {
 }
 
 func setWebauthnClientCtx(ctx echo.Context, cfg models.Config, persister persistence.Persister) error {
-	err := createPasskeyClient(ctx, cfg.WebauthnConfig)
+	err := createPasskeyClient(ctx, cfg.WebauthnConfig, cfg.CeremonyConfigs)
 	if err != nil {
 		ctx.Logger().Error(err)
 		return err
@@ -67,43 +58,8 @@ func setWebauthnClientCtx(ctx echo.Context, cfg models.Config, persister persist
 	return nil
 }
 
-func createClient(ctx echo.Context, ctxKey string, params clientParams) error {
-	var origins []string
-	for _, origin := range params.RP.Origins {
-		origins = append(origins, origin.Origin)
-	}
-
-	requireResidentKey := params.ResidentKeyRequirement == protocol.ResidentKeyRequirementRequired
-
-	authenticatorSelection := protocol.AuthenticatorSelection{
-		RequireResidentKey: &requireResidentKey,
-		ResidentKey:        params.ResidentKeyRequirement,
-		UserVerification:   params.UserVerification,
-	}
-
-	if params.Attachment != nil {
-		authenticatorSelection.AuthenticatorAttachment = *params.Attachment
-	}
-
-	webauthnClient, err := webauthn.New(&webauthn.Config{
-		RPDisplayName:          params.RP.DisplayName,
-		RPID:                   params.RP.RPId,
-		RPOrigins:              origins,
-		AttestationPreference:  params.AttestationPreference,
-		AuthenticatorSelection: authenticatorSelection,
-		Debug:                  false,
-		Timeouts: webauthn.TimeoutsConfig{
-			Login: webauthn.TimeoutConfig{
-				Timeout: time.Duration(params.Timeout) * time.Millisecond,
-				Enforce: true,
-			},
-			Registration: webauthn.TimeoutConfig{
-				Timeout: time.Duration(params.Timeout) * time.Millisecond,
-				Enforce: true,
-			},
-		},
-	})
-
+func createClient(ctx echo.Context, ctxKey string, params helper.WebauthnClientParams) error {
+	webauthnClient, err := helper.NewWebauthnClient(params)
 	if err != nil {
 		return err
 	}
@@ -113,30 +69,12 @@ func createClient(ctx echo.Context, ctxKey string, params clientParams) error {
 	return nil
 }
 
-func createPasskeyClient(ctx echo.Context, cfg models.WebauthnConfig) error {
-	params := clientParams{
-		RP:                     cfg.RelyingParty,
-		Timeout:                cfg.Timeout,
-		UserVerification:       cfg.UserVerification,
-		Attachment:             cfg.Attachment,
-		AttestationPreference:  cfg.AttestationPreference,
-		ResidentKeyRequirement: cfg.ResidentKeyRequirement,
-	}
-
-	return createClient(ctx, "webauthn_client", params)
+func createPasskeyClient(ctx echo.Context, cfg models.WebauthnConfig, ceremonyConfigs models.WebauthnCeremonyConfigs) error {
+	return createClient(ctx, "webauthn_client", helper.NewPasskeyClientParams(cfg, ceremonyConfigs))
 }
 
 func createMFAClient(ctx echo.Context, cfg models.MfaConfig, rp models.RelyingParty) error {
-	params := clientParams{
-		RP:                     rp,
-		Timeout:                cfg.Timeout,
-		UserVerification:       cfg.UserVerification,
-		Attachment:             &cfg.Attachment,
-		AttestationPreference:  cfg.AttestationPreference,
-		ResidentKeyRequirement: cfg.ResidentKeyRequirement,
-	}
-
-	return createClient(ctx, "mfa_client", params)
+	return createClient(ctx, "mfa_client", helper.NewMFAClientParams(cfg, rp))
 }
 
 func createDefaultMfaConfig(persister persistence.Persister, passkeyConfig models.WebauthnConfig) (*models.MfaConfig, error) {
@@ -9,10 +9,11 @@ import (
 	"github.com/teamhanko/passkey-server/api/template"
 	"github.com/teamhanko/passkey-server/api/validators"
 	"github.com/teamhanko/passkey-server/config"
+	"github.com/teamhanko/passkey-server/mapper"
 	"github.com/teamhanko/passkey-server/persistence"
 )
 
-func NewAdminRouter(cfg *config.Config, persister persistence.Persister, prometheus echo.MiddlewareFunc) *echo.Echo {
+func NewAdminRouter(cfg *config.Config, persister persistence.Persister, prometheus echo.MiddlewareFunc, authenticatorMetadata mapper.AuthenticatorMetadata) (*echo.Echo, error) {
 	main := echo.New()
 	main.Renderer = template.NewTemplateRenderer()
 	main.HideBanner = true
@@ -31,8 +32,12 @@ func NewAdminRouter(cfg *config.Config, persister persistence.Persister, prometh
 		rootGroup.Use(passkeyMiddleware.LoggerMiddleware())
 	}
 
-	// add CORS for all
-	main.Use(middleware.CORS())
+	// add CORS only for configured origins
+	if len(cfg.Admin.Cors.AllowOrigins) > 0 {
+		main.Use(middleware.CORSWithConfig(middleware.CORSConfig{
+			AllowOrigins: cfg.Admin.Cors.AllowOrigins,
+		}))
+	}
 
 	// Validator
 	main.Validator = validators.NewCustomValidator()
@@ -52,16 +57,32 @@ func NewAdminRouter(cfg *config.Config, persister persistence.Persister, prometh
 	health.GET("/alive", healthHandler.Alive)
 	health.GET("/ready", healthHandler.Ready)
 
+	if !cfg.Admin.IsEnabled() {
+		main.Logger.Warn("no admin authentication configured, all requests to the tenant endpoints will be rejected")
+	}
+
+	adminAuth, err := passkeyMiddleware.AdminAuthMiddleware(cfg.Admin)
+	if err != nil {
+		return nil, err
+	}
+
 	tenantHandler := admin.NewTenantHandler(persister)
-	tenantsGroup := rootGroup.Group("/tenants")
+	tenantsGroup := rootGroup.Group("/tenants", adminAuth)
 	tenantsGroup.GET("", tenantHandler.List)
 	tenantsGroup.POST("", tenantHandler.Create)
 
-	singleGroup := tenantsGroup.Group("/:tenant_id", passkeyMiddleware.TenantMiddleware(persister))
+	singleGroup := tenantsGroup.Group("/:tenant_id", passkeyMiddleware.AdminTenantScopeMiddleware(), passkeyMiddleware.TenantMiddleware(persister))
 	singleGroup.GET("", tenantHandler.Get)
 	singleGroup.PUT("", tenantHandler.Update)
 	singleGroup.DELETE("", tenantHandler.Remove)
+	singleGroup.GET("/config", tenantHandler.GetConfig)
 	singleGroup.PUT("/config", tenantHandler.UpdateConfig)
+	singleGroup.PATCH("/config", tenantHandler.PatchConfig)
+	singleGroup.POST("/config/validate", tenantHandler.ValidateConfig)
+	singleGroup.GET("/config/versions", tenantHandler.ListConfigVersions)
+	singleGroup.GET("/config/versions/:version", tenantHandler.GetConfigVersion)
+	singleGroup.POST("/config/versions/:version/rollback", tenantHandler.RollbackConfig)
+	singleGroup.GET("/config/diff", tenantHandler.DiffConfigVersions)
 	singleGroup.GET("/audit_logs", tenantHandler.ListAuditLog)
 
 	secretHandler := admin.NewSecretsHandler(persister)
@@ -75,12 +96,26 @@ func NewAdminRouter(cfg *config.Config, persister persistence.Persister, prometh
 	jwkKeyGroup.POST("", secretHandler.CreateJWKKey)
 	jwkKeyGroup.DELETE("/:secret_id", secretHandler.RemoveJWKKey)
 
-	userHandler := admin.NewUserHandler(persister)
+	jwkHandler := admin.NewJwkHandler(persister)
+	jwksGroup := singleGroup.Group("/jwks")
+	jwksGroup.GET("", jwkHandler.List)
+	jwksGroup.POST("/rotate", jwkHandler.Rotate)
+
+	webhookHandler := admin.NewWebhookHandler(persister)
+	webhookGroup := singleGroup.Group("/webhooks")
+	webhookGroup.GET("", webhookHandler.List)
+	webhookGroup.POST("", webhookHandler.Create)
+	webhookGroup.GET("/:webhook_id", webhookHandler.Get)
+	webhookGroup.DELETE("/:webhook_id", webhookHandler.Remove)
+	webhookGroup.GET("/:webhook_id/deliveries", webhookHandler.ListDeliveries)
+	webhookGroup.POST("/:webhook_id/deliveries/:delivery_id/redeliver", webhookHandler.Redeliver)
+
+	userHandler := admin.NewUserHandler(persister, authenticatorMetadata)
 	userGroup := singleGroup.Group("/users")
 	userGroup.GET("", userHandler.List)
 
 	userGroup.GET("/:user_id", userHandler.Get)
 	userGroup.DELETE("/:user_id", userHandler.Remove)
 
-	return main
+	return main, nil
 }
@@ -12,6 +12,7 @@ import (
 	"github.com/teamhanko/passkey-server/config"
 	"github.com/teamhanko/passkey-server/mapper"
 	"github.com/teamhanko/passkey-server/persistence"
+	"github.com/teamhanko/passkey-server/persistence/models"
 )
 
 const (
@@ -49,15 +50,18 @@ func NewMainRouter(cfg *config.Config, persister persistence.Persister, authenti
 
 	logMetrics(cfg.Log.LogHealthAndMetrics, main, tenantGroup)
 
-	RouteWellKnown(tenantGroup)
-	RouteCredentials(tenantGroup, persister)
+	RouteWellKnown(tenantGroup, cfg.PublicUrl)
+	RouteCredentials(tenantGroup, persister, authenticatorMetadata)
+	RouteAuthenticators(tenantGroup, authenticatorMetadata)
 	RouteAuditLogs(tenantGroup, persister)
+	RouteToken(tenantGroup, persister)
 
 	webauthnGroup := tenantGroup.Group("", passkeyMiddleware.WebauthnMiddleware(persister))
 	RouteRegistration(webauthnGroup, persister, authenticatorMetadata)
 	RouteLogin(webauthnGroup, persister)
 	RouteTransaction(webauthnGroup, persister)
 	RouteMfa(webauthnGroup, persister, authenticatorMetadata)
+	RouteOidc(webauthnGroup, persister, cfg.PublicUrl)
 
 	return main
 }
@@ -70,30 +74,42 @@ func logMetrics(logMetrics bool, router *echo.Echo, group *echo.Group) {
 	}
 }
 
-func RouteWellKnown(parent *echo.Group) {
-	wellKnownHandler := handler.NewWellKnownHandler()
+func RouteWellKnown(parent *echo.Group, publicUrl string) {
+	wellKnownHandler := handler.NewWellKnownHandler(publicUrl)
 
 	group := parent.Group("/.well-known")
 	group.GET("/jwks.json", wellKnownHandler.GetPublicKeys)
+	group.GET("/openid-configuration", wellKnownHandler.GetOpenIdConfiguration)
+	group.GET("/webauthn", wellKnownHandler.GetRelatedOrigins)
 }
 
-func RouteCredentials(parent *echo.Group, persister persistence.Persister) {
-	credentialsHandler := handler.NewCredentialsHandler(persister)
+func RouteCredentials(parent *echo.Group, persister persistence.Persister, authenticatorMetadata mapper.AuthenticatorMetadata) {
+	credentialsHandler := handler.NewCredentialsHandler(persister, authenticatorMetadata)
 
-	group := parent.Group("/credentials", passkeyMiddleware.ApiKeyMiddleware())
-	group.GET("", credentialsHandler.List)
-	group.GET("/:credential_id", credentialsHandler.Get)
-	group.PATCH("/:credential_id", credentialsHandler.Update)
-	group.DELETE("/:credential_id", credentialsHandler.Delete)
+	readScope := passkeyMiddleware.ApiKeyMiddleware(persister, models.ApiKeyScopeCredentialsRead)
+	writeScope := passkeyMiddleware.ApiKeyMiddleware(persister, models.ApiKeyScopeCredentialsWrite)
+
+	group := parent.Group("/credentials")
+	group.GET("", credentialsHandler.List, readScope)
+	group.GET("/:credential_id", credentialsHandler.Get, readScope)
+	group.PATCH("/:credential_id", credentialsHandler.Update, writeScope)
+	group.DELETE("/:credential_id", credentialsHandler.Delete, writeScope)
 
 	return
 }
 
+func RouteAuthenticators(parent *echo.Group, authenticatorMetadata mapper.AuthenticatorMetadata) {
+	authenticatorHandler := handler.NewAuthenticatorHandler(authenticatorMetadata)
+
+	group := parent.Group("/authenticators")
+	group.GET("", authenticatorHandler.List)
+}
+
 func RouteRegistration(parent *echo.Group, persister persistence.Persister, authenticatorMetadata mapper.AuthenticatorMetadata) {
 	registrationHandler := handler.NewRegistrationHandler(persister, authenticatorMetadata, false)
 
 	group := parent.Group("/registration")
-	group.POST(InitEndpoint, registrationHandler.Init, passkeyMiddleware.ApiKeyMiddleware())
+	group.POST(InitEndpoint, registrationHandler.Init, passkeyMiddleware.ApiKeyMiddleware(persister, models.ApiKeyScopeRegistrationInit))
 	group.POST(FinishEndpoint, registrationHandler.Finish)
 }
 
@@ -108,7 +124,7 @@ func RouteLogin(parent *echo.Group, persister persistence.Persister) {
 func RouteTransaction(parent *echo.Group, persister persistence.Persister) {
 	transactionHandler := handler.NewTransactionHandler(persister)
 
-	group := parent.Group("/transaction", passkeyMiddleware.ApiKeyMiddleware())
+	group := parent.Group("/transaction", passkeyMiddleware.ApiKeyMiddleware(persister, models.ApiKeyScopeTransactionWrite))
 	group.GET("/:user_id", transactionHandler.List)
 	group.POST(InitEndpoint, transactionHandler.Init)
 	group.POST(FinishEndpoint, transactionHandler.Finish)
@@ -118,7 +134,7 @@ func RouteMfa(parent *echo.Group, persister persistence.Persister, authenticator
 	mfaRegistrationHandler := handler.NewRegistrationHandler(persister, authenticatorMetadata, true)
 	mfaLoginHandler := handler.NewMfaLoginHandler(persister)
 
-	group := parent.Group("/mfa", passkeyMiddleware.ApiKeyMiddleware())
+	group := parent.Group("/mfa", passkeyMiddleware.ApiKeyMiddleware(persister, models.ApiKeyScopeMfa))
 	group.POST(fmt.Sprintf("/registration%s", InitEndpoint), mfaRegistrationHandler.Init)
 	group.POST(fmt.Sprintf("/registration%s", FinishEndpoint), mfaRegistrationHandler.Finish)
 	group.POST(fmt.Sprintf("/login%s", InitEndpoint), mfaLoginHandler.Init)
@@ -128,6 +144,23 @@ func RouteMfa(parent *echo.Group, persister persistence.Persister, authenticator
 func RouteAuditLogs(parent *echo.Group, persister persistence.Persister) {
 	auditLogHandler := handler.NewAuditLogHandler(persister)
 
-	group := parent.Group("/audit_logs", passkeyMiddleware.ApiKeyMiddleware())
+	group := parent.Group("/audit_logs", passkeyMiddleware.ApiKeyMiddleware(persister, models.ApiKeyScopeAuditLogsRead))
 	group.GET("", auditLogHandler.List)
 }
+
+func RouteToken(parent *echo.Group, persister persistence.Persister) {
+	tokenHandler := handler.NewTokenHandler(persister)
+
+	group := parent.Group("/token", passkeyMiddleware.ApiKeyMiddleware(persister, models.ApiKeyScopeTokenIntrospect))
+	group.POST("/introspect", tokenHandler.Introspect)
+}
+
+func RouteOidc(parent *echo.Group, persister persistence.Persister, publicUrl string) {
+	oidcHandler := handler.NewOidcHandler(persister, publicUrl)
+
+	group := parent.Group("/oidc")
+	group.GET("/authorize", oidcHandler.Authorize)
+	group.POST(fmt.Sprintf("/authorize%s", InitEndpoint), oidcHandler.AuthorizeInit)
+	group.POST(fmt.Sprintf("/authorize%s", FinishEndpoint), oidcHandler.AuthorizeFinish)
+	group.POST("/token", oidcHandler.Token)
+}
@@ -0,0 +1,88 @@
+package admin
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/teamhanko/passkey-server/api/dto/admin/response"
+	"reflect"
+	"sort"
+)
+
+// diffConfigs compares two config documents field by field. Lists of plain values are compared as a whole, lists of
+// objects per entry.
+func diffConfigs(from string, to string) ([]response.ConfigChange, error) {
+	var fromDocument interface{}
+	err := json.Unmarshal([]byte(from), &fromDocument)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse config: %w", err)
+	}
+
+	var toDocument interface{}
+	err = json.Unmarshal([]byte(to), &toDocument)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse config: %w", err)
+	}
+
+	fromValues := make(map[string]interface{})
+	flattenConfig("", fromDocument, fromValues)
+
+	toValues := make(map[string]interface{})
+	flattenConfig("", toDocument, toValues)
+
+	paths := make([]string, 0)
+	for path := range fromValues {
+		paths = append(paths, path)
+	}
+
+	for path := range toValues {
+		if _, ok := fromValues[path]; !ok {
+			paths = append(paths, path)
+		}
+	}
+
+	sort.Strings(paths)
+
+	changes := make([]response.ConfigChange, 0)
+	for _, path := range paths {
+		if !reflect.DeepEqual(fromValues[path], toValues[path]) {
+			changes = append(changes, response.ConfigChange{Path: path, From: fromValues[path], To: toValues[path]})
+		}
+	}
+
+	return changes, nil
+}
+
+func flattenConfig(path string, value interface{}, values map[string]interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			childPath := key
+			if path != "" {
+				childPath = path + "." + key
+			}
+
+			flattenConfig(childPath, child, values)
+		}
+	case []interface{}:
+		if !containsObjects(v) {
+			values[path] = v
+			return
+		}
+
+		for i, child := range v {
+			flattenConfig(fmt.Sprintf("%s[%d]", path, i), child, values)
+		}
+	default:
+		values[path] = v
+	}
+}
+
+func containsObjects(list []interface{}) bool {
+	for _, entry := range list {
+		if _, ok := entry.(map[string]interface{}); ok {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,38 @@
+package admin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/teamhanko/passkey-server/api/dto/admin/response"
+)
+
+func TestDiffConfigs(t *testing.T) {
+	// given
+	from := `{"cors":{"allowed_origins":["https://example.com"]},"webauthn":{"timeout":60000,"attachment":null},"oidc":{"clients":[{"client_id":"a"}]}}`
+	to := `{"cors":{"allowed_origins":["https://example.com","https://example.de"]},"webauthn":{"timeout":120000,"attachment":"platform"},"oidc":{"clients":[{"client_id":"b"}]},"mfa":null}`
+
+	// when
+	changes, err := diffConfigs(from, to)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []response.ConfigChange{
+		{Path: "cors.allowed_origins", From: []interface{}{"https://example.com"}, To: []interface{}{"https://example.com", "https://example.de"}},
+		{Path: "oidc.clients[0].client_id", From: "a", To: "b"},
+		{Path: "webauthn.attachment", From: nil, To: "platform"},
+		{Path: "webauthn.timeout", From: float64(60000), To: float64(120000)},
+	}, changes)
+}
+
+func TestDiffConfigsWithoutChanges(t *testing.T) {
+	// given
+	config := `{"webauthn":{"timeout":60000,"relying_party":{"origins":["https://example.com"]}}}`
+
+	// when
+	changes, err := diffConfigs(config, config)
+
+	// then
+	assert.NoError(t, err)
+	assert.Empty(t, changes)
+}
@@ -0,0 +1,191 @@
+package admin
+
+import (
+	"fmt"
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/teamhanko/passkey-server/api/dto/admin/request"
+	"github.com/teamhanko/passkey-server/api/dto/admin/response"
+	"github.com/teamhanko/passkey-server/api/helper"
+	"github.com/teamhanko/passkey-server/persistence/models"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// LintConfig checks a tenant config for settings which are syntactically valid but do not fit together. Findings with
+// severity error would break the webauthn ceremonies of the tenant, warnings will most likely not work as intended.
+func LintConfig(dto request.CreateConfigDto) response.ConfigFindings {
+	findings := make(response.ConfigFindings, 0)
+
+	findings = append(findings, lintRelyingParty(dto.Passkey)...)
+	findings = append(findings, lintCors(dto)...)
+	findings = append(findings, lintAaguidPolicies(dto)...)
+
+	return findings
+}
+
+func lintRelyingParty(dto request.CreatePasskeyConfigDto) response.ConfigFindings {
+	findings := make(response.ConfigFindings, 0)
+	rp := dto.RelyingParty
+
+	if strings.ContainsAny(rp.Id, ":/") {
+		findings = append(findings, newConfigError("webauthn.relying_party.id", fmt.Sprintf("rp id '%s' must be a domain without scheme, port or path", rp.Id)))
+	}
+
+	relatedOrigins := make(map[string]bool)
+	for i, origin := range rp.RelatedOrigins {
+		relatedOrigins[origin] = true
+		findings = append(findings, lintOrigin(fmt.Sprintf("webauthn.relying_party.related_origins[%d]", i), origin)...)
+	}
+
+	for i, origin := range rp.Origins {
+		field := fmt.Sprintf("webauthn.relying_party.origins[%d]", i)
+		findings = append(findings, lintOrigin(field, origin)...)
+
+		if !relatedOrigins[origin] && !models.IsOriginOfRPId(origin, rp.Id) {
+			findings = append(findings, newConfigWarning(field, fmt.Sprintf("origin '%s' is neither under the rp id '%s' nor listed in the related origins", origin, rp.Id)))
+		}
+	}
+
+	_, err := newWebauthnClient(dto)
+	if err != nil {
+		findings = append(findings, newConfigError("webauthn", fmt.Sprintf("unable to create webauthn client: %s", err.Error())))
+	}
+
+	return findings
+}
+
+// lintOrigin checks that the origin is exactly what browsers send in the client data, otherwise it never matches
+func lintOrigin(field string, origin string) response.ConfigFindings {
+	findings := make(response.ConfigFindings, 0)
+
+	fullyQualifiedOrigin, err := protocol.FullyQualifiedOrigin(origin)
+	if err != nil || !strings.EqualFold(fullyQualifiedOrigin, origin) {
+		findings = append(findings, newConfigError(field, fmt.Sprintf("origin '%s' must be of the form 'scheme://host[:port]' without path, query or trailing slash", origin)))
+		return findings
+	}
+
+	originUrl, err := url.Parse(origin)
+	if err == nil && originUrl.Scheme == "http" && originUrl.Hostname() != "localhost" {
+		findings = append(findings, newConfigWarning(field, fmt.Sprintf("origin '%s' is not a secure context, browsers only allow passkeys on https or localhost", origin)))
+	}
+
+	return findings
+}
+
+// lintCors checks that the web origins of the relying party are allowed to call the passkey api from the browser
+func lintCors(dto request.CreateConfigDto) response.ConfigFindings {
+	findings := make(response.ConfigFindings, 0)
+
+	allowedOriginPatterns := make([]*regexp.Regexp, 0)
+	for _, allowedOrigin := range dto.Cors.AllowedOrigins {
+		// same matching as the echo cors middleware
+		pattern := regexp.QuoteMeta(allowedOrigin)
+		pattern = strings.ReplaceAll(pattern, "\\*", ".*")
+		pattern = strings.ReplaceAll(pattern, "\\?", ".")
+
+		re, err := regexp.Compile("^" + pattern + "$")
+		if err == nil {
+			allowedOriginPatterns = append(allowedOriginPatterns, re)
+		}
+	}
+
+	origins := append(append([]string{}, dto.Passkey.RelyingParty.Origins...), dto.Passkey.RelyingParty.RelatedOrigins...)
+	checkedOrigins := make(map[string]bool)
+	for _, origin := range origins {
+		if checkedOrigins[origin] || !isWebOrigin(origin) {
+			continue
+		}
+
+		checkedOrigins[origin] = true
+
+		if !matchesAny(origin, allowedOriginPatterns) {
+			findings = append(findings, newConfigWarning("cors.allowed_origins", fmt.Sprintf("webauthn origin '%s' is not allowed by the cors origins, browsers on it can only use the api through a backend", origin)))
+		}
+	}
+
+	return findings
+}
+
+// lintAaguidPolicies checks that the AAGUIDs of the policies are attested. Without a trusted attestation the AAGUID is
+// asserted by the authenticator itself, so an allowlist can be passed by claiming an allowed AAGUID and a denylist by
+// claiming any other.
+func lintAaguidPolicies(dto request.CreateConfigDto) response.ConfigFindings {
+	findings := make(response.ConfigFindings, 0)
+	if dto.Aaguid == nil {
+		return findings
+	}
+
+	passkeyConfig := dto.Passkey.ToModel(models.Config{})
+	passkeyTrusted := passkeyConfig.RequireTrustedAttestation && isTrustedAttestation(passkeyConfig.AttestationPreference)
+	if dto.Passkey.RegistrationOverrides != nil {
+		for _, attestation := range dto.Passkey.RegistrationOverrides.Attestation {
+			passkeyTrusted = passkeyTrusted && isTrustedAttestation(protocol.ConveyancePreference(attestation))
+		}
+	}
+
+	mfaTrusted := false
+	if dto.Mfa != nil {
+		mfaConfig := dto.Mfa.ToModel(models.Config{})
+		mfaTrusted = mfaConfig.RequireTrustedAttestation && isTrustedAttestation(mfaConfig.AttestationPreference)
+	}
+
+	findings = append(findings, lintAaguidLists(models.AaguidPolicyFlowPasskey, dto.Aaguid.Passkey, passkeyTrusted)...)
+	findings = append(findings, lintAaguidLists(models.AaguidPolicyFlowMfa, dto.Aaguid.Mfa, mfaTrusted)...)
+
+	return findings
+}
+
+func lintAaguidLists(flow models.AaguidPolicyFlow, lists *request.CreateAaguidListsDto, trusted bool) response.ConfigFindings {
+	findings := make(response.ConfigFindings, 0)
+	if lists == nil || trusted {
+		return findings
+	}
+
+	if len(lists.Allow) > 0 {
+		findings = append(findings, newConfigError(fmt.Sprintf("aaguid_policy.%s.allow", flow), fmt.Sprintf("aaguid allowlist of flow '%s' requires require_trusted_attestation with direct or enterprise attestation, otherwise authenticators can claim an allowed aaguid", flow)))
+	}
+
+	if len(lists.Deny) > 0 {
+		findings = append(findings, newConfigWarning(fmt.Sprintf("aaguid_policy.%s.deny", flow), fmt.Sprintf("aaguid denylist of flow '%s' can be bypassed by authenticators claiming another aaguid without require_trusted_attestation with direct or enterprise attestation", flow)))
+	}
+
+	return findings
+}
+
+// isTrustedAttestation returns true for the preferences which are verified by the attestation trust check
+func isTrustedAttestation(preference protocol.ConveyancePreference) bool {
+	return preference == protocol.PreferDirectAttestation || preference == protocol.PreferEnterpriseAttestation
+}
+
+// newWebauthnClient creates the passkey client with the same constructor as the webauthn middleware
+func newWebauthnClient(dto request.CreatePasskeyConfigDto) (*webauthn.WebAuthn, error) {
+	webauthnConfig := dto.ToModel(models.Config{})
+	webauthnConfig.RelyingParty = dto.RelyingParty.ToModel(webauthnConfig)
+	ceremonyConfigs := dto.Ceremonies.ToModel(models.Config{})
+
+	return helper.NewWebauthnClient(helper.NewPasskeyClientParams(webauthnConfig, ceremonyConfigs))
+}
+
+func isWebOrigin(origin string) bool {
+	return strings.HasPrefix(origin, "https://") || strings.HasPrefix(origin, "http://")
+}
+
+func matchesAny(origin string, patterns []*regexp.Regexp) bool {
+	for _, pattern := range patterns {
+		if pattern.MatchString(origin) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func newConfigError(field string, message string) response.ConfigFinding {
+	return response.ConfigFinding{Severity: response.ConfigFindingSeverityError, Field: field, Message: message}
+}
+
+func newConfigWarning(field string, message string) response.ConfigFinding {
+	return response.ConfigFinding{Severity: response.ConfigFindingSeverityWarning, Field: field, Message: message}
+}
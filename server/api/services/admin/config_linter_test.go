@@ -0,0 +1,183 @@
+package admin
+
+import (
+	"testing"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/teamhanko/passkey-server/api/dto/admin/request"
+	"github.com/teamhanko/passkey-server/api/dto/admin/response"
+)
+
+func newLintTestConfig(rpId string, origins []string, relatedOrigins []string, corsOrigins []string) request.CreateConfigDto {
+	allowUnsafe := false
+
+	return request.CreateConfigDto{
+		Cors: request.CreateCorsDto{
+			AllowedOrigins:      corsOrigins,
+			AllowUnsafeWildcard: &allowUnsafe,
+		},
+		Passkey: request.CreatePasskeyConfigDto{
+			RelyingParty: request.CreateRelyingPartyDto{
+				Id:             rpId,
+				DisplayName:    "Example",
+				Origins:        origins,
+				RelatedOrigins: relatedOrigins,
+			},
+			Timeout: 60000,
+		},
+	}
+}
+
+func TestLintConfigValid(t *testing.T) {
+	// given
+	dto := newLintTestConfig(
+		"example.com",
+		[]string{"https://example.com", "https://login.example.com", "android:apk-key-hash:abc"},
+		[]string{"https://example.de"},
+		[]string{"https://*.example.com", "https://example.com", "https://example.de"},
+	)
+
+	// when
+	findings := LintConfig(dto)
+
+	// then
+	assert.Empty(t, findings)
+	assert.False(t, findings.HasErrors())
+}
+
+func TestLintConfigInvalidOrigins(t *testing.T) {
+	// given
+	dto := newLintTestConfig(
+		"https://example.com",
+		[]string{"https://example.com/", "https://example.com/login", "example.com"},
+		nil,
+		[]string{"*"},
+	)
+
+	// when
+	findings := LintConfig(dto)
+
+	// then
+	assert.True(t, findings.HasErrors())
+	assert.Contains(t, findings, newConfigError("webauthn.relying_party.id", "rp id 'https://example.com' must be a domain without scheme, port or path"))
+
+	var fields []string
+	for _, finding := range findings {
+		if finding.Severity == response.ConfigFindingSeverityError {
+			fields = append(fields, finding.Field)
+		}
+	}
+
+	assert.Contains(t, fields, "webauthn.relying_party.origins[0]")
+	assert.Contains(t, fields, "webauthn.relying_party.origins[1]")
+	assert.Contains(t, fields, "webauthn.relying_party.origins[2]")
+}
+
+func TestLintConfigWarnings(t *testing.T) {
+	// given
+	dto := newLintTestConfig(
+		"example.com",
+		[]string{"http://example.com", "https://example.org", "http://localhost:3000"},
+		nil,
+		[]string{"http://example.com"},
+	)
+
+	// when
+	findings := LintConfig(dto)
+
+	// then
+	assert.False(t, findings.HasErrors())
+	assert.ElementsMatch(t, response.ConfigFindings{
+		newConfigWarning("webauthn.relying_party.origins[0]", "origin 'http://example.com' is not a secure context, browsers only allow passkeys on https or localhost"),
+		newConfigWarning("webauthn.relying_party.origins[1]", "origin 'https://example.org' is neither under the rp id 'example.com' nor listed in the related origins"),
+		newConfigWarning("webauthn.relying_party.origins[2]", "origin 'http://localhost:3000' is neither under the rp id 'example.com' nor listed in the related origins"),
+		newConfigWarning("cors.allowed_origins", "webauthn origin 'https://example.org' is not allowed by the cors origins, browsers on it can only use the api through a backend"),
+		newConfigWarning("cors.allowed_origins", "webauthn origin 'http://localhost:3000' is not allowed by the cors origins, browsers on it can only use the api through a backend"),
+	}, findings)
+}
+
+func TestLintConfigMissingDisplayName(t *testing.T) {
+	// given
+	dto := newLintTestConfig("example.com", []string{"https://example.com"}, nil, []string{"https://example.com"})
+	dto.Passkey.RelyingParty.DisplayName = ""
+
+	// when
+	findings := LintConfig(dto)
+
+	// then
+	assert.True(t, findings.HasErrors())
+	assert.Equal(t, "webauthn", findings[0].Field)
+}
+
+func TestLintConfigUntrustedAaguidPolicies(t *testing.T) {
+	// given
+	dto := newLintTestConfig("example.com", []string{"https://example.com"}, nil, []string{"https://example.com"})
+	dto.Aaguid = &request.CreateAaguidPolicyDto{
+		Passkey: &request.CreateAaguidListsDto{Deny: []string{"ea9b8d66-4d01-1d21-3ce4-b6b48cb575d4"}},
+		Mfa:     &request.CreateAaguidListsDto{Allow: []string{"cb69481e-8ff7-4039-93ec-0a2729a154a8"}},
+	}
+
+	// when
+	findings := LintConfig(dto)
+
+	// then
+	assert.True(t, findings.HasErrors())
+	assert.ElementsMatch(t, response.ConfigFindings{
+		newConfigWarning("aaguid_policy.passkey.deny", "aaguid denylist of flow 'passkey' can be bypassed by authenticators claiming another aaguid without require_trusted_attestation with direct or enterprise attestation"),
+		newConfigError("aaguid_policy.mfa.allow", "aaguid allowlist of flow 'mfa' requires require_trusted_attestation with direct or enterprise attestation, otherwise authenticators can claim an allowed aaguid"),
+	}, findings)
+}
+
+func TestLintConfigTrustedAaguidPolicies(t *testing.T) {
+	// given
+	dto := newLintTestConfig("example.com", []string{"https://example.com"}, nil, []string{"https://example.com"})
+	dto.Passkey.RequireTrustedAttestation = true
+	dto.Mfa = &request.CreateMFAConfigDto{Timeout: 60000, RequireTrustedAttestation: true}
+	dto.Aaguid = &request.CreateAaguidPolicyDto{
+		Passkey: &request.CreateAaguidListsDto{Allow: []string{"ea9b8d66-4d01-1d21-3ce4-b6b48cb575d4"}},
+		Mfa:     &request.CreateAaguidListsDto{Allow: []string{"cb69481e-8ff7-4039-93ec-0a2729a154a8"}},
+	}
+
+	// when
+	findings := LintConfig(dto)
+
+	// then
+	assert.Empty(t, findings)
+}
+
+func TestLintConfigAaguidAllowlistWithWeakerAttestationOverride(t *testing.T) {
+	// given
+	dto := newLintTestConfig("example.com", []string{"https://example.com"}, nil, []string{"https://example.com"})
+	dto.Passkey.RequireTrustedAttestation = true
+	dto.Passkey.RegistrationOverrides = &request.CreateRegistrationOverridesDto{Attestation: []string{"direct", "none"}}
+	dto.Aaguid = &request.CreateAaguidPolicyDto{
+		Passkey: &request.CreateAaguidListsDto{Allow: []string{"ea9b8d66-4d01-1d21-3ce4-b6b48cb575d4"}},
+	}
+
+	// when
+	findings := LintConfig(dto)
+
+	// then
+	assert.True(t, findings.HasErrors())
+	assert.Equal(t, "aaguid_policy.passkey.allow", findings[0].Field)
+}
+
+func TestNewWebauthnClientUsesAuthenticatorSelection(t *testing.T) {
+	// given
+	dto := newLintTestConfig("example.com", []string{"https://example.com"}, nil, []string{"https://example.com"})
+	attachment := protocol.Platform
+	residentKey := protocol.ResidentKeyRequirementRequired
+	dto.Passkey.Attachment = &attachment
+	dto.Passkey.ResidentKeyRequirement = &residentKey
+
+	// when
+	client, err := newWebauthnClient(dto.Passkey)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, protocol.Platform, client.Config.AuthenticatorSelection.AuthenticatorAttachment)
+	assert.Equal(t, protocol.ResidentKeyRequirementRequired, client.Config.AuthenticatorSelection.ResidentKey)
+	assert.True(t, *client.Config.AuthenticatorSelection.RequireResidentKey)
+	assert.Equal(t, []string{"https://example.com"}, client.Config.RPOrigins)
+}
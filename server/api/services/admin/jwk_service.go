@@ -0,0 +1,106 @@
+package admin
+
+import (
+	"errors"
+	"fmt"
+	"github.com/gofrs/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/teamhanko/passkey-server/api/dto/admin/request"
+	"github.com/teamhanko/passkey-server/api/dto/admin/response"
+	hankoJwk "github.com/teamhanko/passkey-server/crypto/jwk"
+	"github.com/teamhanko/passkey-server/persistence/models"
+	"github.com/teamhanko/passkey-server/persistence/persisters"
+	"net/http"
+)
+
+type JwkService interface {
+	List() (response.JwkResponseListDto, error)
+	Rotate(dto request.RotateJwkDto) (*response.JwkResponseDto, error)
+}
+
+type jwkService struct {
+	logger echo.Logger
+	tenant models.Tenant
+
+	jwkPersister persisters.JwkPersister
+}
+
+func NewJwkService(ctx echo.Context, tenant models.Tenant, jwkPersister persisters.JwkPersister) JwkService {
+	return &jwkService{
+		logger:       ctx.Logger(),
+		tenant:       tenant,
+		jwkPersister: jwkPersister,
+	}
+}
+
+func (js *jwkService) List() (response.JwkResponseListDto, error) {
+	manager, err := js.createManager(false)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := manager.GetKeys(js.tenant.ID)
+	if err != nil {
+		js.logger.Error(err)
+		return nil, fmt.Errorf("unable to list jwks: %w", err)
+	}
+
+	jwks := make(response.JwkResponseListDto, 0)
+	for _, key := range keys {
+		jwks = append(jwks, response.ToJwkResponse(key))
+	}
+
+	return jwks, nil
+}
+
+func (js *jwkService) Rotate(dto request.RotateJwkDto) (*response.JwkResponseDto, error) {
+	manager, err := js.createManager(true)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := manager.Rotate(js.tenant.ID, dto.Immediate)
+	if err != nil {
+		if errors.Is(err, hankoJwk.ErrRotationPending) {
+			return nil, echo.NewHTTPError(http.StatusConflict, "a key rotation is already pending. Use 'immediate' to activate a new key right away").SetInternal(err)
+		}
+
+		js.logger.Error(err)
+		return nil, fmt.Errorf("unable to rotate jwks: %w", err)
+	}
+
+	jwkDto := response.ToJwkResponse(*key)
+
+	return &jwkDto, nil
+}
+
+// createManager returns a manager for the keys of the tenant. Keys are only generated for writing requests, as they
+// lock the tenant.
+func (js *jwkService) createManager(generateKeys bool) (hankoJwk.Manager, error) {
+	var keys []string
+	for _, secret := range js.tenant.Config.Secrets {
+		if !secret.IsAPISecret {
+			keys = append(keys, secret.Key)
+		}
+	}
+
+	algorithm := models.DefaultJwtSigningAlgorithm
+	if js.tenant.Config.JwtConfig != nil && js.tenant.Config.JwtConfig.ID != uuid.Nil {
+		algorithm = js.tenant.Config.JwtConfig.SigningAlgorithm
+	}
+
+	var manager hankoJwk.Manager
+	var err error
+	if generateKeys {
+		manager, err = hankoJwk.NewDefaultManager(keys, js.tenant.ID, algorithm, js.jwkPersister)
+	} else {
+		manager, err = hankoJwk.NewManager(keys, algorithm, js.jwkPersister)
+	}
+
+	if err != nil {
+		js.logger.Error(err)
+		return nil, fmt.Errorf("unable to initialize jwk manager: %w", err)
+	}
+
+	return manager, nil
+}
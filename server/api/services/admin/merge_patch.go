@@ -0,0 +1,56 @@
+package admin
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// mergePatch applies a JSON merge patch (RFC 7386) to the document: objects are merged recursively, null removes a
+// member and every other value replaces the target value.
+func mergePatch(document []byte, patch []byte) ([]byte, error) {
+	target, err := decodeJson(document)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse document: %w", err)
+	}
+
+	patchValue, err := decodeJson(patch)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse merge patch: %w", err)
+	}
+
+	return json.Marshal(mergeValue(target, patchValue))
+}
+
+func mergeValue(target interface{}, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = make(map[string]interface{})
+	}
+
+	for name, value := range patchObject {
+		if value == nil {
+			delete(targetObject, name)
+		} else {
+			targetObject[name] = mergeValue(targetObject[name], value)
+		}
+	}
+
+	return targetObject
+}
+
+// decodeJson keeps numbers as they are, so integers are not converted to floats
+func decodeJson(data []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value interface{}
+	err := decoder.Decode(&value)
+
+	return value, err
+}
@@ -0,0 +1,41 @@
+package admin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergePatch(t *testing.T) {
+	// given
+	document := `{"cors":{"allowed_origins":["https://example.com"],"allow_unsafe_wildcard":false},"webauthn":{"timeout":60000,"attachment":"platform"},"mfa":{"timeout":60000}}`
+	patch := `{"cors":{"allowed_origins":["https://example.de"]},"webauthn":{"timeout":120000,"attachment":null},"mfa":null,"jwt":{"issuer":"https://example.com"}}`
+
+	// when
+	patched, err := mergePatch([]byte(document), []byte(patch))
+
+	// then
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"cors":{"allowed_origins":["https://example.de"],"allow_unsafe_wildcard":false},"webauthn":{"timeout":120000},"jwt":{"issuer":"https://example.com"}}`, string(patched))
+}
+
+func TestMergePatchReplacesNonObjects(t *testing.T) {
+	// given
+	document := `{"webauthn":{"ceremonies":null}}`
+	patch := `{"webauthn":{"ceremonies":{"login":{"timeout":30000,"attachment":null}}}}`
+
+	// when
+	patched, err := mergePatch([]byte(document), []byte(patch))
+
+	// then
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"webauthn":{"ceremonies":{"login":{"timeout":30000}}}}`, string(patched))
+}
+
+func TestMergePatchInvalidPatch(t *testing.T) {
+	// when
+	_, err := mergePatch([]byte(`{}`), []byte(`{"webauthn":`))
+
+	// then
+	assert.Error(t, err)
+}
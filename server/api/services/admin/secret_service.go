@@ -9,6 +9,7 @@ import (
 	"github.com/teamhanko/passkey-server/persistence/models"
 	"github.com/teamhanko/passkey-server/persistence/persisters"
 	"net/http"
+	"time"
 )
 
 type SecretService interface {
@@ -45,7 +46,11 @@ func (ses *secretService) List(isApiSecret bool) response.SecretResponseListDto
 }
 
 func (ses *secretService) Create(dto request.CreateSecretDto, isApiSecret bool) (*response.SecretResponseDto, error) {
-	secret, err := dto.ToModel(&ses.tenant.Config, isApiSecret)
+	if isApiSecret && dto.ExpiresAt != nil && !dto.ExpiresAt.After(time.Now()) {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "expires_at must be in the future")
+	}
+
+	secret, key, err := dto.ToModel(&ses.tenant.Config, isApiSecret)
 	if err != nil {
 		ses.logger.Error(err)
 		return nil, err
@@ -57,7 +62,7 @@ func (ses *secretService) Create(dto request.CreateSecretDto, isApiSecret bool)
 		return nil, err
 	}
 
-	responseDto := response.ToSecretResponse(secret)
+	responseDto := response.ToCreatedSecretResponse(secret, key)
 	return responseDto, nil
 }
 
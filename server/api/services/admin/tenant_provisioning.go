@@ -0,0 +1,320 @@
+package admin
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/teamhanko/passkey-server/api/dto/admin/request"
+	"github.com/teamhanko/passkey-server/crypto"
+	hankoJwk "github.com/teamhanko/passkey-server/crypto/jwk"
+	"github.com/teamhanko/passkey-server/persistence/models"
+	"strings"
+	"time"
+)
+
+const initialJwkKeyName = "Initial JWK Key"
+
+func (ts *tenantService) Provision(dto request.ProvisionTenantDto, dryRun bool) ([]string, error) {
+	// an existing tenant is locked before it is read, so its config can't be changed by the admin API in the meantime
+	err := ts.tenantPersister.Lock(dto.Id)
+	if err != nil {
+		ts.logger.Error(err)
+		return nil, err
+	}
+
+	tenant, err := ts.tenantPersister.Get(dto.Id)
+	if err != nil {
+		ts.logger.Error(err)
+		return nil, fmt.Errorf("failed to get tenant: %w", err)
+	}
+
+	if tenant == nil {
+		return ts.provisionNewTenant(dto, dryRun)
+	}
+
+	ts.tenant = tenant
+
+	return ts.provisionExistingTenant(dto, dryRun)
+}
+
+func (ts *tenantService) provisionNewTenant(dto request.ProvisionTenantDto, dryRun bool) ([]string, error) {
+	err := ts.checkConfig(dto.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := []string{"create tenant"}
+	for _, apiKey := range dto.ApiKeys {
+		changes = append(changes, fmt.Sprintf("create api key '%s'", apiKey.Name))
+	}
+
+	for _, jwkKey := range dto.JwkKeys {
+		changes = append(changes, fmt.Sprintf("create jwk key '%s'", jwkKey.Name))
+	}
+
+	if len(dto.JwkKeys) == 0 {
+		changes = append(changes, fmt.Sprintf("create jwk key '%s'", initialJwkKeyName))
+	}
+
+	if dryRun {
+		return changes, nil
+	}
+
+	now := time.Now()
+	tenantModel := models.Tenant{
+		ID:          dto.Id,
+		DisplayName: dto.DisplayName,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	configModel, jwtConfigModel, err := ts.createTenant(&tenantModel, dto.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, apiKey := range dto.ApiKeys {
+		_, err = ts.storeSecret(apiKey, configModel.ID, true)
+		if err != nil {
+			ts.logger.Error(err)
+			return nil, fmt.Errorf("unable to create api key '%s': %w", apiKey.Name, err)
+		}
+	}
+
+	jwks := make([]string, 0)
+	for _, jwkKey := range dto.JwkKeys {
+		_, err = ts.storeSecret(jwkKey, configModel.ID, false)
+		if err != nil {
+			ts.logger.Error(err)
+			return nil, fmt.Errorf("unable to create jwk key '%s': %w", jwkKey.Name, err)
+		}
+
+		jwks = append(jwks, jwkKey.Key)
+	}
+
+	if len(jwks) == 0 {
+		jwkSecretModel, _, err := ts.createSecret(initialJwkKeyName, configModel.ID, false)
+		if err != nil {
+			ts.logger.Error(err)
+			return nil, fmt.Errorf("unable to create new jwk key: %w", err)
+		}
+
+		jwks = append(jwks, jwkSecretModel.Key)
+	}
+
+	_, err = hankoJwk.NewDefaultManager(jwks, tenantModel.ID, jwtConfigModel.SigningAlgorithm, ts.jwkPersister)
+	if err != nil {
+		ts.logger.Error(err)
+		return nil, fmt.Errorf("unable to initialize jwt generator: %w", err)
+	}
+
+	return changes, nil
+}
+
+func (ts *tenantService) provisionExistingTenant(dto request.ProvisionTenantDto, dryRun bool) ([]string, error) {
+	changes := make([]string, 0)
+
+	if ts.tenant.DisplayName != dto.DisplayName {
+		changes = append(changes, fmt.Sprintf("update display name from '%s' to '%s'", ts.tenant.DisplayName, dto.DisplayName))
+
+		if !dryRun {
+			err := ts.Update(request.UpdateTenantDto{DisplayName: dto.DisplayName})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	configChanges, err := ts.diffCurrentConfig(dto.Config)
+	if err != nil {
+		ts.logger.Error(err)
+		return nil, err
+	}
+
+	if len(configChanges) > 0 {
+		changes = append(changes, fmt.Sprintf("update config: %s", strings.Join(configChanges, ", ")))
+
+		if dryRun {
+			err = ts.checkConfig(dto.Config)
+		} else {
+			err = ts.provisionConfig(dto)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	secretChanges, err := ts.provisionSecrets(dto, dryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(changes, secretChanges...), nil
+}
+
+// diffCurrentConfig returns the paths of the settings which differ between the current config and the dto
+func (ts *tenantService) diffCurrentConfig(dto request.CreateConfigDto) ([]string, error) {
+	currentConfig, _, err := ts.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	from, err := json.Marshal(currentConfig.CreateConfigDto)
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal current config: %w", err)
+	}
+
+	to, err := json.Marshal(dto)
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal config: %w", err)
+	}
+
+	changes, err := diffConfigs(string(from), string(to))
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, 0)
+	for _, change := range changes {
+		paths = append(paths, change.Path)
+	}
+
+	return paths, nil
+}
+
+// provisionConfig updates the config and reloads the tenant, as the update replaces the config and moves its secrets
+func (ts *tenantService) provisionConfig(dto request.ProvisionTenantDto) error {
+	_, err := ts.updateConfig(dto.Config, nil)
+	if err != nil {
+		return err
+	}
+
+	tenant, err := ts.tenantPersister.Get(dto.Id)
+	if err != nil {
+		ts.logger.Error(err)
+		return fmt.Errorf("failed to get tenant: %w", err)
+	}
+
+	ts.tenant = tenant
+
+	return nil
+}
+
+// provisionSecrets creates missing secrets, rotates changed api keys and updates their scopes and expiry. Secrets which
+// are not part of the dto are kept. JWK keys can't be rotated, as the signing keys of the tenant are encrypted with them.
+func (ts *tenantService) provisionSecrets(dto request.ProvisionTenantDto, dryRun bool) ([]string, error) {
+	changes := make([]string, 0)
+	config := ts.tenant.Config
+
+	for _, apiKey := range dto.ApiKeys {
+		secret := findSecret(config.Secrets, apiKey.Name, true)
+
+		if secret == nil {
+			changes = append(changes, fmt.Sprintf("create api key '%s'", apiKey.Name))
+
+			if !dryRun {
+				_, err := ts.storeSecret(apiKey, config.ID, true)
+				if err != nil {
+					ts.logger.Error(err)
+					return nil, fmt.Errorf("unable to create api key '%s': %w", apiKey.Name, err)
+				}
+			}
+
+			continue
+		}
+
+		hashedKey := crypto.HashSecret(apiKey.Key)
+		keyChanged := secret.Key != hashedKey
+		expiryChanged := !equalExpiry(secret.ExpiresAt, apiKey.ExpiresAt)
+		scopesChanged := !hasExactScopes(secret.Scopes, apiKey.GetScopes())
+
+		if keyChanged {
+			changes = append(changes, fmt.Sprintf("rotate api key '%s'", apiKey.Name))
+		}
+
+		if expiryChanged {
+			changes = append(changes, fmt.Sprintf("update expiry of api key '%s'", apiKey.Name))
+		}
+
+		if scopesChanged {
+			changes = append(changes, fmt.Sprintf("update scopes of api key '%s'", apiKey.Name))
+		}
+
+		if dryRun {
+			continue
+		}
+
+		if keyChanged || expiryChanged {
+			secret.Key = hashedKey
+			secret.ExpiresAt = apiKey.ExpiresAt
+			secret.UpdatedAt = time.Now()
+
+			err := ts.secretPersister.Update(secret)
+			if err != nil {
+				ts.logger.Error(err)
+				return nil, fmt.Errorf("unable to update api key '%s': %w", apiKey.Name, err)
+			}
+		}
+
+		if scopesChanged {
+			err := ts.secretPersister.ReplaceScopes(secret, models.NewSecretScopes(secret.ID, apiKey.GetScopes()))
+			if err != nil {
+				ts.logger.Error(err)
+				return nil, fmt.Errorf("unable to update scopes of api key '%s': %w", apiKey.Name, err)
+			}
+		}
+	}
+
+	for _, jwkKey := range dto.JwkKeys {
+		secret := findSecret(config.Secrets, jwkKey.Name, false)
+
+		if secret == nil {
+			changes = append(changes, fmt.Sprintf("create jwk key '%s'", jwkKey.Name))
+
+			if !dryRun {
+				_, err := ts.storeSecret(jwkKey, config.ID, false)
+				if err != nil {
+					ts.logger.Error(err)
+					return nil, fmt.Errorf("unable to create jwk key '%s': %w", jwkKey.Name, err)
+				}
+			}
+		} else if secret.Key != jwkKey.Key {
+			return nil, fmt.Errorf("jwk key '%s' can't be changed, add a new jwk key instead", jwkKey.Name)
+		}
+	}
+
+	return changes, nil
+}
+
+func findSecret(secrets models.Secrets, name string, isApiKey bool) *models.Secret {
+	for i := range secrets {
+		if secrets[i].Name == name && secrets[i].IsAPISecret == isApiKey {
+			return &secrets[i]
+		}
+	}
+
+	return nil
+}
+
+func equalExpiry(a *time.Time, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return a.Equal(*b)
+}
+
+// hasExactScopes returns true when the secret has the given scopes and no others
+func hasExactScopes(scopes models.SecretScopes, names []models.ApiKeyScope) bool {
+	if len(scopes) != len(names) {
+		return false
+	}
+
+	for _, name := range names {
+		if !scopes.Contains(name) {
+			return false
+		}
+	}
+
+	return true
+}
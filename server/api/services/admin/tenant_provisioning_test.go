@@ -0,0 +1,184 @@
+package admin
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/teamhanko/passkey-server/api/dto/admin/request"
+	"github.com/teamhanko/passkey-server/crypto"
+	"github.com/teamhanko/passkey-server/persistence/models"
+	"github.com/teamhanko/passkey-server/persistence/persisters"
+)
+
+const testApiKey = "an-api-key-with-enough-characters"
+
+type testSecretsPersister struct {
+	persisters.SecretsPersister
+	created  []*models.Secret
+	updated  []*models.Secret
+	replaced models.SecretScopes
+}
+
+func (p *testSecretsPersister) Create(secret *models.Secret) error {
+	p.created = append(p.created, secret)
+	return nil
+}
+
+func (p *testSecretsPersister) Update(secret *models.Secret) error {
+	p.updated = append(p.updated, secret)
+	return nil
+}
+
+func (p *testSecretsPersister) ReplaceScopes(secret *models.Secret, scopes models.SecretScopes) error {
+	p.replaced = scopes
+	secret.Scopes = scopes
+	return nil
+}
+
+type testProvisioningTenantPersister struct {
+	persisters.TenantPersister
+	calls []string
+}
+
+func (p *testProvisioningTenantPersister) Lock(_ uuid.UUID) error {
+	p.calls = append(p.calls, "lock")
+	return nil
+}
+
+func (p *testProvisioningTenantPersister) Get(_ uuid.UUID) (*models.Tenant, error) {
+	p.calls = append(p.calls, "get")
+	return nil, errors.New("connection refused")
+}
+
+func newTestProvisioningService(secrets models.Secrets) (*tenantService, *testSecretsPersister) {
+	secretsPersister := &testSecretsPersister{}
+
+	return &tenantService{
+		logger: echo.New().Logger,
+		tenant: &models.Tenant{
+			ID:     uuid.Must(uuid.NewV4()),
+			Config: models.Config{ID: uuid.Must(uuid.NewV4()), Secrets: secrets},
+		},
+		secretPersister: secretsPersister,
+	}, secretsPersister
+}
+
+func newTestApiKeySecret(scopes []models.ApiKeyScope, expiresAt *time.Time) models.Secret {
+	id := uuid.Must(uuid.NewV4())
+
+	return models.Secret{
+		ID:          id,
+		Name:        "backend",
+		Key:         crypto.HashSecret(testApiKey),
+		IsAPISecret: true,
+		Scopes:      models.NewSecretScopes(id, scopes),
+		ExpiresAt:   expiresAt,
+	}
+}
+
+func TestProvisionSecretsCreatesApiKeyWithScopesAndExpiry(t *testing.T) {
+	// given
+	expiresAt := time.Now().Add(time.Hour)
+	ts, secretsPersister := newTestProvisioningService(models.Secrets{})
+	dto := request.ProvisionTenantDto{ApiKeys: []request.ProvisionSecretDto{
+		{Name: "backend", Key: testApiKey, Scopes: []models.ApiKeyScope{models.ApiKeyScopeTokenIntrospect}, ExpiresAt: &expiresAt},
+	}}
+
+	// when
+	changes, err := ts.provisionSecrets(dto, false)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"create api key 'backend'"}, changes)
+	assert.Len(t, secretsPersister.created, 1)
+	assert.Equal(t, []string{"token:introspect"}, secretsPersister.created[0].Scopes.GetNames())
+	assert.Equal(t, &expiresAt, secretsPersister.created[0].ExpiresAt)
+}
+
+func TestProvisionSecretsGrantsAllScopesWhenOmitted(t *testing.T) {
+	// given
+	ts, secretsPersister := newTestProvisioningService(models.Secrets{})
+	dto := request.ProvisionTenantDto{ApiKeys: []request.ProvisionSecretDto{{Name: "backend", Key: testApiKey}}}
+
+	// when
+	_, err := ts.provisionSecrets(dto, false)
+
+	// then
+	assert.NoError(t, err)
+	assert.Len(t, secretsPersister.created[0].Scopes, len(models.AllApiKeyScopes))
+	assert.Nil(t, secretsPersister.created[0].ExpiresAt)
+}
+
+func TestProvisionSecretsUpdatesScopesAndExpiry(t *testing.T) {
+	// given
+	expiresAt := time.Now().Add(time.Hour)
+	ts, secretsPersister := newTestProvisioningService(models.Secrets{newTestApiKeySecret(models.AllApiKeyScopes, nil)})
+	dto := request.ProvisionTenantDto{ApiKeys: []request.ProvisionSecretDto{
+		{Name: "backend", Key: testApiKey, Scopes: []models.ApiKeyScope{models.ApiKeyScopeMfa}, ExpiresAt: &expiresAt},
+	}}
+
+	// when
+	changes, err := ts.provisionSecrets(dto, false)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"update expiry of api key 'backend'", "update scopes of api key 'backend'"}, changes)
+	assert.Len(t, secretsPersister.updated, 1)
+	assert.Equal(t, &expiresAt, secretsPersister.updated[0].ExpiresAt)
+	assert.Equal(t, []string{"mfa"}, secretsPersister.replaced.GetNames())
+}
+
+func TestProvisionSecretsKeepsUnchangedApiKey(t *testing.T) {
+	// given
+	expiresAt := time.Now().Add(time.Hour)
+	scopes := []models.ApiKeyScope{models.ApiKeyScopeLoginInit, models.ApiKeyScopeMfa}
+	ts, secretsPersister := newTestProvisioningService(models.Secrets{newTestApiKeySecret(scopes, &expiresAt)})
+	sameExpiry := expiresAt.UTC()
+	dto := request.ProvisionTenantDto{ApiKeys: []request.ProvisionSecretDto{
+		{Name: "backend", Key: testApiKey, Scopes: []models.ApiKeyScope{models.ApiKeyScopeMfa, models.ApiKeyScopeLoginInit}, ExpiresAt: &sameExpiry},
+	}}
+
+	// when
+	changes, err := ts.provisionSecrets(dto, false)
+
+	// then
+	assert.NoError(t, err)
+	assert.Empty(t, changes)
+	assert.Empty(t, secretsPersister.updated)
+	assert.Nil(t, secretsPersister.replaced)
+}
+
+func TestProvisionSecretsDryRunOnlyPlansChanges(t *testing.T) {
+	// given
+	ts, secretsPersister := newTestProvisioningService(models.Secrets{newTestApiKeySecret(models.AllApiKeyScopes, nil)})
+	dto := request.ProvisionTenantDto{ApiKeys: []request.ProvisionSecretDto{
+		{Name: "backend", Key: "another-api-key-with-enough-characters", Scopes: []models.ApiKeyScope{models.ApiKeyScopeMfa}},
+	}}
+
+	// when
+	changes, err := ts.provisionSecrets(dto, true)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"rotate api key 'backend'", "update scopes of api key 'backend'"}, changes)
+	assert.Empty(t, secretsPersister.updated)
+	assert.Nil(t, secretsPersister.replaced)
+	assert.Equal(t, crypto.HashSecret(testApiKey), ts.tenant.Config.Secrets[0].Key)
+}
+
+func TestProvisionLocksTenantBeforeReadingIt(t *testing.T) {
+	// given
+	tenantPersister := &testProvisioningTenantPersister{}
+	ts := &tenantService{logger: echo.New().Logger, tenantPersister: tenantPersister}
+
+	// when
+	_, err := ts.Provision(request.ProvisionTenantDto{Id: uuid.Must(uuid.NewV4())}, false)
+
+	// then
+	assert.Error(t, err)
+	assert.Equal(t, []string{"lock", "get"}, tenantPersister.calls)
+}
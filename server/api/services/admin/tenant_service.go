@@ -1,15 +1,20 @@
 package admin
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/gofrs/uuid"
 	"github.com/labstack/echo/v4"
 	"github.com/teamhanko/passkey-server/api/dto/admin/request"
 	"github.com/teamhanko/passkey-server/api/dto/admin/response"
+	"github.com/teamhanko/passkey-server/api/validators"
 	"github.com/teamhanko/passkey-server/crypto"
 	hankoJwk "github.com/teamhanko/passkey-server/crypto/jwk"
 	"github.com/teamhanko/passkey-server/persistence/models"
 	"github.com/teamhanko/passkey-server/persistence/persisters"
+	"net/http"
+	"strings"
 	"time"
 )
 
@@ -17,57 +22,104 @@ type TenantService interface {
 	List() (*response.ListTenantResponses, error)
 	Create(dto request.CreateTenantDto) (*response.CreateTenantResponse, error)
 	Update(dto request.UpdateTenantDto) error
-	UpdateConfig(dto request.UpdateConfigDto) error
+	// GetConfig returns the config in the format of the update request together with its etag
+	GetConfig() (*request.UpdateConfigDto, string, error)
+	// UpdateConfig replaces the config and returns the new etag. The config must still match the etag of ifMatch.
+	UpdateConfig(dto request.UpdateConfigDto, ifMatch string) (string, error)
+	// PatchConfig applies a JSON merge patch to the current config, which must still match the etag of ifMatch, and
+	// returns the new etag
+	PatchConfig(patch []byte, ifMatch string) (string, error)
+	ValidateConfig(dto request.UpdateConfigDto) response.ValidateConfigResponse
+	ListConfigVersions() (response.ListConfigVersionsResponse, error)
+	GetConfigVersion(dto request.GetConfigVersionDto) (*response.GetConfigVersionDetailResponse, error)
+	DiffConfigVersions(dto request.DiffConfigVersionsDto) (*response.ConfigDiffResponse, error)
+	// RollbackConfig stores the config of an earlier version as new version
+	RollbackConfig(dto request.GetConfigVersionDto, ifMatch string) (string, error)
 	ListAuditLogs(dto request.ListAuditLogDto) (models.AuditLogs, int, error)
+	// Provision creates the tenant or updates it to match the dto and returns the changes. With dryRun the changes
+	// are only planned.
+	Provision(dto request.ProvisionTenantDto, dryRun bool) ([]string, error)
 }
 
 type tenantService struct {
 	logger echo.Logger
 	tenant *models.Tenant
-
-	tenantPersister         persisters.TenantPersister
-	configPersister         persisters.ConfigPersister
-	corsPersister           persisters.CorsPersister
-	webauthnConfigPersister persisters.WebauthnConfigPersister
-	relyingPartyPerister    persisters.WebauthnRelyingPartyPersister
-	auditConfigPersister    persisters.AuditLogConfigPersister
-	secretPersister         persisters.SecretsPersister
-	jwkPersister            persisters.JwkPersister
-	auditLogPersister       persisters.AuditLogPersister
-	mfaConfigPersister      persisters.MFAConfigPersister
+	actor  string
+
+	tenantPersister               persisters.TenantPersister
+	configPersister               persisters.ConfigPersister
+	corsPersister                 persisters.CorsPersister
+	webauthnConfigPersister       persisters.WebauthnConfigPersister
+	relyingPartyPerister          persisters.WebauthnRelyingPartyPersister
+	auditConfigPersister          persisters.AuditLogConfigPersister
+	secretPersister               persisters.SecretsPersister
+	jwkPersister                  persisters.JwkPersister
+	auditLogPersister             persisters.AuditLogPersister
+	mfaConfigPersister            persisters.MFAConfigPersister
+	jwtConfigPersister            persisters.JwtConfigPersister
+	oidcClientPersister           persisters.OidcClientPersister
+	aaguidPolicyPersister         persisters.AaguidPolicyPersister
+	backupPolicyPersister         persisters.BackupPolicyPersister
+	ceremonyConfigPersister       persisters.WebauthnCeremonyConfigPersister
+	registrationOverridePersister persisters.RegistrationOverridePersister
+	configVersionPersister        persisters.ConfigVersionPersister
 }
 
 type CreateTenantServiceParams struct {
-	Ctx    echo.Context
+	Ctx echo.Context
+	// Logger is used when the service runs outside of a request, e.g. when provisioning tenants on startup
+	Logger echo.Logger
 	Tenant *models.Tenant
-
-	TenantPersister         persisters.TenantPersister
-	ConfigPersister         persisters.ConfigPersister
-	CorsPersister           persisters.CorsPersister
-	WebauthnConfigPersister persisters.WebauthnConfigPersister
-	RelyingPartyPerister    persisters.WebauthnRelyingPartyPersister
-	AuditConfigPersister    persisters.AuditLogConfigPersister
-	SecretPersister         persisters.SecretsPersister
-	JwkPersister            persisters.JwkPersister
-	AuditLogPersister       persisters.AuditLogPersister
-	MFAConfigPersister      persisters.MFAConfigPersister
+	// Actor is the subject of the admin principal, it is stored with every config version
+	Actor string
+
+	TenantPersister               persisters.TenantPersister
+	ConfigPersister               persisters.ConfigPersister
+	CorsPersister                 persisters.CorsPersister
+	WebauthnConfigPersister       persisters.WebauthnConfigPersister
+	RelyingPartyPerister          persisters.WebauthnRelyingPartyPersister
+	AuditConfigPersister          persisters.AuditLogConfigPersister
+	SecretPersister               persisters.SecretsPersister
+	JwkPersister                  persisters.JwkPersister
+	AuditLogPersister             persisters.AuditLogPersister
+	MFAConfigPersister            persisters.MFAConfigPersister
+	JwtConfigPersister            persisters.JwtConfigPersister
+	OidcClientPersister           persisters.OidcClientPersister
+	AaguidPolicyPersister         persisters.AaguidPolicyPersister
+	BackupPolicyPersister         persisters.BackupPolicyPersister
+	CeremonyConfigPersister       persisters.WebauthnCeremonyConfigPersister
+	RegistrationOverridePersister persisters.RegistrationOverridePersister
+	ConfigVersionPersister        persisters.ConfigVersionPersister
 }
 
 func NewTenantService(params CreateTenantServiceParams) TenantService {
+	logger := params.Logger
+	if params.Ctx != nil {
+		logger = params.Ctx.Logger()
+	}
+
 	return &tenantService{
-		logger: params.Ctx.Logger(),
+		logger: logger,
 		tenant: params.Tenant,
-
-		tenantPersister:         params.TenantPersister,
-		configPersister:         params.ConfigPersister,
-		corsPersister:           params.CorsPersister,
-		webauthnConfigPersister: params.WebauthnConfigPersister,
-		relyingPartyPerister:    params.RelyingPartyPerister,
-		auditConfigPersister:    params.AuditConfigPersister,
-		secretPersister:         params.SecretPersister,
-		jwkPersister:            params.JwkPersister,
-		auditLogPersister:       params.AuditLogPersister,
-		mfaConfigPersister:      params.MFAConfigPersister,
+		actor:  params.Actor,
+
+		tenantPersister:               params.TenantPersister,
+		configPersister:               params.ConfigPersister,
+		corsPersister:                 params.CorsPersister,
+		webauthnConfigPersister:       params.WebauthnConfigPersister,
+		relyingPartyPerister:          params.RelyingPartyPerister,
+		auditConfigPersister:          params.AuditConfigPersister,
+		secretPersister:               params.SecretPersister,
+		jwkPersister:                  params.JwkPersister,
+		auditLogPersister:             params.AuditLogPersister,
+		mfaConfigPersister:            params.MFAConfigPersister,
+		jwtConfigPersister:            params.JwtConfigPersister,
+		oidcClientPersister:           params.OidcClientPersister,
+		aaguidPolicyPersister:         params.AaguidPolicyPersister,
+		backupPolicyPersister:         params.BackupPolicyPersister,
+		ceremonyConfigPersister:       params.CeremonyConfigPersister,
+		registrationOverridePersister: params.RegistrationOverridePersister,
+		configVersionPersister:        params.ConfigVersionPersister,
 	}
 }
 
@@ -88,84 +140,140 @@ func (ts *tenantService) List() (*response.ListTenantResponses, error) {
 }
 
 func (ts *tenantService) Create(dto request.CreateTenantDto) (*response.CreateTenantResponse, error) {
-	// transform dto to model
-	tenantModel := dto.ToModel()
-	configModel := dto.Config.ToModel(tenantModel)
-	corsModel := dto.Config.Cors.ToModel(configModel)
-	passkeyConfigModel := dto.Config.Passkey.ToModel(configModel)
-	relyingPartyModel := dto.Config.Passkey.RelyingParty.ToModel(passkeyConfigModel)
-
-	var mfaConfigModel models.MfaConfig
-	if dto.Config.Mfa == nil {
-		mfaConfigModel = dto.Config.Passkey.ToMfaModel(configModel)
-	} else {
-		mfaConfigModel = dto.Config.Mfa.ToModel(configModel)
+	err := ts.checkConfig(dto.Config)
+	if err != nil {
+		return nil, err
 	}
 
-	err := ts.tenantPersister.Create(&tenantModel)
+	tenantModel := dto.ToModel()
+	configModel, jwtConfigModel, err := ts.createTenant(&tenantModel, dto.Config)
 	if err != nil {
-		ts.logger.Error(err)
 		return nil, err
 	}
 
-	err = ts.persistConfig(
-		&configModel,
-		&corsModel,
-		&passkeyConfigModel,
-		&relyingPartyModel,
-		&mfaConfigModel,
-	)
-
 	var apiSecretModel *models.Secret = nil
+	var apiKey string
 	if dto.CreateApiKey {
-		apiSecretModel, err = ts.createSecret("Initial API Key", configModel.ID, true)
+		apiSecretModel, apiKey, err = ts.createSecret("Initial API Key", configModel.ID, true)
 		if err != nil {
 			ts.logger.Error(err)
 			return nil, fmt.Errorf("unable to create new api key: %w", err)
 		}
 	}
 
-	jwkSecretModel, err := ts.createSecret("Initial JWK Key", configModel.ID, false)
+	jwkSecretModel, _, err := ts.createSecret("Initial JWK Key", configModel.ID, false)
 	if err != nil {
 		ts.logger.Error(err)
 		return nil, fmt.Errorf("unable to create new jwk key: %w", err)
 	}
 
 	jwks := []string{jwkSecretModel.Key}
-	_, err = hankoJwk.NewDefaultManager(jwks, tenantModel.ID, ts.jwkPersister)
+	_, err = hankoJwk.NewDefaultManager(jwks, tenantModel.ID, jwtConfigModel.SigningAlgorithm, ts.jwkPersister)
 	if err != nil {
 		ts.logger.Error(err)
 		return nil, fmt.Errorf("unable to initialize jwt generator: %w", err)
 	}
 
-	createResponse := response.ToCreateTenantResponse(&tenantModel, apiSecretModel)
+	createResponse := response.ToCreateTenantResponse(&tenantModel, apiSecretModel, apiKey)
 
 	return &createResponse, nil
 }
 
-func (ts *tenantService) createSecret(name string, configId uuid.UUID, isAPIKey bool) (*models.Secret, error) {
-	secretId, err := uuid.NewV4()
+// createTenant stores the tenant together with its config and the first config version
+func (ts *tenantService) createTenant(tenantModel *models.Tenant, dto request.CreateConfigDto) (*models.Config, *models.JwtConfig, error) {
+	// transform dto to model
+	configModel := dto.ToModel(*tenantModel)
+	corsModel := dto.Cors.ToModel(configModel)
+	passkeyConfigModel := dto.Passkey.ToModel(configModel)
+	relyingPartyModel := dto.Passkey.RelyingParty.ToModel(passkeyConfigModel)
+
+	var mfaConfigModel models.MfaConfig
+	if dto.Mfa == nil {
+		mfaConfigModel = dto.Passkey.ToMfaModel(configModel)
+	} else {
+		mfaConfigModel = dto.Mfa.ToModel(configModel)
+	}
+
+	jwtConfigModel := dto.Jwt.ToModel(configModel)
+	oidcClientModels := dto.Oidc.ToModel(configModel)
+	aaguidPolicyModels := dto.Aaguid.ToModel(configModel)
+	backupPolicyModels := dto.Backup.ToModel(configModel)
+	ceremonyConfigModels := dto.Passkey.Ceremonies.ToModel(configModel)
+	registrationOverrideModels := dto.Passkey.RegistrationOverrides.ToModel(configModel)
+
+	err := ts.tenantPersister.Create(tenantModel)
 	if err != nil {
-		return nil, fmt.Errorf("unable to create id for a new key: %w", err)
+		ts.logger.Error(err)
+		return nil, nil, err
 	}
 
+	err = ts.persistConfig(
+		&configModel,
+		&corsModel,
+		&passkeyConfigModel,
+		&relyingPartyModel,
+		&mfaConfigModel,
+		&jwtConfigModel,
+		oidcClientModels,
+		aaguidPolicyModels,
+		backupPolicyModels,
+		ceremonyConfigModels,
+		registrationOverrideModels,
+	)
+
+	if err != nil {
+		ts.logger.Error(err)
+		return nil, nil, err
+	}
+
+	_, err = ts.storeConfigVersion(tenantModel.ID, dto, 1, &ts.actor, nil)
+	if err != nil {
+		ts.logger.Error(err)
+		return nil, nil, err
+	}
+
+	return &configModel, &jwtConfigModel, nil
+}
+
+func (ts *tenantService) createSecret(name string, configId uuid.UUID, isAPIKey bool) (*models.Secret, string, error) {
 	secretKey, err := crypto.GenerateRandomStringURLSafe(64)
 	if err != nil {
-		return nil, fmt.Errorf("unable to create key: %w", err)
+		return nil, "", fmt.Errorf("unable to create key: %w", err)
+	}
+
+	model, err := ts.storeSecret(request.ProvisionSecretDto{Name: name, Key: secretKey}, configId, isAPIKey)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return model, secretKey, nil
+}
+
+// storeSecret stores the key as new secret. API keys are only stored as hash and get the scopes and expiry of the dto.
+func (ts *tenantService) storeSecret(dto request.ProvisionSecretDto, configId uuid.UUID, isAPIKey bool) (*models.Secret, error) {
+	secretId, err := uuid.NewV4()
+	if err != nil {
+		return nil, fmt.Errorf("unable to create id for a new key: %w", err)
 	}
 
 	now := time.Now()
 
 	model := &models.Secret{
 		ID:          secretId,
-		Name:        name,
-		Key:         secretKey,
+		Name:        dto.Name,
+		Key:         dto.Key,
 		ConfigID:    configId,
 		IsAPISecret: isAPIKey,
 		CreatedAt:   now,
 		UpdatedAt:   now,
 	}
 
+	if isAPIKey {
+		model.Key = crypto.HashSecret(dto.Key)
+		model.Scopes = models.NewSecretScopes(secretId, dto.GetScopes())
+		model.ExpiresAt = dto.ExpiresAt
+	}
+
 	err = ts.secretPersister.Create(model)
 	if err != nil {
 		return nil, err
@@ -174,7 +282,7 @@ func (ts *tenantService) createSecret(name string, configId uuid.UUID, isAPIKey
 	return model, nil
 }
 
-func (ts *tenantService) persistConfig(config *models.Config, cors *models.Cors, webauthn *models.WebauthnConfig, rp *models.RelyingParty, mfaConfig *models.MfaConfig) error {
+func (ts *tenantService) persistConfig(config *models.Config, cors *models.Cors, webauthn *models.WebauthnConfig, rp *models.RelyingParty, mfaConfig *models.MfaConfig, jwtConfig *models.JwtConfig, oidcClients models.OidcClients, aaguidPolicies models.AaguidPolicies, backupPolicies models.BackupPolicies, ceremonyConfigs models.WebauthnCeremonyConfigs, registrationOverrides models.RegistrationOverrides) error {
 	err := ts.configPersister.Create(config)
 	if err != nil {
 		return err
@@ -200,6 +308,46 @@ func (ts *tenantService) persistConfig(config *models.Config, cors *models.Cors,
 		return err
 	}
 
+	err = ts.jwtConfigPersister.Create(jwtConfig)
+	if err != nil {
+		return err
+	}
+
+	for i := range oidcClients {
+		err = ts.oidcClientPersister.Create(&oidcClients[i])
+		if err != nil {
+			return err
+		}
+	}
+
+	for i := range aaguidPolicies {
+		err = ts.aaguidPolicyPersister.Create(&aaguidPolicies[i])
+		if err != nil {
+			return err
+		}
+	}
+
+	for i := range backupPolicies {
+		err = ts.backupPolicyPersister.Create(&backupPolicies[i])
+		if err != nil {
+			return err
+		}
+	}
+
+	for i := range ceremonyConfigs {
+		err = ts.ceremonyConfigPersister.Create(&ceremonyConfigs[i])
+		if err != nil {
+			return err
+		}
+	}
+
+	for i := range registrationOverrides {
+		err = ts.registrationOverridePersister.Create(&registrationOverrides[i])
+		if err != nil {
+			return err
+		}
+	}
+
 	err = ts.auditConfigPersister.Create(&config.AuditLogConfig)
 	if err != nil {
 		return err
@@ -221,7 +369,88 @@ func (ts *tenantService) Update(dto request.UpdateTenantDto) error {
 	return nil
 }
 
-func (ts *tenantService) UpdateConfig(dto request.UpdateConfigDto) error {
+func (ts *tenantService) GetConfig() (*request.UpdateConfigDto, string, error) {
+	latestVersion, err := ts.configVersionPersister.GetLatest(ts.tenant.ID)
+	if err != nil {
+		ts.logger.Error(err)
+		return nil, "", fmt.Errorf("failed to get config: %w", err)
+	}
+
+	var configDto request.CreateConfigDto
+	if latestVersion == nil {
+		configDto, err = ts.convertCurrentConfig()
+	} else {
+		err = json.Unmarshal([]byte(latestVersion.Config), &configDto)
+	}
+
+	if err != nil {
+		ts.logger.Error(err)
+		return nil, "", fmt.Errorf("unable to read config: %w", err)
+	}
+
+	return &request.UpdateConfigDto{CreateConfigDto: configDto}, toConfigETag(latestVersion), nil
+}
+
+func (ts *tenantService) UpdateConfig(dto request.UpdateConfigDto, ifMatch string) (string, error) {
+	err := ts.checkConfigETag(ifMatch)
+	if err != nil {
+		return "", err
+	}
+
+	return ts.updateConfig(dto.CreateConfigDto, nil)
+}
+
+func (ts *tenantService) PatchConfig(patch []byte, ifMatch string) (string, error) {
+	err := ts.checkConfigETag(ifMatch)
+	if err != nil {
+		return "", err
+	}
+
+	currentConfig, _, err := ts.GetConfig()
+	if err != nil {
+		return "", err
+	}
+
+	document, err := json.Marshal(currentConfig)
+	if err != nil {
+		ts.logger.Error(err)
+		return "", fmt.Errorf("unable to marshal config: %w", err)
+	}
+
+	patchedDocument, err := mergePatch(document, patch)
+	if err != nil {
+		return "", echo.NewHTTPError(http.StatusBadRequest, "invalid merge patch").SetInternal(err)
+	}
+
+	var patchedConfig request.UpdateConfigDto
+	err = json.Unmarshal(patchedDocument, &patchedConfig)
+	if err != nil {
+		return "", echo.NewHTTPError(http.StatusBadRequest, "patched config is invalid").SetInternal(err)
+	}
+
+	err = validators.NewCustomValidator().Validate(&patchedConfig)
+	if err != nil {
+		return "", echo.NewHTTPError(http.StatusBadRequest, "unable to patch tenant config").SetInternal(err)
+	}
+
+	// the etag was already checked and the tenant is still locked
+	return ts.updateConfig(patchedConfig.CreateConfigDto, nil)
+}
+
+// updateConfig replaces the config of the tenant and stores it as new version. It has to be called within a
+// transaction after lockTenant, so the config and its version are stored together and the replaced config is current.
+func (ts *tenantService) updateConfig(dto request.CreateConfigDto, rollbackOf *int) (string, error) {
+	err := ts.checkConfig(dto)
+	if err != nil {
+		return "", err
+	}
+
+	latestVersion, err := ts.getLatestConfigVersion()
+	if err != nil {
+		ts.logger.Error(err)
+		return "", err
+	}
+
 	config := ts.tenant.Config
 	newConfig := dto.ToModel(*ts.tenant)
 	corsModel := dto.Cors.ToModel(newConfig)
@@ -235,17 +464,30 @@ func (ts *tenantService) UpdateConfig(dto request.UpdateConfigDto) error {
 		mfaConfigModel = dto.Mfa.ToModel(newConfig)
 	}
 
-	err := ts.persistConfig(
+	jwtConfigModel := dto.Jwt.ToModel(newConfig)
+	oidcClientModels := dto.Oidc.ToModel(newConfig)
+	aaguidPolicyModels := dto.Aaguid.ToModel(newConfig)
+	backupPolicyModels := dto.Backup.ToModel(newConfig)
+	ceremonyConfigModels := dto.Passkey.Ceremonies.ToModel(newConfig)
+	registrationOverrideModels := dto.Passkey.RegistrationOverrides.ToModel(newConfig)
+
+	err = ts.persistConfig(
 		&newConfig,
 		&corsModel,
 		&webauthnConfigModel,
 		&relyingPartyModel,
 		&mfaConfigModel,
+		&jwtConfigModel,
+		oidcClientModels,
+		aaguidPolicyModels,
+		backupPolicyModels,
+		ceremonyConfigModels,
+		registrationOverrideModels,
 	)
 
 	if err != nil {
 		ts.logger.Error(err)
-		return err
+		return "", err
 	}
 
 	for _, secret := range config.Secrets {
@@ -254,19 +496,277 @@ func (ts *tenantService) UpdateConfig(dto request.UpdateConfigDto) error {
 		err = ts.secretPersister.Update(&s)
 		if err != nil {
 			ts.logger.Error(err)
-			return err
+			return "", err
 		}
 	}
 
 	err = ts.configPersister.Delete(&config)
 	if err != nil {
 		ts.logger.Error(err)
+		return "", err
+	}
+
+	newVersion, err := ts.storeConfigVersion(ts.tenant.ID, dto, latestVersion.Version+1, &ts.actor, rollbackOf)
+	if err != nil && persisters.IsUniqueViolation(err) {
+		return "", echo.NewHTTPError(http.StatusPreconditionFailed, "config was changed in the meantime").SetInternal(err)
+	}
+
+	if err != nil {
+		ts.logger.Error(err)
+		return "", err
+	}
+
+	return toConfigETag(newVersion), nil
+}
+
+// checkConfigETag answers with 428 without etag and with 412 if the config was changed since the client read the etag.
+// The tenant stays locked until the end of the transaction, so concurrent writers with the same etag are serialized.
+func (ts *tenantService) checkConfigETag(ifMatch string) error {
+	if strings.TrimSpace(ifMatch) == "" {
+		return echo.NewHTTPError(http.StatusPreconditionRequired, "the If-Match header is required to change the tenant config")
+	}
+
+	err := ts.lockTenant()
+	if err != nil {
 		return err
 	}
 
+	latestVersion, err := ts.configVersionPersister.GetLatest(ts.tenant.ID)
+	if err != nil {
+		ts.logger.Error(err)
+		return fmt.Errorf("failed to get latest config version: %w", err)
+	}
+
+	currentETag := toConfigETag(latestVersion)
+	for _, eTag := range strings.Split(ifMatch, ",") {
+		eTag = strings.TrimSpace(eTag)
+		if eTag == "*" || eTag == currentETag {
+			return nil
+		}
+	}
+
+	return echo.NewHTTPError(http.StatusPreconditionFailed, "config was changed in the meantime").SetInternal(fmt.Errorf("if-match '%s' does not match etag %s", ifMatch, currentETag))
+}
+
+// lockTenant locks the tenant until the end of the transaction and reloads it. The tenant of the request was read
+// before the lock was taken, so its config may already have been replaced by a concurrent writer.
+func (ts *tenantService) lockTenant() error {
+	err := ts.tenantPersister.Lock(ts.tenant.ID)
+	if err != nil {
+		ts.logger.Error(err)
+		return err
+	}
+
+	tenant, err := ts.tenantPersister.Get(ts.tenant.ID)
+	if err != nil {
+		ts.logger.Error(err)
+		return fmt.Errorf("failed to get tenant: %w", err)
+	}
+
+	if tenant == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "tenant not found")
+	}
+
+	ts.tenant = tenant
+
+	return nil
+}
+
+// toConfigETag returns the etag of a config version. Configs which were not changed since the versioning have no
+// version yet and use version 0.
+func toConfigETag(version *models.ConfigVersion) string {
+	if version == nil {
+		return `"0"`
+	}
+
+	return fmt.Sprintf(`"%d"`, version.Version)
+}
+
+func (ts *tenantService) ValidateConfig(dto request.UpdateConfigDto) response.ValidateConfigResponse {
+	return response.ToValidateConfigResponse(LintConfig(dto.CreateConfigDto))
+}
+
+// checkConfig rejects configs with blocking findings and logs the warnings of all others. Rejections answer with the
+// findings in the same format as the validate endpoint.
+func (ts *tenantService) checkConfig(dto request.CreateConfigDto) error {
+	findings := LintConfig(dto)
+	if findings.HasErrors() {
+		return echo.NewHTTPError(http.StatusBadRequest, response.ToValidateConfigResponse(findings)).SetInternal(errors.New(findings.Errors()))
+	}
+
+	for _, finding := range findings {
+		ts.logger.Warnf("%s: %s", finding.Field, finding.Message)
+	}
+
 	return nil
 }
 
+func (ts *tenantService) ListConfigVersions() (response.ListConfigVersionsResponse, error) {
+	versions, err := ts.configVersionPersister.List(ts.tenant.ID)
+	if err != nil {
+		ts.logger.Error(err)
+		return nil, fmt.Errorf("failed to list config versions: %w", err)
+	}
+
+	list := make(response.ListConfigVersionsResponse, 0)
+	for i := range versions {
+		list = append(list, response.ToGetConfigVersionResponse(&versions[i]))
+	}
+
+	return list, nil
+}
+
+func (ts *tenantService) GetConfigVersion(dto request.GetConfigVersionDto) (*response.GetConfigVersionDetailResponse, error) {
+	version, err := ts.getConfigVersion(dto.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	versionResponse := response.ToGetConfigVersionDetailResponse(version)
+
+	return &versionResponse, nil
+}
+
+func (ts *tenantService) DiffConfigVersions(dto request.DiffConfigVersionsDto) (*response.ConfigDiffResponse, error) {
+	fromVersion, err := ts.getConfigVersion(dto.From)
+	if err != nil {
+		return nil, err
+	}
+
+	toVersion, err := ts.getConfigVersion(dto.To)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := diffConfigs(fromVersion.Config, toVersion.Config)
+	if err != nil {
+		ts.logger.Error(err)
+		return nil, err
+	}
+
+	return &response.ConfigDiffResponse{
+		From:    fromVersion.Version,
+		To:      toVersion.Version,
+		Changes: changes,
+	}, nil
+}
+
+func (ts *tenantService) RollbackConfig(dto request.GetConfigVersionDto, ifMatch string) (string, error) {
+	err := ts.checkConfigETag(ifMatch)
+	if err != nil {
+		return "", err
+	}
+
+	version, err := ts.getConfigVersion(dto.Version)
+	if err != nil {
+		return "", err
+	}
+
+	var configDto request.CreateConfigDto
+	err = json.Unmarshal([]byte(version.Config), &configDto)
+	if err != nil {
+		ts.logger.Error(err)
+		return "", fmt.Errorf("unable to parse config of version %d: %w", version.Version, err)
+	}
+
+	return ts.updateConfig(configDto, &version.Version)
+}
+
+func (ts *tenantService) getConfigVersion(version int) (*models.ConfigVersion, error) {
+	configVersion, err := ts.configVersionPersister.Get(ts.tenant.ID, version)
+	if err != nil {
+		ts.logger.Error(err)
+		return nil, fmt.Errorf("failed to get config version: %w", err)
+	}
+
+	if configVersion == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("config version %d not found", version))
+	}
+
+	return configVersion, nil
+}
+
+// getLatestConfigVersion returns the latest version of the tenant config. Configs which were stored before the
+// versioning get their current state as first version without actor, so it can be restored later on.
+func (ts *tenantService) getLatestConfigVersion() (*models.ConfigVersion, error) {
+	latestVersion, err := ts.configVersionPersister.GetLatest(ts.tenant.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if latestVersion != nil {
+		return latestVersion, nil
+	}
+
+	configDto, err := ts.convertCurrentConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return ts.storeConfigVersion(ts.tenant.ID, configDto, 1, nil, nil)
+}
+
+// convertCurrentConfig converts the stored config of the tenant to the format of the update request
+func (ts *tenantService) convertCurrentConfig() (request.CreateConfigDto, error) {
+	config := ts.tenant.Config
+	hasMfaConfig := config.MfaConfig != nil
+	if !hasMfaConfig {
+		config.MfaConfig = &models.MfaConfig{}
+	}
+
+	var configDto request.CreateConfigDto
+	err := convertConfig(response.ToGetConfigResponse(&config), &configDto)
+	if err != nil {
+		return configDto, fmt.Errorf("unable to convert current config: %w", err)
+	}
+
+	// without a stored mfa config the mfa settings are derived from the webauthn config
+	if !hasMfaConfig {
+		configDto.Mfa = nil
+	}
+
+	return configDto, nil
+}
+
+func (ts *tenantService) storeConfigVersion(tenantId uuid.UUID, dto request.CreateConfigDto, version int, actor *string, rollbackOf *int) (*models.ConfigVersion, error) {
+	config, err := json.Marshal(dto)
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal config: %w", err)
+	}
+
+	versionId, err := uuid.NewV4()
+	if err != nil {
+		return nil, fmt.Errorf("unable to create id for a new config version: %w", err)
+	}
+
+	configVersion := &models.ConfigVersion{
+		ID:         versionId,
+		Version:    version,
+		Config:     string(config),
+		Actor:      actor,
+		RollbackOf: rollbackOf,
+		TenantID:   tenantId,
+		CreatedAt:  time.Now(),
+	}
+
+	err = ts.configVersionPersister.Create(configVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	return configVersion, nil
+}
+
+// convertConfig converts between the config request and response, which share the same json format
+func convertConfig(from interface{}, to interface{}) error {
+	data, err := json.Marshal(from)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, to)
+}
+
 func (ts *tenantService) ListAuditLogs(dto request.ListAuditLogDto) (models.AuditLogs, int, error) {
 	options := persisters.AuditLogOptions{
 		Page:     dto.Page,
@@ -0,0 +1,113 @@
+package admin
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/gofrs/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/teamhanko/passkey-server/persistence/models"
+	"github.com/teamhanko/passkey-server/persistence/persisters"
+)
+
+type testTenantPersister struct {
+	persisters.TenantPersister
+	locked   bool
+	reloaded bool
+}
+
+func (p *testTenantPersister) Lock(_ uuid.UUID) error {
+	p.locked = true
+	return nil
+}
+
+func (p *testTenantPersister) Get(tenantId uuid.UUID) (*models.Tenant, error) {
+	if !p.locked {
+		return nil, errors.New("tenant was read before it was locked")
+	}
+
+	p.reloaded = true
+
+	return &models.Tenant{ID: tenantId, DisplayName: "reloaded"}, nil
+}
+
+type testConfigVersionPersister struct {
+	persisters.ConfigVersionPersister
+	latest *models.ConfigVersion
+}
+
+func (p *testConfigVersionPersister) GetLatest(_ uuid.UUID) (*models.ConfigVersion, error) {
+	return p.latest, nil
+}
+
+func newTestETagService() (*tenantService, *testTenantPersister) {
+	tenantPersister := &testTenantPersister{}
+
+	return &tenantService{
+		logger:                 echo.New().Logger,
+		tenant:                 &models.Tenant{ID: uuid.Must(uuid.NewV4())},
+		tenantPersister:        tenantPersister,
+		configVersionPersister: &testConfigVersionPersister{latest: &models.ConfigVersion{Version: 3}},
+	}, tenantPersister
+}
+
+func assertHTTPStatus(t *testing.T, status int, err error) {
+	var httpError *echo.HTTPError
+	assert.True(t, errors.As(err, &httpError))
+	assert.Equal(t, status, httpError.Code)
+}
+
+func TestCheckConfigETagRequiresIfMatch(t *testing.T) {
+	// given
+	ts, tenantPersister := newTestETagService()
+
+	// when
+	err := ts.checkConfigETag("")
+
+	// then
+	assertHTTPStatus(t, http.StatusPreconditionRequired, err)
+	assert.False(t, tenantPersister.locked)
+}
+
+func TestCheckConfigETagRejectsOutdatedETag(t *testing.T) {
+	// given
+	ts, tenantPersister := newTestETagService()
+
+	// when
+	err := ts.checkConfigETag(`"2"`)
+
+	// then
+	assertHTTPStatus(t, http.StatusPreconditionFailed, err)
+	assert.True(t, tenantPersister.locked)
+}
+
+func TestCheckConfigETagAcceptsCurrentETag(t *testing.T) {
+	for _, ifMatch := range []string{`"3"`, `"2", "3"`, "*"} {
+		// given
+		ts, tenantPersister := newTestETagService()
+
+		// when
+		err := ts.checkConfigETag(ifMatch)
+
+		// then
+		assert.NoError(t, err, ifMatch)
+		assert.True(t, tenantPersister.locked)
+	}
+}
+
+func TestCheckConfigETagReloadsTenantAfterLock(t *testing.T) {
+	// given
+	ts, tenantPersister := newTestETagService()
+	tenantId := ts.tenant.ID
+
+	// when
+	err := ts.checkConfigETag("*")
+
+	// then
+	assert.NoError(t, err)
+	assert.True(t, tenantPersister.reloaded)
+	assert.Equal(t, tenantId, ts.tenant.ID)
+	assert.Equal(t, "reloaded", ts.tenant.DisplayName)
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/labstack/echo/v4"
 	"github.com/teamhanko/passkey-server/api/dto/admin/request"
 	"github.com/teamhanko/passkey-server/api/dto/admin/response"
+	"github.com/teamhanko/passkey-server/mapper"
 	"github.com/teamhanko/passkey-server/persistence/models"
 	"github.com/teamhanko/passkey-server/persistence/persisters"
 	"net/http"
@@ -21,19 +22,23 @@ type CreateUserServiceParams struct {
 	Tenant models.Tenant
 
 	UserPersister persisters.WebauthnUserPersister
+
+	AuthenticatorMetadata mapper.AuthenticatorMetadata
 }
 
 type userService struct {
-	ctx           echo.Context
-	tenant        models.Tenant
-	userPersister persisters.WebauthnUserPersister
+	ctx                   echo.Context
+	tenant                models.Tenant
+	userPersister         persisters.WebauthnUserPersister
+	authenticatorMetadata mapper.AuthenticatorMetadata
 }
 
 func NewUserService(params CreateUserServiceParams) UserService {
 	return &userService{
-		ctx:           params.Ctx,
-		tenant:        params.Tenant,
-		userPersister: params.UserPersister,
+		ctx:                   params.Ctx,
+		tenant:                params.Tenant,
+		userPersister:         params.UserPersister,
+		authenticatorMetadata: params.AuthenticatorMetadata,
 	}
 }
 
@@ -70,7 +75,7 @@ func (us *userService) Get(userId uuid.UUID) (*response.UserGetDto, error) {
 		return nil, echo.NewHTTPError(http.StatusNotFound, "user not found")
 	}
 
-	dto := response.UserGetDtoFromModel(*user)
+	dto := response.UserGetDtoFromModel(*user, us.authenticatorMetadata)
 	return &dto, nil
 }
 
@@ -0,0 +1,198 @@
+package admin
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/teamhanko/passkey-server/api/dto/admin/request"
+	"github.com/teamhanko/passkey-server/api/dto/admin/response"
+	"github.com/teamhanko/passkey-server/persistence/models"
+	"github.com/teamhanko/passkey-server/persistence/persisters"
+)
+
+type WebhookService interface {
+	List() (response.WebhookResponseListDto, error)
+	Get(dto request.GetWebhookDto) (*response.WebhookResponseDto, error)
+	Create(dto request.CreateWebhookDto) (*response.WebhookResponseDto, error)
+	Remove(dto request.GetWebhookDto) error
+	ListDeliveries(dto request.ListWebhookDeliveriesDto) (response.WebhookDeliveryResponseListDto, int, error)
+	// Redeliver queues a delivery again, regardless of its current status
+	Redeliver(dto request.RedeliverWebhookDto) (*response.WebhookDeliveryResponseDto, error)
+}
+
+type CreateWebhookServiceParams struct {
+	Ctx    echo.Context
+	Tenant models.Tenant
+
+	WebhookPersister         persisters.WebhookPersister
+	WebhookDeliveryPersister persisters.WebhookDeliveryPersister
+}
+
+type webhookService struct {
+	logger echo.Logger
+	tenant models.Tenant
+
+	webhookPersister         persisters.WebhookPersister
+	webhookDeliveryPersister persisters.WebhookDeliveryPersister
+}
+
+func NewWebhookService(params CreateWebhookServiceParams) WebhookService {
+	return &webhookService{
+		logger:                   params.Ctx.Logger(),
+		tenant:                   params.Tenant,
+		webhookPersister:         params.WebhookPersister,
+		webhookDeliveryPersister: params.WebhookDeliveryPersister,
+	}
+}
+
+func (ws *webhookService) List() (response.WebhookResponseListDto, error) {
+	webhooks, err := ws.webhookPersister.List(ws.tenant.ID)
+	if err != nil {
+		ws.logger.Error(err)
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "unable to list webhooks").SetInternal(err)
+	}
+
+	list := make(response.WebhookResponseListDto, 0)
+	for i := range webhooks {
+		list = append(list, *response.ToWebhookResponse(&webhooks[i]))
+	}
+
+	return list, nil
+}
+
+func (ws *webhookService) Get(dto request.GetWebhookDto) (*response.WebhookResponseDto, error) {
+	webhook, err := ws.getWebhook(dto.WebhookId)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.ToWebhookResponse(webhook), nil
+}
+
+func (ws *webhookService) Create(dto request.CreateWebhookDto) (*response.WebhookResponseDto, error) {
+	for _, event := range dto.Events {
+		if !models.IsValidAuditLogType(event) {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("unknown event '%s'", event))
+		}
+	}
+
+	webhook, err := dto.ToModel(ws.tenant)
+	if err != nil {
+		ws.logger.Error(err)
+		return nil, err
+	}
+
+	err = ws.webhookPersister.Create(webhook)
+	if err != nil {
+		ws.logger.Error(err)
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "unable to create webhook").SetInternal(err)
+	}
+
+	webhookDto := response.ToWebhookResponse(webhook)
+	webhookDto.Secret = webhook.Secret
+
+	return webhookDto, nil
+}
+
+func (ws *webhookService) Remove(dto request.GetWebhookDto) error {
+	webhook, err := ws.getWebhook(dto.WebhookId)
+	if err != nil {
+		return err
+	}
+
+	err = ws.webhookPersister.Delete(webhook)
+	if err != nil {
+		ws.logger.Error(err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "unable to delete webhook").SetInternal(err)
+	}
+
+	return nil
+}
+
+func (ws *webhookService) ListDeliveries(dto request.ListWebhookDeliveriesDto) (response.WebhookDeliveryResponseListDto, int, error) {
+	webhook, err := ws.getWebhook(dto.WebhookId)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	count, err := ws.webhookDeliveryPersister.Count(webhook.ID)
+	if err != nil {
+		ws.logger.Error(err)
+		return nil, 0, echo.NewHTTPError(http.StatusInternalServerError, "unable to count webhook deliveries").SetInternal(err)
+	}
+
+	deliveries, err := ws.webhookDeliveryPersister.List(webhook.ID, dto.Page, dto.PerPage)
+	if err != nil {
+		ws.logger.Error(err)
+		return nil, 0, echo.NewHTTPError(http.StatusInternalServerError, "unable to list webhook deliveries").SetInternal(err)
+	}
+
+	list := make(response.WebhookDeliveryResponseListDto, 0)
+	for i := range deliveries {
+		list = append(list, *response.ToWebhookDeliveryResponse(&deliveries[i]))
+	}
+
+	return list, count, nil
+}
+
+func (ws *webhookService) Redeliver(dto request.RedeliverWebhookDto) (*response.WebhookDeliveryResponseDto, error) {
+	webhook, err := ws.getWebhook(dto.WebhookId)
+	if err != nil {
+		return nil, err
+	}
+
+	deliveryId, err := uuid.FromString(dto.DeliveryId)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "invalid delivery_id").SetInternal(err)
+	}
+
+	delivery, err := ws.webhookDeliveryPersister.Get(webhook.ID, deliveryId)
+	if err != nil {
+		ws.logger.Error(err)
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "unable to get webhook delivery").SetInternal(err)
+	}
+
+	if delivery == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "webhook delivery not found")
+	}
+
+	if !webhook.Enabled {
+		return nil, echo.NewHTTPError(http.StatusConflict, "webhook is disabled")
+	}
+
+	now := time.Now().UTC()
+	delivery.Status = models.WebhookDeliveryStatusPending
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = now
+	delivery.UpdatedAt = now
+
+	err = ws.webhookDeliveryPersister.Update(delivery)
+	if err != nil {
+		ws.logger.Error(err)
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "unable to queue webhook delivery").SetInternal(err)
+	}
+
+	return response.ToWebhookDeliveryResponse(delivery), nil
+}
+
+func (ws *webhookService) getWebhook(webhookIdString string) (*models.Webhook, error) {
+	webhookId, err := uuid.FromString(webhookIdString)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "invalid webhook_id").SetInternal(err)
+	}
+
+	webhook, err := ws.webhookPersister.Get(ws.tenant.ID, webhookId)
+	if err != nil {
+		ws.logger.Error(err)
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "unable to get webhook").SetInternal(err)
+	}
+
+	if webhook == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "webhook not found")
+	}
+
+	return webhook, nil
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/labstack/echo/v4"
 	"github.com/teamhanko/passkey-server/api/dto/request"
 	"github.com/teamhanko/passkey-server/api/dto/response"
+	"github.com/teamhanko/passkey-server/mapper"
 	"github.com/teamhanko/passkey-server/persistence/models"
 	"github.com/teamhanko/passkey-server/persistence/persisters"
 )
@@ -20,15 +21,17 @@ type CredentialService interface {
 
 type credentialService struct {
 	*BaseService
+	mapper.AuthenticatorMetadata
 }
 
-func NewCredentialService(ctx echo.Context, tenant models.Tenant, credentialPersister persisters.WebauthnCredentialPersister) CredentialService {
+func NewCredentialService(ctx echo.Context, tenant models.Tenant, credentialPersister persisters.WebauthnCredentialPersister, authenticatorMetadata mapper.AuthenticatorMetadata) CredentialService {
 	return &credentialService{
 		&BaseService{
 			logger:              ctx.Logger(),
 			tenant:              tenant,
 			credentialPersister: credentialPersister,
 		},
+		authenticatorMetadata,
 	}
 }
 
@@ -41,7 +44,7 @@ func (cs *credentialService) List(dto request.ListCredentialsDto) (response.Cred
 
 	dtos := make(response.CredentialDtoList, len(credentialModels))
 	for i := range credentialModels {
-		dtos[i] = response.CredentialDtoFromModel(credentialModels[i])
+		dtos[i] = response.CredentialDtoFromModel(credentialModels[i], cs.AuthenticatorMetadata)
 	}
 
 	credentialsCount, err := cs.credentialPersister.Count(cs.tenant.ID, dto)
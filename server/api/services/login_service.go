@@ -1,24 +1,37 @@
 package services
 
 import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"github.com/go-webauthn/webauthn/protocol"
 	"github.com/go-webauthn/webauthn/webauthn"
 	"github.com/labstack/echo/v4"
 	"github.com/teamhanko/passkey-server/api/dto/intern"
 	"github.com/teamhanko/passkey-server/persistence/models"
+	"github.com/teamhanko/passkey-server/persistence/persisters"
 	"net/http"
 )
 
 type LoginService interface {
 	Initialize() (*protocol.CredentialAssertion, error)
 	Finalize(req *protocol.ParsedCredentialAssertionData) (string, string, error)
+	// Authenticate validates the assertion like Finalize but returns the used credential instead of a token
+	Authenticate(req *protocol.ParsedCredentialAssertionData) (*models.WebauthnCredential, string, error)
+	// UsedConditionalMediation returns true when the login was initialized with conditional mediation. It is only set
+	// for the initialization and after the session data was found on finalization.
+	UsedConditionalMediation() bool
 }
 
 type loginService struct {
 	WebauthnService
-	userId *string
+	tenantPersister persisters.TenantPersister
+	userId          *string
+	username        *string
+	conditional     bool
 }
 
 func NewLoginService(params WebauthnServiceCreateParams) LoginService {
@@ -32,12 +45,18 @@ func NewLoginService(params WebauthnServiceCreateParams) LoginService {
 			},
 			webauthnClient: params.WebauthnClient,
 			generator:      params.Generator,
+			auditLog:       params.AuditLog,
+			tx:             params.Tx,
 
-			userPersister:        params.UserPersister,
-			sessionDataPersister: params.SessionPersister,
-			useMFA:               params.UseMFA,
+			userPersister:              params.UserPersister,
+			sessionDataPersister:       params.SessionPersister,
+			credentialHistoryPersister: params.CredentialHistoryPersister,
+			useMFA:                     params.UseMFA,
 		},
+		params.TenantPersister,
 		params.UserId,
+		params.Username,
+		params.ConditionalMediation,
 	}
 }
 
@@ -47,6 +66,45 @@ func (ls *loginService) Initialize() (*protocol.CredentialAssertion, error) {
 	var err error
 	isDiscoverable := true
 
+	if ls.conditional && (ls.userId != nil || ls.username != nil) {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "conditional mediation is only available for discoverable logins")
+	}
+
+	// MFA logins keep the settings of the mfa config
+	var opts []webauthn.LoginOption
+	if !ls.useMFA {
+		opts = ls.getCeremonyLoginOptions(ls.getCeremony())
+	}
+
+	if ls.username != nil {
+		user, err := ls.userPersister.GetByName(*ls.username, ls.tenant.ID)
+		if err != nil && errors.Is(err, persisters.ErrAmbiguousName) {
+			return nil, echo.NewHTTPError(http.StatusConflict, "username is used by more than one user, login with the user id instead").SetInternal(err)
+		}
+
+		if err != nil {
+			ls.logger.Error(err)
+			return nil, err
+		}
+
+		preventEnumeration := ls.tenant.Config.WebauthnConfig.PreventUserEnumeration
+		if user == nil || (preventEnumeration && len(intern.NewWebauthnUser(*user, ls.useMFA).WebAuthnCredentials()) == 0) {
+			if !preventEnumeration {
+				return nil, echo.NewHTTPError(http.StatusNotFound, "user not found")
+			}
+
+			credentialAssertion, err = ls.createFakeAssertion(*ls.username, opts)
+			if err != nil {
+				return nil, err
+			}
+
+			removeTransports(credentialAssertion)
+			return credentialAssertion, nil
+		}
+
+		ls.userId = &user.UserID
+	}
+
 	if ls.userId != nil {
 		user, err := ls.getWebauthnUserByUserHandle(*ls.userId)
 		if err != nil {
@@ -55,7 +113,7 @@ func (ls *loginService) Initialize() (*protocol.CredentialAssertion, error) {
 			return nil, echo.NewHTTPError(http.StatusNotFound, err)
 		}
 
-		credentialAssertion, sessionData, err = ls.webauthnClient.BeginLogin(user)
+		credentialAssertion, sessionData, err = ls.webauthnClient.BeginLogin(user, opts...)
 		if err != nil {
 			ls.logger.Error(err)
 			return nil, echo.NewHTTPError(
@@ -66,7 +124,7 @@ func (ls *loginService) Initialize() (*protocol.CredentialAssertion, error) {
 
 		isDiscoverable = false
 	} else {
-		credentialAssertion, sessionData, err = ls.webauthnClient.BeginDiscoverableLogin()
+		credentialAssertion, sessionData, err = ls.webauthnClient.BeginDiscoverableLogin(opts...)
 
 		if err != nil {
 			ls.logger.Error(err)
@@ -77,29 +135,128 @@ func (ls *loginService) Initialize() (*protocol.CredentialAssertion, error) {
 		}
 	}
 
-	err = ls.sessionDataPersister.Create(*intern.WebauthnSessionDataToModel(sessionData, ls.tenant.ID, models.WebauthnOperationAuthentication, isDiscoverable))
+	sessionDataModel := intern.WebauthnSessionDataToModel(sessionData, ls.tenant.ID, models.WebauthnOperationAuthentication, isDiscoverable)
+	sessionDataModel.IsConditional = ls.conditional
+
+	err = ls.sessionDataPersister.Create(*sessionDataModel)
 	if err != nil {
 		ls.logger.Error(err)
 		return nil, err
 	}
 
-	// Remove all transports, because of a bug in android and windows where the internal authenticator gets triggered,
-	// when the transports array contains the type 'internal' although the credential is not available on the device.
+	removeTransports(credentialAssertion)
+	return credentialAssertion, nil
+}
+
+// removeTransports removes all transports, because of a bug in android and windows where the internal authenticator
+// gets triggered, when the transports array contains the type 'internal' although the credential is not available on
+// the device.
+func removeTransports(credentialAssertion *protocol.CredentialAssertion) {
 	for i := range credentialAssertion.Response.AllowedCredentials {
 		credentialAssertion.Response.AllowedCredentials[i].Transport = nil
 	}
+}
+
+// createFakeAssertion returns assertion options for credentials derived from the username, so unknown usernames can not
+// be distinguished from known ones. No session data is stored, therefore the login can never be finalized.
+func (ls *loginService) createFakeAssertion(username string, opts []webauthn.LoginOption) (*protocol.CredentialAssertion, error) {
+	key, err := ls.getUserEnumerationKey()
+	if err != nil {
+		ls.logger.Error(err)
+		return nil, err
+	}
+
+	user := &intern.WebauthnUser{
+		UserId:              username,
+		Name:                username,
+		DisplayName:         username,
+		WebauthnCredentials: createFakeCredentials(username, key),
+	}
+
+	credentialAssertion, _, err := ls.webauthnClient.BeginLogin(user, opts...)
+	if err != nil {
+		ls.logger.Error(err)
+		return nil, echo.NewHTTPError(
+			http.StatusInternalServerError,
+			fmt.Errorf("failed to create webauthn assertion options for login: %w", err),
+		)
+	}
 
 	return credentialAssertion, nil
 }
 
+// getUserEnumerationKey returns the key of the tenant for fake credentials and creates it on first use
+func (ls *loginService) getUserEnumerationKey() ([]byte, error) {
+	encodedKey := ls.tenant.UserEnumerationKey
+	if encodedKey == nil {
+		randomKey := make([]byte, 32)
+		_, err := rand.Read(randomKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create user enumeration key: %w", err)
+		}
+
+		storedKey, err := ls.tenantPersister.SetUserEnumerationKey(ls.tenant.ID, base64.RawURLEncoding.EncodeToString(randomKey))
+		if err != nil {
+			return nil, err
+		}
+
+		encodedKey = &storedKey
+	}
+
+	key, err := base64.RawURLEncoding.DecodeString(*encodedKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode user enumeration key: %w", err)
+	}
+
+	return key, nil
+}
+
+// createFakeCredentials derives one or two credentials from the username. They are keyed with the user enumeration
+// key of the tenant, so they stay the same for every request but can not be computed by a client.
+func createFakeCredentials(username string, key []byte) []models.WebauthnCredential {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(username))
+	seed := mac.Sum(nil)
+
+	credentials := make([]models.WebauthnCredential, 1+int(seed[0]%2))
+	for i := range credentials {
+		mac.Reset()
+		mac.Write(seed)
+		mac.Write([]byte{byte(i)})
+		id := mac.Sum(nil)
+
+		credentials[i].ID = base64.RawURLEncoding.EncodeToString(id)
+	}
+
+	return credentials
+}
+
 func (ls *loginService) Finalize(req *protocol.ParsedCredentialAssertionData) (string, string, error) {
+	dbCredential, userHandle, err := ls.Authenticate(req)
+	if err != nil {
+		return "", userHandle, err
+	}
+
+	token, err := ls.createUserCredentialToken(userHandle, dbCredential.ID)
+	if err != nil {
+		ls.logger.Error(err)
+		return "", userHandle, err
+	}
+
+	return token, userHandle, nil
+}
+
+func (ls *loginService) Authenticate(req *protocol.ParsedCredentialAssertionData) (*models.WebauthnCredential, string, error) {
 	// backward compatibility
 	userHandle := ls.convertUserHandle(req.Response.UserHandle)
 	sessionData, dbSessionData, err := ls.getSessionByChallenge(req.Response.CollectedClientData.Challenge, models.WebauthnOperationAuthentication)
 	if err != nil {
-		return "", userHandle, echo.NewHTTPError(http.StatusUnauthorized, "failed to get session data").SetInternal(err)
+		return nil, userHandle, echo.NewHTTPError(http.StatusUnauthorized, "failed to get session data").SetInternal(err)
 	}
 
+	// conditional sessions are finalized like every other discoverable login
+	ls.conditional = dbSessionData.IsConditional
+
 	// when using MFA or session was initialized for a non-discoverable cred
 	if ls.useMFA || !dbSessionData.IsDiscoverable {
 		userHandle = ls.convertUserHandle(sessionData.UserID)
@@ -108,7 +265,7 @@ func (ls *loginService) Finalize(req *protocol.ParsedCredentialAssertionData) (s
 	req.Response.UserHandle = []byte(userHandle)
 	webauthnUser, err := ls.getWebauthnUserByUserHandle(userHandle)
 	if err != nil {
-		return "", userHandle, echo.NewHTTPError(http.StatusUnauthorized, "failed to get user handle").SetInternal(err)
+		return nil, userHandle, echo.NewHTTPError(http.StatusUnauthorized, "failed to get user handle").SetInternal(err)
 	}
 
 	var credential *webauthn.Credential
@@ -122,31 +279,54 @@ func (ls *loginService) Finalize(req *protocol.ParsedCredentialAssertionData) (s
 
 	if err != nil {
 		ls.logger.Error(err)
-		return "", userHandle, echo.NewHTTPError(http.StatusUnauthorized, "failed to validate assertion").SetInternal(err)
+		return nil, userHandle, echo.NewHTTPError(http.StatusUnauthorized, "failed to validate assertion").SetInternal(err)
 	}
 	credentialId := base64.RawURLEncoding.EncodeToString(credential.ID)
 
 	dbCredential := webauthnUser.FindCredentialById(credentialId)
 	if !ls.useMFA && dbCredential.IsMFA {
-		return "", userHandle, echo.NewHTTPError(http.StatusBadRequest, "MFA credentials are not usable for normal login")
+		return nil, userHandle, echo.NewHTTPError(http.StatusBadRequest, "MFA credentials are not usable for normal login")
+	}
+
+	if !ls.useMFA {
+		err = ls.checkCeremonyAttachment(ls.getCeremony(), userHandle, req.AuthenticatorAttachment)
+		if err != nil {
+			return nil, userHandle, err
+		}
+	}
+
+	backupFlow := models.BackupPolicyFlowPasskey
+	if ls.useMFA {
+		backupFlow = models.BackupPolicyFlowMfa
 	}
 
-	err = ls.updateCredentialForUser(dbCredential, req.Response.AuthenticatorData.Flags)
+	err = ls.checkBackupPolicy(backupFlow, userHandle, req.Response.AuthenticatorData.Flags)
 	if err != nil {
-		return "", userHandle, err
+		return nil, userHandle, err
 	}
 
-	err = ls.sessionDataPersister.Delete(*dbSessionData)
+	err = ls.updateCredentialForUser(dbCredential, credential.Authenticator, req.Response.AuthenticatorData.Flags)
 	if err != nil {
-		ls.logger.Error(err)
-		return "", userHandle, fmt.Errorf("failed to delete assertion session data: %w", err)
+		return nil, userHandle, err
 	}
 
-	token, err := ls.createUserCredentialToken(webauthnUser.UserId, credentialId)
+	err = ls.sessionDataPersister.Delete(*dbSessionData)
 	if err != nil {
 		ls.logger.Error(err)
-		return "", userHandle, err
+		return nil, userHandle, fmt.Errorf("failed to delete assertion session data: %w", err)
 	}
 
-	return token, userHandle, nil
+	return dbCredential, userHandle, nil
+}
+
+func (ls *loginService) UsedConditionalMediation() bool {
+	return ls.conditional
+}
+
+func (ls *loginService) getCeremony() models.WebauthnCeremony {
+	if ls.conditional {
+		return models.WebauthnCeremonyConditionalLogin
+	}
+
+	return models.WebauthnCeremonyLogin
 }
@@ -0,0 +1,50 @@
+package services
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/teamhanko/passkey-server/api/dto/intern"
+	"github.com/teamhanko/passkey-server/persistence/models"
+)
+
+func TestCreateFakeCredentialsIsDeterministic(t *testing.T) {
+	// given
+	key := []byte("user-enumeration-key")
+
+	// when
+	first := createFakeCredentials("alice", key)
+	second := createFakeCredentials("alice", key)
+	otherUser := createFakeCredentials("bob", key)
+	otherKey := createFakeCredentials("alice", []byte("other-user-enumeration-key"))
+
+	// then
+	assert.Equal(t, first, second)
+	assert.NotEqual(t, first[0].ID, otherUser[0].ID)
+	assert.NotEqual(t, first[0].ID, otherKey[0].ID)
+}
+
+func TestCreateFakeCredentialsLookLikeRealCredentials(t *testing.T) {
+	// given
+	realCredential := models.WebauthnCredential{
+		ID: base64.RawURLEncoding.EncodeToString(make([]byte, 32)),
+	}
+	realDescriptor := intern.WebauthnCredentialFromModel(&realCredential).Descriptor()
+
+	for _, username := range []string{"alice", "bob", "carol", "dave", "eve"} {
+		// when
+		credentials := createFakeCredentials(username, []byte("user-enumeration-key"))
+
+		// then
+		assert.NotEmpty(t, credentials)
+		assert.LessOrEqual(t, len(credentials), 2)
+
+		for i := range credentials {
+			descriptor := intern.WebauthnCredentialFromModel(&credentials[i]).Descriptor()
+
+			assert.Equal(t, realDescriptor.Type, descriptor.Type)
+			assert.Len(t, descriptor.CredentialID, len(realDescriptor.CredentialID))
+		}
+	}
+}
@@ -0,0 +1,242 @@
+package services
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/teamhanko/passkey-server/api/dto/request"
+	"github.com/teamhanko/passkey-server/api/dto/response"
+	"github.com/teamhanko/passkey-server/crypto"
+	"github.com/teamhanko/passkey-server/crypto/jwt"
+	"github.com/teamhanko/passkey-server/persistence/models"
+	"github.com/teamhanko/passkey-server/persistence/persisters"
+)
+
+const (
+	// OidcAuthorizationCodeLifetime is the time in seconds an authorization code can be exchanged
+	OidcAuthorizationCodeLifetime = 60
+
+	OidcErrorInvalidRequest          = "invalid_request"
+	OidcErrorInvalidScope            = "invalid_scope"
+	OidcErrorInvalidGrant            = "invalid_grant"
+	OidcErrorUnsupportedResponseType = "unsupported_response_type"
+	OidcErrorUnsupportedGrantType    = "unsupported_grant_type"
+)
+
+// OidcError is an error which is returned to the OpenID Connect client as defined in RFC 6749
+type OidcError struct {
+	Code        string
+	Description string
+}
+
+func (e *OidcError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code, e.Description)
+}
+
+func newOidcError(code string, description string) *OidcError {
+	return &OidcError{Code: code, Description: description}
+}
+
+type OidcService interface {
+	// ValidateAuthorizationRequest returns an echo.HTTPError when the client or redirect uri is unknown and an OidcError
+	// when the request is invalid but the error can be sent to the redirect uri.
+	ValidateAuthorizationRequest(dto request.OidcAuthorizationRequestDto) error
+	// CreateAuthorizationCode stores an authorization code for the authenticated credential and returns the uri the
+	// user agent has to be redirected to.
+	CreateAuthorizationCode(dto request.OidcAuthorizationRequestDto, credential *models.WebauthnCredential) (string, error)
+	// ExchangeCode redeems an authorization code and returns the tokens. The returned user id is the subject of the tokens.
+	ExchangeCode(dto request.OidcTokenRequestDto) (*response.OidcTokenDto, *string, error)
+}
+
+type oidcService struct {
+	*BaseService
+
+	issuer        string
+	generator     jwt.Generator
+	codePersister persisters.OidcAuthorizationCodePersister
+}
+
+func NewOidcService(ctx echo.Context, tenant models.Tenant, issuer string, generator jwt.Generator, codePersister persisters.OidcAuthorizationCodePersister) OidcService {
+	return &oidcService{
+		BaseService: &BaseService{
+			logger: ctx.Logger(),
+			tenant: tenant,
+		},
+		issuer:        issuer,
+		generator:     generator,
+		codePersister: codePersister,
+	}
+}
+
+func (oc *oidcService) ValidateAuthorizationRequest(dto request.OidcAuthorizationRequestDto) error {
+	client := oc.tenant.Config.OidcClients.FindByClientId(dto.ClientId)
+	if client == nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "unknown client_id")
+	}
+
+	if !client.HasRedirectUri(dto.RedirectUri) {
+		return echo.NewHTTPError(http.StatusBadRequest, "redirect_uri is not registered for the client")
+	}
+
+	if dto.ResponseType != "code" {
+		return newOidcError(OidcErrorUnsupportedResponseType, "only the response_type 'code' is supported")
+	}
+
+	if !containsScope(dto.Scope, "openid") {
+		return newOidcError(OidcErrorInvalidScope, "scope must contain 'openid'")
+	}
+
+	if dto.CodeChallenge == "" || dto.CodeChallengeMethod != "S256" {
+		return newOidcError(OidcErrorInvalidRequest, "a code_challenge with code_challenge_method 'S256' is required")
+	}
+
+	return nil
+}
+
+func (oc *oidcService) CreateAuthorizationCode(dto request.OidcAuthorizationRequestDto, credential *models.WebauthnCredential) (string, error) {
+	code, err := crypto.GenerateRandomStringURLSafe(32)
+	if err != nil {
+		oc.logger.Error(err)
+		return "", fmt.Errorf("unable to generate authorization code: %w", err)
+	}
+
+	amr := models.OidcAmrHardwareKey
+	if credential.BackupEligible {
+		amr = models.OidcAmrSoftwareKey
+	}
+
+	id, _ := uuid.NewV4()
+	now := time.Now().UTC()
+
+	err = oc.codePersister.Create(&models.OidcAuthorizationCode{
+		ID:            id,
+		Code:          crypto.HashSecret(code),
+		ClientID:      dto.ClientId,
+		RedirectUri:   dto.RedirectUri,
+		UserID:        credential.UserId,
+		CredentialID:  credential.ID,
+		Nonce:         dto.Nonce,
+		CodeChallenge: dto.CodeChallenge,
+		Amr:           amr,
+		AuthTime:      now,
+		ExpiresAt:     now.Add(OidcAuthorizationCodeLifetime * time.Second),
+		TenantID:      oc.tenant.ID,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	})
+	if err != nil {
+		oc.logger.Error(err)
+		return "", err
+	}
+
+	params := url.Values{}
+	params.Set("code", code)
+	if dto.State != nil {
+		params.Set("state", *dto.State)
+	}
+
+	return BuildOidcRedirectUri(dto.RedirectUri, params)
+}
+
+func (oc *oidcService) ExchangeCode(dto request.OidcTokenRequestDto) (*response.OidcTokenDto, *string, error) {
+	if dto.GrantType != "authorization_code" {
+		return nil, nil, newOidcError(OidcErrorUnsupportedGrantType, "only the grant_type 'authorization_code' is supported")
+	}
+
+	if dto.Code == "" || dto.CodeVerifier == "" {
+		return nil, nil, newOidcError(OidcErrorInvalidRequest, "code and code_verifier are required")
+	}
+
+	code, err := oc.codePersister.GetByCode(oc.tenant.ID, crypto.HashSecret(dto.Code))
+	if err != nil {
+		oc.logger.Error(err)
+		return nil, nil, err
+	}
+
+	if code == nil {
+		return nil, nil, newOidcError(OidcErrorInvalidGrant, "authorization code is invalid")
+	}
+
+	// codes can only be used once, regardless of the outcome of the exchange. Of concurrent exchanges of the same code
+	// only the one which deleted it may continue.
+	deleted, err := oc.codePersister.Delete(code)
+	if err != nil {
+		oc.logger.Error(err)
+		return nil, &code.UserID, err
+	}
+
+	if !deleted {
+		return nil, &code.UserID, newOidcError(OidcErrorInvalidGrant, "authorization code is invalid")
+	}
+
+	if code.IsExpired(time.Now().UTC()) {
+		return nil, &code.UserID, newOidcError(OidcErrorInvalidGrant, "authorization code is expired")
+	}
+
+	if code.ClientID != dto.ClientId || code.RedirectUri != dto.RedirectUri {
+		return nil, &code.UserID, newOidcError(OidcErrorInvalidGrant, "client_id or redirect_uri does not match the authorization request")
+	}
+
+	if !crypto.VerifyCodeChallengeS256(code.CodeChallenge, dto.CodeVerifier) {
+		return nil, &code.UserID, newOidcError(OidcErrorInvalidGrant, "code_verifier does not match the code_challenge")
+	}
+
+	accessToken, err := oc.generator.Generate(code.UserID, code.CredentialID)
+	if err != nil {
+		oc.logger.Error(err)
+		return nil, &code.UserID, err
+	}
+
+	idToken, err := oc.generator.GenerateIdToken(jwt.IdTokenParams{
+		Issuer:   oc.issuer,
+		Subject:  code.UserID,
+		Audience: code.ClientID,
+		Nonce:    code.Nonce,
+		AuthTime: code.AuthTime,
+		Amr:      []string{code.Amr},
+	})
+	if err != nil {
+		oc.logger.Error(err)
+		return nil, &code.UserID, err
+	}
+
+	return &response.OidcTokenDto{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   oc.generator.GetTokenLifetime(),
+		IdToken:     idToken,
+	}, &code.UserID, nil
+}
+
+// BuildOidcRedirectUri appends the params to the query of the redirect uri
+func BuildOidcRedirectUri(redirectUri string, params url.Values) (string, error) {
+	parsedUri, err := url.Parse(redirectUri)
+	if err != nil {
+		return "", fmt.Errorf("unable to parse redirect uri: %w", err)
+	}
+
+	query := parsedUri.Query()
+	for key, values := range params {
+		for _, value := range values {
+			query.Add(key, value)
+		}
+	}
+	parsedUri.RawQuery = query.Encode()
+
+	return parsedUri.String(), nil
+}
+
+func containsScope(scope string, expected string) bool {
+	for _, value := range strings.Fields(scope) {
+		if value == expected {
+			return true
+		}
+	}
+
+	return false
+}
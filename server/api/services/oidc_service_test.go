@@ -0,0 +1,61 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/teamhanko/passkey-server/api/dto/request"
+	"github.com/teamhanko/passkey-server/persistence/models"
+	"github.com/teamhanko/passkey-server/persistence/persisters"
+)
+
+type testOidcCodePersister struct {
+	persisters.OidcAuthorizationCodePersister
+	code    *models.OidcAuthorizationCode
+	deleted bool
+}
+
+func (p *testOidcCodePersister) GetByCode(_ uuid.UUID, _ string) (*models.OidcAuthorizationCode, error) {
+	return p.code, nil
+}
+
+func (p *testOidcCodePersister) Delete(_ *models.OidcAuthorizationCode) (bool, error) {
+	return p.deleted, nil
+}
+
+func TestExchangeCodeRejectsCodeDeletedByConcurrentExchange(t *testing.T) {
+	// given
+	codePersister := &testOidcCodePersister{
+		code: &models.OidcAuthorizationCode{
+			ID:          uuid.Must(uuid.NewV4()),
+			ClientID:    "client",
+			RedirectUri: "https://example.com/callback",
+			UserID:      "user",
+			ExpiresAt:   time.Now().UTC().Add(time.Minute),
+		},
+		deleted: false,
+	}
+	service := &oidcService{
+		BaseService:   &BaseService{logger: echo.New().Logger, tenant: models.Tenant{ID: uuid.Must(uuid.NewV4())}},
+		codePersister: codePersister,
+	}
+
+	// when
+	tokens, _, err := service.ExchangeCode(request.OidcTokenRequestDto{
+		GrantType:    "authorization_code",
+		Code:         "code",
+		CodeVerifier: "verifier",
+		ClientId:     "client",
+		RedirectUri:  "https://example.com/callback",
+	})
+
+	// then
+	var oidcError *OidcError
+	assert.Nil(t, tokens)
+	assert.True(t, errors.As(err, &oidcError))
+	assert.Equal(t, OidcErrorInvalidGrant, oidcError.Code)
+}
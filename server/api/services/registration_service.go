@@ -4,17 +4,23 @@ import (
 	"errors"
 	"fmt"
 	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/gobuffalo/nulls"
+	"github.com/gofrs/uuid"
 	"github.com/labstack/echo/v4"
 	"github.com/teamhanko/passkey-server/api/dto/intern"
 	"github.com/teamhanko/passkey-server/mapper"
 	"github.com/teamhanko/passkey-server/persistence/models"
+	"github.com/teamhanko/passkey-server/persistence/persisters"
 	"net/http"
 	"strings"
 	"time"
 )
 
 type RegistrationService interface {
-	Initialize(user *models.WebauthnUser) (*protocol.CredentialCreation, string, error)
+	// Initialize starts a registration. The options override the authenticator selection of the tenant and have to
+	// be allowed by its registration overrides.
+	Initialize(user *models.WebauthnUser, options *intern.RegistrationOptions) (*protocol.CredentialCreation, string, error)
 	Finalize(req *protocol.ParsedCredentialCreationData) (string, *string, error)
 }
 
@@ -35,6 +41,8 @@ func NewRegistrationService(params WebauthnServiceCreateParams) RegistrationServ
 
 			webauthnClient: params.WebauthnClient,
 			generator:      params.Generator,
+			auditLog:       params.AuditLog,
+			tx:             params.Tx,
 
 			userPersister:        params.UserPersister,
 			sessionDataPersister: params.SessionPersister,
@@ -45,26 +53,99 @@ func NewRegistrationService(params WebauthnServiceCreateParams) RegistrationServ
 	}
 }
 
-func (rs *registrationService) Initialize(user *models.WebauthnUser) (*protocol.CredentialCreation, string, error) {
+func (rs *registrationService) Initialize(user *models.WebauthnUser, options *intern.RegistrationOptions) (*protocol.CredentialCreation, string, error) {
+	registrationOptions, err := rs.getRegistrationOptions(options)
+	if err != nil {
+		return nil, user.UserID, err
+	}
+
 	internalUser, err := rs.createOrUpdateUser(*user)
 	if err != nil {
 		return nil, user.UserID, err
 	}
 
-	credentialCreation, sessionData, err := rs.webauthnClient.BeginRegistration(
-		internalUser,
-	)
+	registrationOptions = append(registrationOptions, webauthn.WithExclusions(rs.getExcludedCredentials(internalUser)))
+	credentialCreation, sessionData, err := rs.webauthnClient.BeginRegistration(internalUser, registrationOptions...)
 	if err != nil {
 		return nil, internalUser.UserId, err
 	}
 
-	err = rs.sessionDataPersister.Create(*intern.WebauthnSessionDataToModel(sessionData, rs.tenant.ID, models.WebauthnOperationRegistration, false))
+	sessionDataModel := intern.WebauthnSessionDataToModel(sessionData, rs.tenant.ID, models.WebauthnOperationRegistration, false)
+	sessionDataModel.AttestationPreference = nulls.NewString(string(credentialCreation.Response.Attestation))
+
+	err = rs.sessionDataPersister.Create(*sessionDataModel)
 	if err != nil {
 		return nil, internalUser.UserId, err
 	}
 
 	return credentialCreation, internalUser.UserId, nil
 }
+
+// getRegistrationOptions checks the requested options against the registration overrides of the tenant and returns
+// them as options for the credential creation. Hints are not supported by go-webauthn, they are only validated here.
+func (rs *registrationService) getRegistrationOptions(options *intern.RegistrationOptions) ([]webauthn.RegistrationOption, error) {
+	registrationOptions := make([]webauthn.RegistrationOption, 0)
+	if options == nil || options.IsEmpty() {
+		return registrationOptions, nil
+	}
+
+	if rs.useMFA {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "registration options can not be overridden for MFA credentials")
+	}
+
+	requested := make(map[models.RegistrationOverrideOption][]string)
+	authenticatorSelection := rs.webauthnClient.Config.AuthenticatorSelection
+
+	if options.AuthenticatorAttachment != nil {
+		requested[models.RegistrationOverrideOptionAuthenticatorAttachment] = []string{string(*options.AuthenticatorAttachment)}
+		authenticatorSelection.AuthenticatorAttachment = *options.AuthenticatorAttachment
+	}
+
+	if options.ResidentKey != nil {
+		requireResidentKey := *options.ResidentKey == protocol.ResidentKeyRequirementRequired
+		requested[models.RegistrationOverrideOptionResidentKey] = []string{string(*options.ResidentKey)}
+		authenticatorSelection.ResidentKey = *options.ResidentKey
+		authenticatorSelection.RequireResidentKey = &requireResidentKey
+	}
+
+	if options.UserVerification != nil {
+		requested[models.RegistrationOverrideOptionUserVerification] = []string{string(*options.UserVerification)}
+		authenticatorSelection.UserVerification = *options.UserVerification
+	}
+
+	if options.Attestation != nil {
+		requested[models.RegistrationOverrideOptionAttestation] = []string{string(*options.Attestation)}
+		registrationOptions = append(registrationOptions, webauthn.WithConveyancePreference(*options.Attestation))
+	}
+
+	requested[models.RegistrationOverrideOptionHints] = options.Hints
+
+	for option, values := range requested {
+		for _, value := range values {
+			if !rs.tenant.Config.RegistrationOverrides.IsAllowed(option, value) {
+				return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("%s '%s' is not allowed by the tenant", option, value))
+			}
+		}
+	}
+
+	return append(registrationOptions, webauthn.WithAuthenticatorSelection(authenticatorSelection)), nil
+}
+
+// getExcludedCredentials returns the passkeys of the user, or the MFA credentials when registering an MFA credential,
+// so an authenticator can not be registered twice
+func (rs *registrationService) getExcludedCredentials(user *intern.WebauthnUser) []protocol.CredentialDescriptor {
+	excludedCredentials := make([]protocol.CredentialDescriptor, 0)
+	for i := range user.WebauthnCredentials {
+		if user.WebauthnCredentials[i].IsMFA != rs.useMFA {
+			continue
+		}
+
+		excludedCredentials = append(excludedCredentials, intern.WebauthnCredentialFromModel(&user.WebauthnCredentials[i]).Descriptor())
+	}
+
+	return excludedCredentials
+}
+
 func (rs *registrationService) createOrUpdateUser(user models.WebauthnUser) (*intern.WebauthnUser, error) {
 	dbUser, err := rs.getDbUser(user.UserID)
 	user.TenantID = rs.tenant.ID
@@ -80,6 +161,8 @@ func (rs *registrationService) createOrUpdateUser(user models.WebauthnUser) (*in
 	} else {
 		rs.logger.Debugf("Updating user: %v", user)
 		err = rs.updateUser(dbUser, &user)
+		// use the stored user, as it contains the existing credentials
+		user = *dbUser
 	}
 
 	if err != nil {
@@ -192,7 +275,27 @@ func (rs *registrationService) createCredential(dbUser *models.WebauthnUser, ses
 		return nil, echo.NewHTTPError(errorStatus, errorMessage).SetInternal(err)
 	}
 
+	err = rs.checkAaguidPolicy(session.UserId, credential.Authenticator.AAGUID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = rs.checkAttestationTrust(session, credential.Authenticator.AAGUID, req.Response.AttestationObject.AttStatement)
+	if err != nil {
+		return nil, err
+	}
+
 	flags := req.Response.AttestationObject.AuthData.Flags
+	backupFlow := models.BackupPolicyFlowPasskey
+	if rs.useMFA {
+		backupFlow = models.BackupPolicyFlowMfa
+	}
+
+	err = rs.checkBackupPolicy(backupFlow, session.UserId, flags)
+	if err != nil {
+		return nil, err
+	}
+
 	dbCredential := intern.WebauthnCredentialToModel(
 		credential,
 		session.UserId,
@@ -203,7 +306,12 @@ func (rs *registrationService) createCredential(dbUser *models.WebauthnUser, ses
 		rs.useMFA,
 	)
 
+	// credential ids are unique across all tenants, so the database rejects duplicates of every tenant
 	err = rs.credentialPersister.Create(dbCredential)
+	if err != nil && persisters.IsUniqueViolation(err) {
+		return nil, echo.NewHTTPError(http.StatusConflict, "credential is already registered").SetInternal(err)
+	}
+
 	if err != nil {
 		rs.logger.Error(err)
 		return nil, err
@@ -211,3 +319,124 @@ func (rs *registrationService) createCredential(dbUser *models.WebauthnUser, ses
 
 	return dbCredential, nil
 }
+
+// checkAaguidPolicy rejects authenticator models which are not allowed by the tenant for the current flow. The config
+// linter only accepts allowlists together with trusted attestations, as the AAGUID is self-asserted otherwise.
+func (rs *registrationService) checkAaguidPolicy(userId string, rawAaguid []byte) error {
+	flow := models.AaguidPolicyFlowPasskey
+	if rs.useMFA {
+		flow = models.AaguidPolicyFlowMfa
+	}
+
+	aaguid, err := uuid.FromBytes(rawAaguid)
+	if err != nil {
+		aaguid = uuid.Nil
+	}
+
+	if rs.tenant.Config.AaguidPolicies.IsAllowed(aaguid, flow) {
+		return nil
+	}
+
+	policyErr := fmt.Errorf("authenticator with aaguid '%s' is not allowed for flow '%s'", aaguid, flow)
+	rs.logger.Warn(policyErr)
+
+	err = rs.createAuditLog(models.AuditLogWebAuthnAuthenticatorRejected, &userId, policyErr, true)
+	if err != nil {
+		return err
+	}
+
+	return echo.NewHTTPError(http.StatusForbidden, "authenticator is not allowed").SetInternal(policyErr)
+}
+
+// checkAttestationTrust verifies the authenticator against the FIDO metadata service when the tenant requires it for
+// the current flow
+func (rs *registrationService) checkAttestationTrust(session *models.WebauthnSessionData, rawAaguid []byte, attStatement map[string]interface{}) error {
+	attestationPreference, requireTrusted, rejectRevoked := rs.getAttestationTrustSettings(session)
+	requireTrusted = requireTrusted &&
+		(attestationPreference == protocol.PreferDirectAttestation || attestationPreference == protocol.PreferEnterpriseAttestation)
+
+	if !requireTrusted && !rejectRevoked {
+		return nil
+	}
+
+	aaguid, err := uuid.FromBytes(rawAaguid)
+	if err != nil {
+		aaguid = uuid.Nil
+	}
+
+	authenticator := rs.AuthenticatorMetadata.GetForAaguid(aaguid)
+
+	var trustErr error
+	if rejectRevoked && authenticator != nil && authenticator.IsRevoked() {
+		trustErr = fmt.Errorf("authenticator with aaguid '%s' is revoked by the metadata service", aaguid)
+	} else if requireTrusted {
+		if authenticator == nil {
+			trustErr = fmt.Errorf("no metadata available for authenticator with aaguid '%s'", aaguid)
+		} else {
+			err = authenticator.VerifyAttestationCertificates(getAttestationCertificates(attStatement), time.Now())
+			if err != nil {
+				trustErr = fmt.Errorf("attestation of authenticator with aaguid '%s' is not trusted: %w", aaguid, err)
+			}
+		}
+	}
+
+	if trustErr == nil {
+		return nil
+	}
+
+	rs.logger.Warn(trustErr)
+
+	err = rs.createAuditLog(models.AuditLogWebAuthnAuthenticatorRejected, &session.UserId, trustErr, true)
+	if err != nil {
+		return err
+	}
+
+	return echo.NewHTTPError(http.StatusForbidden, "authenticator is not trusted").SetInternal(trustErr)
+}
+
+// getAttestationTrustSettings returns the attestation preference of the registration together with the trust settings
+// of the flow. The preference is taken from the session, as it can be overridden per registration.
+func (rs *registrationService) getAttestationTrustSettings(session *models.WebauthnSessionData) (protocol.ConveyancePreference, bool, bool) {
+	var attestationPreference protocol.ConveyancePreference
+	var requireTrusted, rejectRevoked bool
+
+	if rs.useMFA {
+		mfaConfig := rs.tenant.Config.MfaConfig
+		if mfaConfig == nil {
+			return protocol.PreferNoAttestation, false, false
+		}
+
+		attestationPreference, requireTrusted, rejectRevoked = mfaConfig.AttestationPreference, mfaConfig.RequireTrustedAttestation, mfaConfig.RejectRevokedAuthenticators
+	} else {
+		webauthnConfig := rs.tenant.Config.WebauthnConfig
+		attestationPreference, requireTrusted, rejectRevoked = webauthnConfig.AttestationPreference, webauthnConfig.RequireTrustedAttestation, webauthnConfig.RejectRevokedAuthenticators
+	}
+
+	// sessions created before the preference was stored use the one of the tenant
+	if session.AttestationPreference.Valid {
+		attestationPreference = protocol.ConveyancePreference(session.AttestationPreference.String)
+	}
+
+	return attestationPreference, requireTrusted, rejectRevoked
+}
+
+// getAttestationCertificates returns the DER encoded certificates of the x5c field. Self attestation and formats
+// without x5c field result in an empty chain.
+func getAttestationCertificates(attStatement map[string]interface{}) [][]byte {
+	rawCertificates, ok := attStatement["x5c"].([]interface{})
+	if !ok {
+		return nil
+	}
+
+	certificates := make([][]byte, 0, len(rawCertificates))
+	for _, rawCertificate := range rawCertificates {
+		certificate, ok := rawCertificate.([]byte)
+		if !ok {
+			return nil
+		}
+
+		certificates = append(certificates, certificate)
+	}
+
+	return certificates
+}
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/gobuffalo/nulls"
+	"github.com/stretchr/testify/assert"
+	"github.com/teamhanko/passkey-server/api/dto/intern"
+	"github.com/teamhanko/passkey-server/persistence/models"
+)
+
+func newTestUserWithCredentials() *intern.WebauthnUser {
+	return &intern.WebauthnUser{
+		UserId: "user",
+		WebauthnCredentials: []models.WebauthnCredential{
+			{ID: base64.RawURLEncoding.EncodeToString([]byte("passkey")), IsMFA: false},
+			{ID: base64.RawURLEncoding.EncodeToString([]byte("mfa")), IsMFA: true},
+		},
+	}
+}
+
+func TestGetExcludedCredentialsReturnsPasskeys(t *testing.T) {
+	// given
+	rs := &registrationService{WebauthnService: WebauthnService{useMFA: false}}
+
+	// when
+	excludedCredentials := rs.getExcludedCredentials(newTestUserWithCredentials())
+
+	// then
+	assert.Len(t, excludedCredentials, 1)
+	assert.Equal(t, protocol.URLEncodedBase64("passkey"), excludedCredentials[0].CredentialID)
+	assert.Equal(t, protocol.PublicKeyCredentialType, excludedCredentials[0].Type)
+}
+
+func TestGetExcludedCredentialsReturnsMfaCredentials(t *testing.T) {
+	// given
+	rs := &registrationService{WebauthnService: WebauthnService{useMFA: true}}
+
+	// when
+	excludedCredentials := rs.getExcludedCredentials(newTestUserWithCredentials())
+
+	// then
+	assert.Len(t, excludedCredentials, 1)
+	assert.Equal(t, protocol.URLEncodedBase64("mfa"), excludedCredentials[0].CredentialID)
+}
+
+func TestGetExcludedCredentialsReturnsEmptyListWithoutCredentials(t *testing.T) {
+	// given
+	rs := &registrationService{WebauthnService: WebauthnService{useMFA: false}}
+
+	// when
+	excludedCredentials := rs.getExcludedCredentials(&intern.WebauthnUser{UserId: "user"})
+
+	// then
+	assert.NotNil(t, excludedCredentials)
+	assert.Empty(t, excludedCredentials)
+}
+
+func newTestTrustRegistrationService(attestationPreference protocol.ConveyancePreference) *registrationService {
+	return &registrationService{WebauthnService: WebauthnService{
+		BaseService: &BaseService{
+			tenant: models.Tenant{Config: models.Config{WebauthnConfig: models.WebauthnConfig{
+				AttestationPreference:     attestationPreference,
+				RequireTrustedAttestation: true,
+			}}},
+		},
+	}}
+}
+
+func TestGetAttestationTrustSettingsUsesPreferenceOfSession(t *testing.T) {
+	// given
+	rs := newTestTrustRegistrationService(protocol.PreferNoAttestation)
+	session := &models.WebauthnSessionData{AttestationPreference: nulls.NewString(string(protocol.PreferDirectAttestation))}
+
+	// when
+	attestationPreference, requireTrusted, rejectRevoked := rs.getAttestationTrustSettings(session)
+
+	// then
+	assert.Equal(t, protocol.PreferDirectAttestation, attestationPreference)
+	assert.True(t, requireTrusted)
+	assert.False(t, rejectRevoked)
+}
+
+func TestGetAttestationTrustSettingsFallsBackToTenantPreference(t *testing.T) {
+	// given
+	rs := newTestTrustRegistrationService(protocol.PreferDirectAttestation)
+	session := &models.WebauthnSessionData{}
+
+	// when
+	attestationPreference, _, _ := rs.getAttestationTrustSettings(session)
+
+	// then
+	assert.Equal(t, protocol.PreferDirectAttestation, attestationPreference)
+}
+
+func TestCheckAttestationTrustSkipsOverriddenNoneAttestation(t *testing.T) {
+	// given
+	rs := newTestTrustRegistrationService(protocol.PreferDirectAttestation)
+	session := &models.WebauthnSessionData{UserId: "user", AttestationPreference: nulls.NewString(string(protocol.PreferNoAttestation))}
+
+	// when
+	err := rs.checkAttestationTrust(session, make([]byte, 16), map[string]interface{}{})
+
+	// then
+	assert.NoError(t, err)
+}
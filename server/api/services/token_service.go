@@ -0,0 +1,111 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/teamhanko/passkey-server/api/dto/request"
+	"github.com/teamhanko/passkey-server/api/dto/response"
+	"github.com/teamhanko/passkey-server/crypto/jwt"
+	"github.com/teamhanko/passkey-server/persistence/models"
+	"github.com/teamhanko/passkey-server/persistence/persisters"
+)
+
+type TokenService interface {
+	// Introspect verifies the token and returns its claims. The returned user id is the subject of a valid token.
+	Introspect(dto request.IntrospectTokenDto) (*response.TokenIntrospectionDto, *string, error)
+}
+
+type tokenService struct {
+	*BaseService
+
+	generator              jwt.Generator
+	redeemedTokenPersister persisters.RedeemedTokenPersister
+}
+
+func NewTokenService(ctx echo.Context, tenant models.Tenant, generator jwt.Generator, redeemedTokenPersister persisters.RedeemedTokenPersister) TokenService {
+	return &tokenService{
+		BaseService: &BaseService{
+			logger: ctx.Logger(),
+			tenant: tenant,
+		},
+		generator:              generator,
+		redeemedTokenPersister: redeemedTokenPersister,
+	}
+}
+
+func (ts *tokenService) Introspect(dto request.IntrospectTokenDto) (*response.TokenIntrospectionDto, *string, error) {
+	token, err := ts.generator.VerifyToken([]byte(dto.Token))
+	if err != nil {
+		ts.logger.Debug(err)
+		return &response.TokenIntrospectionDto{Active: false}, nil, nil
+	}
+
+	userId := token.Subject()
+	tokenId := token.JwtID()
+
+	if tokenId != "" {
+		redeemedToken, err := ts.redeemedTokenPersister.Get(ts.tenant.ID, tokenId)
+		if err != nil {
+			ts.logger.Error(err)
+			return nil, &userId, err
+		}
+
+		if redeemedToken != nil {
+			if dto.Redeem {
+				return nil, &userId, echo.NewHTTPError(http.StatusConflict, "token has already been redeemed")
+			}
+
+			return &response.TokenIntrospectionDto{Active: false}, &userId, nil
+		}
+	}
+
+	if dto.Redeem {
+		if tokenId == "" {
+			return nil, &userId, echo.NewHTTPError(http.StatusBadRequest, "token can not be redeemed as it has no jti")
+		}
+
+		err = ts.redeem(tokenId, userId, token.Expiration())
+		if err != nil {
+			// a concurrent request redeemed the token first
+			if persisters.IsUniqueViolation(err) {
+				return nil, &userId, echo.NewHTTPError(http.StatusConflict, "token has already been redeemed").SetInternal(err)
+			}
+
+			ts.logger.Error(err)
+			return nil, &userId, err
+		}
+	}
+
+	claims, err := token.AsMap(context.Background())
+	if err != nil {
+		ts.logger.Error(err)
+		return nil, &userId, fmt.Errorf("unable to read token claims: %w", err)
+	}
+
+	return &response.TokenIntrospectionDto{Active: true, Claims: claims}, &userId, nil
+}
+
+func (ts *tokenService) redeem(tokenId string, userId string, expiresAt time.Time) error {
+	id, _ := uuid.NewV4()
+	now := time.Now()
+
+	redeemedToken := &models.RedeemedToken{
+		ID:        id,
+		TokenID:   tokenId,
+		ExpiresAt: expiresAt,
+		TenantID:  ts.tenant.ID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if userId != "" {
+		redeemedToken.UserID = &userId
+	}
+
+	return ts.redeemedTokenPersister.Create(redeemedToken)
+}
@@ -41,9 +41,12 @@ func NewTransactionService(params TransactionServiceCreateParams) TransactionSer
 
 			webauthnClient: params.WebauthnClient,
 			generator:      params.Generator,
+			auditLog:       params.AuditLog,
+			tx:             params.Tx,
 
-			userPersister:        params.UserPersister,
-			sessionDataPersister: params.SessionPersister,
+			userPersister:              params.UserPersister,
+			sessionDataPersister:       params.SessionPersister,
+			credentialHistoryPersister: params.CredentialHistoryPersister,
 
 			useMFA: params.UseMFA,
 		},
@@ -81,9 +84,14 @@ func (ts *transactionService) Initialize(userId string, transaction *models.Tran
 		)
 	}
 
+	opts := append(
+		ts.getCeremonyLoginOptions(models.WebauthnCeremonyTransaction),
+		ts.withTransaction(transaction.Identifier, transaction.Data),
+	)
+
 	credentialAssertion, sessionData, err := ts.webauthnClient.BeginLogin(
 		intern.NewWebauthnUser(*webauthnUser, ts.useMFA),
-		ts.withTransaction(transaction.Identifier, transaction.Data),
+		opts...,
 	)
 	if err != nil {
 		return nil, echo.NewHTTPError(
@@ -165,7 +173,17 @@ func (ts *transactionService) Finalize(req *protocol.ParsedCredentialAssertionDa
 		return "", userHandle, transaction, echo.NewHTTPError(http.StatusBadRequest, "MFA credentials are not usable for transactions")
 	}
 
-	err = ts.updateCredentialForUser(dbCredential, req.Response.AuthenticatorData.Flags)
+	err = ts.checkCeremonyAttachment(models.WebauthnCeremonyTransaction, userHandle, req.AuthenticatorAttachment)
+	if err != nil {
+		return "", userHandle, transaction, err
+	}
+
+	err = ts.checkBackupPolicy(models.BackupPolicyFlowTransaction, userHandle, req.Response.AuthenticatorData.Flags)
+	if err != nil {
+		return "", userHandle, transaction, err
+	}
+
+	err = ts.updateCredentialForUser(dbCredential, credential.Authenticator, req.Response.AuthenticatorData.Flags)
 	if err != nil {
 		return "", userHandle, transaction, err
 	}
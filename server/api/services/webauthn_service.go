@@ -4,9 +4,11 @@ import (
 	"fmt"
 	"github.com/go-webauthn/webauthn/protocol"
 	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/gobuffalo/pop/v6"
 	"github.com/gofrs/uuid"
 	"github.com/labstack/echo/v4"
 	"github.com/teamhanko/passkey-server/api/dto/intern"
+	auditlog "github.com/teamhanko/passkey-server/audit_log"
 	"github.com/teamhanko/passkey-server/crypto/jwt"
 	"github.com/teamhanko/passkey-server/mapper"
 	"github.com/teamhanko/passkey-server/persistence/models"
@@ -20,9 +22,12 @@ type WebauthnService struct {
 
 	webauthnClient webauthn.WebAuthn
 	generator      jwt.Generator
+	auditLog       auditlog.Logger
+	tx             *pop.Connection
 
-	userPersister        persisters.WebauthnUserPersister
-	sessionDataPersister persisters.WebauthnSessionDataPersister
+	userPersister              persisters.WebauthnUserPersister
+	sessionDataPersister       persisters.WebauthnSessionDataPersister
+	credentialHistoryPersister persisters.WebauthnCredentialHistoryPersister
 
 	useMFA bool
 }
@@ -32,13 +37,22 @@ type WebauthnServiceCreateParams struct {
 	Tenant                models.Tenant
 	WebauthnClient        webauthn.WebAuthn
 	Generator             jwt.Generator
+	AuditLog              auditlog.Logger
+	Tx                    *pop.Connection
 	AuthenticatorMetadata mapper.AuthenticatorMetadata
 	UserId                *string
+	Username              *string
 	UseMFA                bool
+	// ConditionalMediation is set for logins which request the credential via autofill
+	ConditionalMediation bool
 
 	UserPersister       persisters.WebauthnUserPersister
 	SessionPersister    persisters.WebauthnSessionDataPersister
 	CredentialPersister persisters.WebauthnCredentialPersister
+	// CredentialHistoryPersister is only needed to finalize logins and transactions
+	CredentialHistoryPersister persisters.WebauthnCredentialHistoryPersister
+	// TenantPersister is only needed to initialize logins with a username
+	TenantPersister persisters.TenantPersister
 }
 
 func (ws *WebauthnService) getSessionByChallenge(challenge string, operation models.Operation) (*webauthn.SessionData, *models.WebauthnSessionData, error) {
@@ -90,12 +104,33 @@ func (ws *WebauthnService) createUserCredentialToken(userId string, credentialId
 	return token, nil
 }
 
-func (ws *WebauthnService) updateCredentialForUser(credential *models.WebauthnCredential, flags protocol.AuthenticatorFlags) error {
+func (ws *WebauthnService) updateCredentialForUser(credential *models.WebauthnCredential, authenticator webauthn.Authenticator, flags protocol.AuthenticatorFlags) error {
 	if credential != nil {
 		now := time.Now().UTC()
 
-		credential.BackupState = flags.HasBackupState()
-		credential.BackupEligible = flags.HasBackupEligible()
+		if authenticator.CloneWarning {
+			err := ws.handleCloneWarning(credential, now)
+			if err != nil {
+				return err
+			}
+		} else {
+			credential.SignCount = int(authenticator.SignCount)
+		}
+
+		if credential.BackupEligible != flags.HasBackupEligible() {
+			err := ws.handleBackupEligibilityChange(credential, flags, now)
+			if err != nil {
+				return err
+			}
+		}
+
+		if credential.BackupState != flags.HasBackupState() {
+			err := ws.handleBackupStateChange(credential, flags, now)
+			if err != nil {
+				return err
+			}
+		}
+
 		credential.LastUsedAt = &now
 		err := ws.credentialPersister.Update(credential)
 		if err != nil {
@@ -106,3 +141,173 @@ func (ws *WebauthnService) updateCredentialForUser(credential *models.WebauthnCr
 
 	return nil
 }
+
+// createAuditLog writes the audit log of a ceremony check together with its webhook deliveries. Logs of rejections are
+// written outside the current transaction, as the transaction gets rolled back with the rejection. All other logs are
+// part of the transaction, so they are only kept when the changes they describe are stored.
+func (ws *WebauthnService) createAuditLog(auditLogType models.AuditLogType, userId *string, logError error, rejected bool) error {
+	var auditErr error
+	if rejected || ws.tx == nil {
+		auditErr = ws.auditLog.Create(auditLogType, userId, nil, logError)
+	} else {
+		auditErr = ws.auditLog.CreateWithConnection(ws.tx, auditLogType, userId, nil, logError)
+	}
+
+	if auditErr != nil {
+		ws.logger.Error(auditErr)
+		return fmt.Errorf(auditlog.CreationFailureFormat, auditErr)
+	}
+
+	return nil
+}
+
+// handleCloneWarning applies the sign counter policy of the tenant to a credential whose signature counter did not
+// increase
+func (ws *WebauthnService) handleCloneWarning(credential *models.WebauthnCredential, now time.Time) error {
+	cloneErr := fmt.Errorf("signature counter of credential '%s' did not increase above %d", credential.ID, credential.SignCount)
+	ws.logger.Warn(cloneErr)
+
+	policy := ws.tenant.Config.WebauthnConfig.SignCounterPolicy
+	err := ws.createAuditLog(models.AuditLogWebAuthnCredentialCloneWarning, &credential.UserId, cloneErr, policy == models.SignCounterPolicyReject)
+	if err != nil {
+		return err
+	}
+
+	switch policy {
+	case models.SignCounterPolicyReject:
+		return echo.NewHTTPError(http.StatusUnauthorized, "credential might be cloned").SetInternal(cloneErr)
+	case models.SignCounterPolicyFlag:
+		reason := string(models.AuditLogWebAuthnCredentialCloneWarning)
+		credential.FlaggedAt = &now
+		credential.FlagReason = &reason
+	}
+
+	return nil
+}
+
+// handleBackupEligibilityChange applies the backup eligibility policy of the tenant to a credential whose backup
+// eligibility flag changed. The stored flag is kept, as it must never change after the creation of a credential.
+func (ws *WebauthnService) handleBackupEligibilityChange(credential *models.WebauthnCredential, flags protocol.AuthenticatorFlags, now time.Time) error {
+	changeErr := fmt.Errorf(
+		"backup eligibility of credential '%s' changed from '%t' to '%t'",
+		credential.ID,
+		credential.BackupEligible,
+		flags.HasBackupEligible(),
+	)
+	ws.logger.Warn(changeErr)
+
+	rejected := ws.tenant.Config.WebauthnConfig.BackupEligibilityPolicy == models.BackupEligibilityPolicyReject
+	err := ws.createAuditLog(models.AuditLogWebAuthnCredentialBackupEligibilityChanged, &credential.UserId, changeErr, rejected)
+	if err != nil {
+		return err
+	}
+
+	if rejected {
+		return echo.NewHTTPError(http.StatusUnauthorized, "backup eligibility of credential changed").SetInternal(changeErr)
+	}
+
+	reason := string(models.AuditLogWebAuthnCredentialBackupEligibilityChanged)
+	credential.FlaggedAt = &now
+	credential.FlagReason = &reason
+
+	return nil
+}
+
+// handleBackupStateChange records the changed backup state of a credential in its history, e.g. when a device-bound
+// credential got synced for the first time
+func (ws *WebauthnService) handleBackupStateChange(credential *models.WebauthnCredential, flags protocol.AuthenticatorFlags, now time.Time) error {
+	historyId, _ := uuid.NewV4()
+	err := ws.credentialHistoryPersister.Create(&models.WebauthnCredentialHistory{
+		ID:                   historyId,
+		WebauthnCredentialID: credential.ID,
+		Type:                 models.WebauthnCredentialHistoryTypeBackupStateChanged,
+		BackupEligible:       credential.BackupEligible,
+		BackupState:          flags.HasBackupState(),
+		CreatedAt:            now,
+		UpdatedAt:            now,
+	})
+	if err != nil {
+		ws.logger.Error(err)
+		return err
+	}
+
+	changeErr := fmt.Errorf(
+		"backup state of credential '%s' changed from '%t' to '%t'",
+		credential.ID,
+		credential.BackupState,
+		flags.HasBackupState(),
+	)
+
+	err = ws.createAuditLog(models.AuditLogWebAuthnCredentialBackupStateChanged, &credential.UserId, changeErr, false)
+	if err != nil {
+		return err
+	}
+
+	credential.BackupState = flags.HasBackupState()
+
+	return nil
+}
+
+// checkBackupPolicy rejects credentials whose backup flags do not satisfy the backup policy of the tenant for the flow
+func (ws *WebauthnService) checkBackupPolicy(flow models.BackupPolicyFlow, userId string, flags protocol.AuthenticatorFlags) error {
+	requirement := ws.tenant.Config.BackupPolicies.GetRequirement(flow)
+	if requirement.IsSatisfiedBy(flags.HasBackupEligible(), flags.HasBackupState()) {
+		return nil
+	}
+
+	policyErr := fmt.Errorf(
+		"credential with backup eligible '%t' and backup state '%t' does not satisfy the backup requirement '%s' for flow '%s'",
+		flags.HasBackupEligible(),
+		flags.HasBackupState(),
+		requirement,
+		flow,
+	)
+	ws.logger.Warn(policyErr)
+
+	err := ws.createAuditLog(models.AuditLogWebAuthnBackupPolicyViolated, &userId, policyErr, true)
+	if err != nil {
+		return err
+	}
+
+	message := "only device-bound credentials are allowed"
+	if requirement == models.BackupRequirementSynced {
+		message = "only synced credentials are allowed"
+	}
+
+	return echo.NewHTTPError(http.StatusForbidden, message).SetInternal(policyErr)
+}
+
+// getCeremonyLoginOptions applies the user verification and timeout of the ceremony to an assertion
+func (ws *WebauthnService) getCeremonyLoginOptions(ceremony models.WebauthnCeremony) []webauthn.LoginOption {
+	settings := ws.tenant.Config.CeremonyConfigs.GetSettings(ceremony, ws.tenant.Config.WebauthnConfig)
+
+	return []webauthn.LoginOption{
+		webauthn.WithUserVerification(settings.UserVerification),
+		ws.withTimeout(settings.Timeout),
+	}
+}
+
+func (ws *WebauthnService) withTimeout(timeout int) webauthn.LoginOption {
+	return func(options *protocol.PublicKeyCredentialRequestOptions) {
+		options.Timeout = timeout
+	}
+}
+
+// checkCeremonyAttachment rejects assertions of authenticators with an attachment other than the one of the ceremony.
+// Clients which do not report the attachment are accepted.
+func (ws *WebauthnService) checkCeremonyAttachment(ceremony models.WebauthnCeremony, userId string, attachment protocol.AuthenticatorAttachment) error {
+	settings := ws.tenant.Config.CeremonyConfigs.GetSettings(ceremony, ws.tenant.Config.WebauthnConfig)
+	if settings.Attachment == nil || attachment == "" || attachment == *settings.Attachment {
+		return nil
+	}
+
+	attachmentErr := fmt.Errorf("authenticator attachment '%s' is not allowed for ceremony '%s'", attachment, ceremony)
+	ws.logger.Warn(attachmentErr)
+
+	err := ws.createAuditLog(models.AuditLogWebAuthnAuthenticatorRejected, &userId, attachmentErr, true)
+	if err != nil {
+		return err
+	}
+
+	return echo.NewHTTPError(http.StatusForbidden, fmt.Sprintf("only %s authenticators are allowed", *settings.Attachment)).SetInternal(attachmentErr)
+}
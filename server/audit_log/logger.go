@@ -10,6 +10,7 @@ import (
 	"github.com/teamhanko/passkey-server/config"
 	"github.com/teamhanko/passkey-server/persistence"
 	"github.com/teamhanko/passkey-server/persistence/models"
+	"github.com/teamhanko/passkey-server/webhook"
 	"os"
 	"strconv"
 	"time"
@@ -59,13 +60,24 @@ func (l *logger) Create(auditLogType models.AuditLogType, user *string, transact
 }
 
 func (l *logger) CreateWithConnection(tx *pop.Connection, auditLogType models.AuditLogType, user *string, transaction *models.Transaction, logError error) error {
+	al, err := l.newAuditLog(auditLogType, user, transaction, logError)
+	if err != nil {
+		return err
+	}
+
 	if l.storageEnabled {
-		err := l.store(tx, auditLogType, user, transaction, logError)
+		err = l.persister.GetAuditLogPersister(tx).Create(*al)
 		if err != nil {
 			return err
 		}
 	}
 
+	// webhook deliveries are written to the outbox in the same transaction, so they are only sent when the transaction succeeds
+	err = l.enqueueWebhooks(tx, *al)
+	if err != nil {
+		return err
+	}
+
 	if l.consoleLoggingEnabled {
 		l.logToConsole(auditLogType, user, transaction, logError)
 	}
@@ -73,15 +85,17 @@ func (l *logger) CreateWithConnection(tx *pop.Connection, auditLogType models.Au
 	return nil
 }
 
-func (l *logger) store(tx *pop.Connection, auditLogType models.AuditLogType, user *string, transaction *models.Transaction, logError error) error {
+func (l *logger) newAuditLog(auditLogType models.AuditLogType, user *string, transaction *models.Transaction, logError error) (*models.AuditLog, error) {
 	id, err := uuid.NewV4()
 	if err != nil {
-		return fmt.Errorf("failed to create id: %w", err)
+		return nil, fmt.Errorf("failed to create id: %w", err)
 	}
 
-	al := models.AuditLog{
+	now := time.Now().UTC()
+	al := &models.AuditLog{
 		ID:                id,
 		Tenant:            l.tenant,
+		TenantID:          l.tenant.ID,
 		Type:              auditLogType,
 		Error:             nil,
 		MetaHttpRequestId: l.ctx.Response().Header().Get(echo.HeaderXRequestID),
@@ -89,6 +103,8 @@ func (l *logger) store(tx *pop.Connection, auditLogType models.AuditLogType, use
 		MetaSourceIp:      l.ctx.RealIP(),
 		ActorUserId:       nil,
 		TransactionId:     nil,
+		CreatedAt:         now,
+		UpdatedAt:         now,
 	}
 
 	if user != nil {
@@ -105,7 +121,29 @@ func (l *logger) store(tx *pop.Connection, auditLogType models.AuditLogType, use
 		al.Error = &tmp
 	}
 
-	return l.persister.GetAuditLogPersister(tx).Create(al)
+	return al, nil
+}
+
+func (l *logger) enqueueWebhooks(tx *pop.Connection, al models.AuditLog) error {
+	webhooks, err := l.persister.GetWebhookPersister(tx).ListForEvent(l.tenant.ID, al.Type)
+	if err != nil {
+		return err
+	}
+
+	deliveries, err := webhook.NewDeliveries(webhooks, al)
+	if err != nil {
+		return err
+	}
+
+	deliveryPersister := l.persister.GetWebhookDeliveryPersister(tx)
+	for i := range deliveries {
+		err = deliveryPersister.Create(&deliveries[i])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 func (l *logger) logToConsole(auditLogType models.AuditLogType, user *string, transaction *models.Transaction, logError error) {
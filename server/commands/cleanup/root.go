@@ -0,0 +1,47 @@
+package cleanup
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/teamhanko/passkey-server/config"
+	"github.com/teamhanko/passkey-server/janitor"
+	"github.com/teamhanko/passkey-server/persistence"
+	"log"
+)
+
+func NewCleanupCommand() *cobra.Command {
+	var configFile string
+
+	cmd := &cobra.Command{
+		Use:   "cleanup",
+		Args:  cobra.NoArgs,
+		Short: "Remove expired session data, abandoned transactions and expired redeemed tokens",
+		Long:  "Runs the janitor once. Expired session data, abandoned transactions and expired redeemed tokens are removed, users without credentials are removed when enabled in the janitor config",
+		Run: func(cmd *cobra.Command, args []string) {
+			cfg, err := config.Load(&configFile)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			persister, err := persistence.NewDatabase(cfg.Database)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			result, err := janitor.NewJanitor(persister, cfg.Janitor).RunOnce()
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			log.Printf("removed %d session data, %d transactions, %d users and %d redeemed tokens", result.SessionData, result.Transactions, result.Users, result.RedeemedTokens)
+		},
+	}
+
+	cmd.Flags().StringVar(&configFile, "config", config.DefaultConfigFilePath, "config file")
+
+	return cmd
+}
+
+func RegisterCommands(parent *cobra.Command) {
+	cmd := NewCleanupCommand()
+	parent.AddCommand(cmd)
+}
@@ -2,9 +2,11 @@ package commands
 
 import (
 	"github.com/spf13/cobra"
+	"github.com/teamhanko/passkey-server/commands/cleanup"
 	"github.com/teamhanko/passkey-server/commands/isready"
 	"github.com/teamhanko/passkey-server/commands/migrate"
 	"github.com/teamhanko/passkey-server/commands/serve"
+	"github.com/teamhanko/passkey-server/commands/tenants"
 	"github.com/teamhanko/passkey-server/commands/version"
 	"log"
 )
@@ -18,6 +20,8 @@ func NewCommand() *cobra.Command {
 	migrate.RegisterCommands(cmd)
 	version.RegisterCommands(cmd)
 	serve.RegisterCommands(cmd)
+	cleanup.RegisterCommands(cmd)
+	tenants.RegisterCommands(cmd)
 
 	return cmd
 }
@@ -1,16 +1,22 @@
 package serve
 
 import (
+	"context"
 	"github.com/spf13/cobra"
 	"github.com/teamhanko/passkey-server/api"
 	"github.com/teamhanko/passkey-server/config"
+	"github.com/teamhanko/passkey-server/keyrotation"
 	"github.com/teamhanko/passkey-server/persistence"
+	"github.com/teamhanko/passkey-server/provisioning"
 	"log"
 	"sync"
 )
 
 func NewServeAdminApiCommand() *cobra.Command {
-	var configFile string
+	var (
+		configFile                 string
+		authenticatorMetadataFlags authenticatorMetadataFlags
+	)
 
 	cmd := &cobra.Command{
 		Use:   "admin",
@@ -22,21 +28,31 @@ func NewServeAdminApiCommand() *cobra.Command {
 				log.Fatal(err)
 			}
 
+			authenticatorMetadata := authenticatorMetadataFlags.load()
+
 			persister, err := persistence.NewDatabase(globalConfig.Database)
 			if err != nil {
 				log.Fatal(err)
 			}
 
+			err = provisioning.ApplyOnStartup(persister, globalConfig.Tenants)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			go keyrotation.NewRotator(persister, globalConfig.KeyRotation).Run(context.Background())
+
 			var wg sync.WaitGroup
 			wg.Add(1)
 
-			go api.StartAdmin(globalConfig, &wg, persister, nil)
+			go api.StartAdmin(globalConfig, &wg, persister, nil, authenticatorMetadata)
 
 			wg.Wait()
 		},
 	}
 
 	cmd.Flags().StringVar(&configFile, "config", config.DefaultConfigFilePath, "config file")
+	authenticatorMetadataFlags.register(cmd)
 
 	return cmd
 }
@@ -1,20 +1,24 @@
 package serve
 
 import (
+	"context"
 	"github.com/labstack/echo-contrib/echoprometheus"
 	"github.com/spf13/cobra"
 	"github.com/teamhanko/passkey-server/api"
 	"github.com/teamhanko/passkey-server/config"
-	"github.com/teamhanko/passkey-server/mapper"
+	"github.com/teamhanko/passkey-server/janitor"
+	"github.com/teamhanko/passkey-server/keyrotation"
 	"github.com/teamhanko/passkey-server/persistence"
+	"github.com/teamhanko/passkey-server/provisioning"
+	"github.com/teamhanko/passkey-server/webhook"
 	"log"
 	"sync"
 )
 
 func NewServeAllCommand() *cobra.Command {
 	var (
-		configFile                string
-		authenticatorMetadataFile string
+		configFile                 string
+		authenticatorMetadataFlags authenticatorMetadataFlags
 	)
 
 	cmd := &cobra.Command{
@@ -27,26 +31,42 @@ func NewServeAllCommand() *cobra.Command {
 				log.Fatal(err)
 			}
 
-			authenticatorMetadata := mapper.LoadAuthenticatorMetadata(&authenticatorMetadataFile)
+			authenticatorMetadata := authenticatorMetadataFlags.load()
 
 			persister, err := persistence.NewDatabase(cfg.Database)
 			if err != nil {
 				log.Fatal(err)
 			}
+
+			err = provisioning.ApplyOnStartup(persister, cfg.Tenants)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			if cfg.Webhooks.Enabled {
+				go webhook.NewWorker(persister, cfg.Webhooks).Run(context.Background())
+			}
+
+			if cfg.Janitor.Enabled {
+				go janitor.NewJanitor(persister, cfg.Janitor).Run(context.Background())
+			}
+
+			go keyrotation.NewRotator(persister, cfg.KeyRotation).Run(context.Background())
+
 			var wg sync.WaitGroup
 			wg.Add(2)
 
 			prometheus := echoprometheus.NewMiddleware("hanko")
 
 			go api.StartPublic(cfg, &wg, persister, authenticatorMetadata)
-			go api.StartAdmin(cfg, &wg, persister, prometheus)
+			go api.StartAdmin(cfg, &wg, persister, prometheus, authenticatorMetadata)
 
 			wg.Wait()
 		},
 	}
 
 	cmd.Flags().StringVar(&configFile, "config", config.DefaultConfigFilePath, "config file")
-	cmd.Flags().StringVar(&authenticatorMetadataFile, "auth-meta", "", "authenticator metadata file")
+	authenticatorMetadataFlags.register(cmd)
 
 	return cmd
 }
@@ -0,0 +1,35 @@
+package serve
+
+import (
+	"log"
+
+	"github.com/spf13/cobra"
+	"github.com/teamhanko/passkey-server/mapper"
+)
+
+type authenticatorMetadataFlags struct {
+	authenticatorMetadataFile string
+	metadataBlobFile          string
+	metadataRootFile          string
+}
+
+func (f *authenticatorMetadataFlags) register(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&f.authenticatorMetadataFile, "auth-meta", "", "authenticator metadata file")
+	cmd.Flags().StringVar(&f.metadataBlobFile, "mds-blob", "", "signed FIDO MDS3 metadata blob file")
+	cmd.Flags().StringVar(&f.metadataRootFile, "mds-root", "", "PEM encoded root certificate of the metadata blob (default: FIDO Alliance production root)")
+}
+
+// load returns the authenticator metadata file (or the embedded one) extended by the authenticators of the metadata blob
+func (f *authenticatorMetadataFlags) load() mapper.AuthenticatorMetadata {
+	authenticatorMetadata := mapper.LoadAuthenticatorMetadata(&f.authenticatorMetadataFile)
+	if f.metadataBlobFile == "" {
+		return authenticatorMetadata
+	}
+
+	metadataBlob, err := mapper.LoadMetadataBlob(f.metadataBlobFile, &f.metadataRootFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return authenticatorMetadata.WithMetadataBlob(metadataBlob)
+}
@@ -1,19 +1,23 @@
 package serve
 
 import (
+	"context"
 	"github.com/spf13/cobra"
 	"github.com/teamhanko/passkey-server/api"
 	"github.com/teamhanko/passkey-server/config"
-	"github.com/teamhanko/passkey-server/mapper"
+	"github.com/teamhanko/passkey-server/janitor"
+	"github.com/teamhanko/passkey-server/keyrotation"
 	"github.com/teamhanko/passkey-server/persistence"
+	"github.com/teamhanko/passkey-server/provisioning"
+	"github.com/teamhanko/passkey-server/webhook"
 	"log"
 	"sync"
 )
 
 func NewServePublicCommand() *cobra.Command {
 	var (
-		configFile                string
-		authenticatorMetadataFile string
+		configFile                 string
+		authenticatorMetadataFlags authenticatorMetadataFlags
 	)
 
 	cmd := &cobra.Command{
@@ -26,13 +30,28 @@ func NewServePublicCommand() *cobra.Command {
 				log.Fatal(err)
 			}
 
-			authenticatorMetadata := mapper.LoadAuthenticatorMetadata(&authenticatorMetadataFile)
+			authenticatorMetadata := authenticatorMetadataFlags.load()
 
 			persister, err := persistence.NewDatabase(globalConfig.Database)
 			if err != nil {
 				log.Fatal(err)
 			}
 
+			err = provisioning.ApplyOnStartup(persister, globalConfig.Tenants)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			if globalConfig.Webhooks.Enabled {
+				go webhook.NewWorker(persister, globalConfig.Webhooks).Run(context.Background())
+			}
+
+			if globalConfig.Janitor.Enabled {
+				go janitor.NewJanitor(persister, globalConfig.Janitor).Run(context.Background())
+			}
+
+			go keyrotation.NewRotator(persister, globalConfig.KeyRotation).Run(context.Background())
+
 			var wg sync.WaitGroup
 			wg.Add(1)
 
@@ -43,7 +62,7 @@ func NewServePublicCommand() *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&configFile, "config", config.DefaultConfigFilePath, "config file")
-	cmd.Flags().StringVar(&authenticatorMetadataFile, "auth-meta", "", "authenticator metadata file")
+	authenticatorMetadataFlags.register(cmd)
 
 	return cmd
 }
@@ -0,0 +1,74 @@
+package tenants
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/teamhanko/passkey-server/config"
+	"github.com/teamhanko/passkey-server/persistence"
+	"github.com/teamhanko/passkey-server/provisioning"
+	"log"
+	"strings"
+)
+
+func NewTenantsCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "tenants",
+		Short: "Tenant provisioning helper",
+		Long:  "Reconciling the tenants of the config with the database",
+	}
+}
+
+func NewApplyCommand() *cobra.Command {
+	var (
+		configFile string
+		dryRun     bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "apply",
+		Args:  cobra.NoArgs,
+		Short: "Create or update the tenants of the config",
+		Long:  "Creates missing tenants of the config and updates existing ones. With --dry-run the planned changes are printed without applying them",
+		Run: func(cmd *cobra.Command, args []string) {
+			cfg, err := config.Load(&configFile)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			persister, err := persistence.NewDatabase(cfg.Database)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			results, err := provisioning.NewProvisioner(persister, cfg.Tenants).Apply(dryRun)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			action := "applied"
+			if dryRun {
+				action = "planned"
+			}
+
+			for _, result := range results {
+				if len(result.Changes) == 0 {
+					log.Printf("tenant '%s' (%s) is up to date", result.DisplayName, result.TenantID)
+					continue
+				}
+
+				log.Printf("%s changes for tenant '%s' (%s):\n  - %s", action, result.DisplayName, result.TenantID, strings.Join(result.Changes, "\n  - "))
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&configFile, "config", config.DefaultConfigFilePath, "config file")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "only print the planned changes")
+
+	return cmd
+}
+
+func RegisterCommands(parent *cobra.Command) {
+	cmd := NewTenantsCommand()
+	cmd.AddCommand(NewApplyCommand())
+
+	parent.AddCommand(cmd)
+}
@@ -0,0 +1,111 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/gofrs/uuid"
+)
+
+const (
+	// AdminAllTenants grants an admin credential access to every tenant
+	AdminAllTenants = "*"
+
+	adminTokenMinLength = 32
+)
+
+type Admin struct {
+	// Tokens are static bearer tokens which are accepted by the admin API
+	Tokens []AdminToken `yaml:"tokens" json:"tokens,omitempty" koanf:"tokens"`
+	// Jwt configures the verification of signed JWTs issued by an external identity provider
+	Jwt AdminJwt `yaml:"jwt" json:"jwt,omitempty" koanf:"jwt"`
+	// Cors configures which origins are allowed to call the admin API from a browser. No origin is allowed by default.
+	Cors AdminCors `yaml:"cors" json:"cors,omitempty" koanf:"cors"`
+}
+
+type AdminToken struct {
+	Name  string `yaml:"name" json:"name,omitempty" koanf:"name"`
+	Token string `yaml:"token" json:"token,omitempty" koanf:"token"`
+	// Tenants limits the token to the listed tenant ids. Use '*' to grant access to all tenants.
+	Tenants []string `yaml:"tenants" json:"tenants,omitempty" koanf:"tenants"`
+}
+
+type AdminJwt struct {
+	JwksUrl  string `yaml:"jwks_url" json:"jwks_url,omitempty" koanf:"jwks_url"`
+	Issuer   string `yaml:"issuer" json:"issuer,omitempty" koanf:"issuer"`
+	Audience string `yaml:"audience" json:"audience,omitempty" koanf:"audience"`
+	// TenantsClaim is the name of the claim containing the tenant ids the JWT grants access to. Use '*' inside the
+	// claim to grant access to all tenants.
+	TenantsClaim string `yaml:"tenants_claim" json:"tenants_claim,omitempty" koanf:"tenants_claim"`
+}
+
+type AdminCors struct {
+	AllowOrigins []string `yaml:"allow_origins" json:"allow_origins,omitempty" koanf:"allow_origins"`
+}
+
+func (a *Admin) Validate() error {
+	names := make(map[string]bool)
+	for _, token := range a.Tokens {
+		if len(strings.TrimSpace(token.Name)) == 0 {
+			return errors.New("name of admin token must not be empty")
+		}
+
+		if names[token.Name] {
+			return fmt.Errorf("name of admin token '%s' is not unique", token.Name)
+		}
+		names[token.Name] = true
+
+		if len(token.Token) < adminTokenMinLength {
+			return fmt.Errorf("admin token '%s' must be at least %d characters long", token.Name, adminTokenMinLength)
+		}
+
+		err := validateAdminTenants(token.Tenants)
+		if err != nil {
+			return fmt.Errorf("admin token '%s': %w", token.Name, err)
+		}
+	}
+
+	if a.IsJwtEnabled() {
+		if _, err := url.ParseRequestURI(a.Jwt.JwksUrl); err != nil {
+			return fmt.Errorf("jwks_url must be a valid url: %w", err)
+		}
+
+		if len(strings.TrimSpace(a.Jwt.Issuer)) == 0 {
+			return errors.New("issuer must not be empty when jwt authentication is enabled")
+		}
+
+		if len(strings.TrimSpace(a.Jwt.Audience)) == 0 {
+			return errors.New("audience must not be empty when jwt authentication is enabled")
+		}
+	}
+
+	return nil
+}
+
+func (a *Admin) IsJwtEnabled() bool {
+	return len(strings.TrimSpace(a.Jwt.JwksUrl)) > 0
+}
+
+func (a *Admin) IsEnabled() bool {
+	return len(a.Tokens) > 0 || a.IsJwtEnabled()
+}
+
+func validateAdminTenants(tenants []string) error {
+	if len(tenants) == 0 {
+		return errors.New("tenants must not be empty. Use '*' to grant access to all tenants")
+	}
+
+	for _, tenant := range tenants {
+		if tenant == AdminAllTenants {
+			continue
+		}
+
+		if _, err := uuid.FromString(tenant); err != nil {
+			return fmt.Errorf("tenant '%s' is not a valid uuid", tenant)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package config
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestAdminValidation(t *testing.T) {
+	tests := []struct {
+		name          string
+		tokens        []AdminToken
+		jwt           AdminJwt
+		expectedError string
+	}{
+		{
+			name:          "error on missing token name",
+			tokens:        []AdminToken{{Name: "", Token: "a-very-long-and-secret-admin-token", Tenants: []string{"*"}}},
+			expectedError: "name of admin token must not be empty",
+		},
+		{
+			name: "error on duplicate token name",
+			tokens: []AdminToken{
+				{Name: "test", Token: "a-very-long-and-secret-admin-token", Tenants: []string{"*"}},
+				{Name: "test", Token: "another-long-and-secret-admin-token", Tenants: []string{"*"}},
+			},
+			expectedError: "name of admin token 'test' is not unique",
+		},
+		{
+			name:          "error on short token",
+			tokens:        []AdminToken{{Name: "test", Token: "short", Tenants: []string{"*"}}},
+			expectedError: "admin token 'test' must be at least 32 characters long",
+		},
+		{
+			name:          "error on missing tenants",
+			tokens:        []AdminToken{{Name: "test", Token: "a-very-long-and-secret-admin-token"}},
+			expectedError: "admin token 'test': tenants must not be empty. Use '*' to grant access to all tenants",
+		},
+		{
+			name:          "error on invalid tenant",
+			tokens:        []AdminToken{{Name: "test", Token: "a-very-long-and-secret-admin-token", Tenants: []string{"tenant"}}},
+			expectedError: "admin token 'test': tenant 'tenant' is not a valid uuid",
+		},
+		{
+			name:          "error on missing jwt issuer",
+			jwt:           AdminJwt{JwksUrl: "https://example.com/.well-known/jwks.json", Audience: "passkey-server"},
+			expectedError: "issuer must not be empty when jwt authentication is enabled",
+		},
+		{
+			name:          "error on missing jwt audience",
+			jwt:           AdminJwt{JwksUrl: "https://example.com/.well-known/jwks.json", Issuer: "https://example.com"},
+			expectedError: "audience must not be empty when jwt authentication is enabled",
+		},
+	}
+
+	for _, testData := range tests {
+		t.Run(testData.name, func(t *testing.T) {
+			// given
+			cfg := &Admin{
+				Tokens: testData.tokens,
+				Jwt:    testData.jwt,
+			}
+
+			// when
+			err := cfg.Validate()
+
+			// then
+			assert.NotNil(t, err)
+			assert.Equal(t, testData.expectedError, err.Error())
+		})
+	}
+}
+
+func TestAdminValidations(t *testing.T) {
+	// given
+	cfg := &Admin{
+		Tokens: []AdminToken{
+			{Name: "all", Token: "a-very-long-and-secret-admin-token", Tenants: []string{"*"}},
+			{Name: "single", Token: "another-long-and-secret-admin-token", Tenants: []string{"0b41f4dd-8e46-4a7c-bb5d-7b8a2e5b8f2c"}},
+		},
+		Jwt: AdminJwt{
+			JwksUrl:  "https://example.com/.well-known/jwks.json",
+			Issuer:   "https://example.com",
+			Audience: "passkey-server",
+		},
+	}
+
+	// when
+	err := cfg.Validate()
+
+	// then
+	assert.Nil(t, err)
+	assert.True(t, cfg.IsEnabled())
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/teamhanko/passkey-server/utils"
 	"log"
 	"net"
+	"net/url"
 	"strings"
 )
 
@@ -16,10 +17,16 @@ var (
 )
 
 type Config struct {
-	Address      string   `yaml:"address" json:"address,omitempty" koanf:"address"`
-	AdminAddress string   `yaml:"admin_address" json:"admin_address,omitempty" koanf:"admin_address"`
-	Database     Database `yaml:"database" json:"database,omitempty" koanf:"database"`
-	Log          Logger   `yaml:"log" json:"log,omitempty" koanf:"log"`
+	Address      string      `yaml:"address" json:"address,omitempty" koanf:"address"`
+	AdminAddress string      `yaml:"admin_address" json:"admin_address,omitempty" koanf:"admin_address"`
+	PublicUrl    string      `yaml:"public_url" json:"public_url,omitempty" koanf:"public_url"`
+	Database     Database    `yaml:"database" json:"database,omitempty" koanf:"database"`
+	Log          Logger      `yaml:"log" json:"log,omitempty" koanf:"log"`
+	Admin        Admin       `yaml:"admin" json:"admin,omitempty" koanf:"admin"`
+	Webhooks     Webhooks    `yaml:"webhooks" json:"webhooks,omitempty" koanf:"webhooks"`
+	Janitor      Janitor     `yaml:"janitor" json:"janitor,omitempty" koanf:"janitor"`
+	KeyRotation  KeyRotation `yaml:"key_rotation" json:"key_rotation,omitempty" koanf:"key_rotation"`
+	Tenants      []Tenant    `yaml:"tenants" json:"tenants,omitempty" koanf:"tenants"`
 }
 
 func (c *Config) Validate() error {
@@ -39,11 +46,47 @@ func (c *Config) Validate() error {
 		return errors.New("field AdminAddress must be formatted as 'host%zone:port', '[host]:port' or '[host%zone]:port'")
 	}
 
+	if c.PublicUrl != "" {
+		publicUrl, err := url.Parse(c.PublicUrl)
+		if err != nil || (publicUrl.Scheme != "http" && publicUrl.Scheme != "https") || publicUrl.Host == "" {
+			return errors.New("field PublicUrl must be an absolute http or https url")
+		}
+
+		if publicUrl.RawQuery != "" || publicUrl.Fragment != "" {
+			return errors.New("field PublicUrl must not contain a query or fragment")
+		}
+	}
+
 	err := c.Database.Validate()
 	if err != nil {
 		return fmt.Errorf("failed to validate database config: %w", err)
 	}
 
+	err = c.Admin.Validate()
+	if err != nil {
+		return fmt.Errorf("failed to validate admin config: %w", err)
+	}
+
+	err = c.Webhooks.Validate()
+	if err != nil {
+		return fmt.Errorf("failed to validate webhooks config: %w", err)
+	}
+
+	err = c.Janitor.Validate()
+	if err != nil {
+		return fmt.Errorf("failed to validate janitor config: %w", err)
+	}
+
+	err = c.KeyRotation.Validate()
+	if err != nil {
+		return fmt.Errorf("failed to validate key rotation config: %w", err)
+	}
+
+	err = validateTenants(c.Tenants)
+	if err != nil {
+		return fmt.Errorf("failed to validate tenants config: %w", err)
+	}
+
 	return nil
 }
 
@@ -86,6 +129,28 @@ func NewConfig() *Config {
 		Database: Database{
 			Database: "passkey",
 		},
+		Admin: Admin{
+			Jwt: AdminJwt{
+				TenantsClaim: "tenants",
+			},
+		},
+		Webhooks: Webhooks{
+			Enabled:      true,
+			PollInterval: 5,
+			Timeout:      10,
+			MaxAttempts:  8,
+			BackoffBase:  30,
+			BackoffMax:   3600,
+		},
+		Janitor: Janitor{
+			Enabled:                       true,
+			Interval:                      3600,
+			RemoveUsersWithoutCredentials: false,
+			UserGracePeriod:               86400,
+		},
+		KeyRotation: KeyRotation{
+			Interval: 60,
+		},
 	}
 }
 
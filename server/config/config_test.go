@@ -69,3 +69,33 @@ func TestLoadingMinimalConfig(t *testing.T) {
 	assert.NotNil(t, cfg)
 	assert.Equal(t, defaultConfig.Address, cfg.Address)
 }
+
+func TestPublicUrlValidation(t *testing.T) {
+	configPath := "./config.yaml"
+	publicUrls := map[string]bool{
+		"":                                   true,
+		"https://passkeys.example.com":       true,
+		"http://localhost:8000/passkeys":     true,
+		"passkeys.example.com":               false,
+		"ftp://passkeys.example.com":         false,
+		"https://passkeys.example.com/?a=b":  false,
+		"https://passkeys.example.com/#part": false,
+	}
+
+	for publicUrl, valid := range publicUrls {
+		// given
+		cfg, err := Load(&configPath)
+		assert.NoError(t, err)
+		cfg.PublicUrl = publicUrl
+
+		// when
+		err = cfg.Validate()
+
+		// then
+		if valid {
+			assert.NoError(t, err, publicUrl)
+		} else {
+			assert.Error(t, err, publicUrl)
+		}
+	}
+}
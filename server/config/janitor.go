@@ -0,0 +1,37 @@
+package config
+
+import (
+	"errors"
+	"time"
+)
+
+type Janitor struct {
+	// Enabled starts the janitor in the background of the server. The `cleanup` command runs regardless of this setting.
+	Enabled bool `yaml:"enabled" json:"enabled" koanf:"enabled" jsonschema:"default=true"`
+	// Interval is the time in seconds between two cleanups
+	Interval int `yaml:"interval" json:"interval,omitempty" koanf:"interval" jsonschema:"default=3600"`
+	// RemoveUsersWithoutCredentials removes users which have no credentials and no transactions
+	RemoveUsersWithoutCredentials bool `yaml:"remove_users_without_credentials" json:"remove_users_without_credentials" koanf:"remove_users_without_credentials" jsonschema:"default=false"`
+	// UserGracePeriod is the time in seconds a user without credentials is kept after its last change
+	UserGracePeriod int `yaml:"user_grace_period" json:"user_grace_period,omitempty" koanf:"user_grace_period" jsonschema:"default=86400"`
+}
+
+func (j *Janitor) Validate() error {
+	if j.Interval <= 0 {
+		return errors.New("interval must be greater than 0")
+	}
+
+	if j.RemoveUsersWithoutCredentials && j.UserGracePeriod <= 0 {
+		return errors.New("user_grace_period must be greater than 0")
+	}
+
+	return nil
+}
+
+func (j *Janitor) GetInterval() time.Duration {
+	return time.Duration(j.Interval) * time.Second
+}
+
+func (j *Janitor) GetUserGracePeriod() time.Duration {
+	return time.Duration(j.UserGracePeriod) * time.Second
+}
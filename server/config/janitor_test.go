@@ -0,0 +1,33 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJanitorValidation(t *testing.T) {
+	// given
+	cfg := NewConfig().Janitor
+	cfg.RemoveUsersWithoutCredentials = true
+	cfg.UserGracePeriod = 0
+
+	// when
+	err := cfg.Validate()
+
+	// then
+	assert.NotNil(t, err)
+	assert.Equal(t, "user_grace_period must be greater than 0", err.Error())
+}
+
+func TestJanitorIgnoresGracePeriodWhenUsersAreKept(t *testing.T) {
+	// given
+	cfg := NewConfig().Janitor
+	cfg.UserGracePeriod = 0
+
+	// when
+	err := cfg.Validate()
+
+	// then
+	assert.Nil(t, err)
+}
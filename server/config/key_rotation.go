@@ -0,0 +1,24 @@
+package config
+
+import (
+	"errors"
+	"time"
+)
+
+type KeyRotation struct {
+	// Interval is the time in seconds between two runs of the key lifecycle of all tenants. Pending keys are activated
+	// and retiring keys are revoked with a delay of up to one interval.
+	Interval int `yaml:"interval" json:"interval,omitempty" koanf:"interval" jsonschema:"default=60"`
+}
+
+func (k *KeyRotation) Validate() error {
+	if k.Interval <= 0 {
+		return errors.New("interval must be greater than 0")
+	}
+
+	return nil
+}
+
+func (k *KeyRotation) GetInterval() time.Duration {
+	return time.Duration(k.Interval) * time.Second
+}
@@ -0,0 +1,20 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyRotationValidation(t *testing.T) {
+	// given
+	cfg := NewConfig().KeyRotation
+	cfg.Interval = 0
+
+	// when
+	err := cfg.Validate()
+
+	// then
+	assert.NotNil(t, err)
+	assert.Equal(t, "interval must be greater than 0", err.Error())
+}
@@ -0,0 +1,127 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"github.com/gofrs/uuid"
+	"os"
+	"strings"
+	"time"
+)
+
+// Tenant is created or updated when the server starts or with the `tenants apply` command
+type Tenant struct {
+	// ID is the fixed id of the tenant
+	ID          string `yaml:"id" json:"id" koanf:"id"`
+	DisplayName string `yaml:"display_name" json:"display_name" koanf:"display_name"`
+	// Config has the same format as the config of the create tenant request of the admin API
+	Config map[string]interface{} `yaml:"config" json:"config" koanf:"config"`
+	// ApiKeys are created or rotated by their name. API keys which are not listed are kept.
+	ApiKeys []TenantSecret `yaml:"api_keys" json:"api_keys,omitempty" koanf:"api_keys"`
+	// JwkKeys are used to encrypt the signing keys of the tenant. A random key is generated for new tenants without JWK
+	// keys. They can't be changed once they are stored, add a new key instead.
+	JwkKeys []TenantSecret `yaml:"jwk_keys" json:"jwk_keys,omitempty" koanf:"jwk_keys"`
+}
+
+// TenantSecret references a secret, so it does not have to be stored in the config file
+type TenantSecret struct {
+	Name string `yaml:"name" json:"name" koanf:"name"`
+	// Env is the name of the environment variable which contains the secret
+	Env string `yaml:"env" json:"env,omitempty" koanf:"env"`
+	// File is the path of a file which contains the secret, e.g. a mounted kubernetes secret
+	File string `yaml:"file" json:"file,omitempty" koanf:"file"`
+	// Scopes are only used for api keys. All scopes are granted when omitted.
+	Scopes []string `yaml:"scopes" json:"scopes,omitempty" koanf:"scopes"`
+	// ExpiresAt is only used for api keys. The api key can't be used after this time.
+	ExpiresAt *time.Time `yaml:"expires_at" json:"expires_at,omitempty" koanf:"expires_at"`
+}
+
+func (t *Tenant) Validate() error {
+	if _, err := uuid.FromString(t.ID); err != nil {
+		return fmt.Errorf("id '%s' must be a uuid", t.ID)
+	}
+
+	if len(strings.TrimSpace(t.DisplayName)) == 0 {
+		return errors.New("display_name must not be empty")
+	}
+
+	if t.Config == nil {
+		return errors.New("config must be set")
+	}
+
+	for _, jwkKey := range t.JwkKeys {
+		if len(jwkKey.Scopes) > 0 || jwkKey.ExpiresAt != nil {
+			return fmt.Errorf("jwk key '%s' must not have scopes or an expiry", jwkKey.Name)
+		}
+	}
+
+	for _, secrets := range [][]TenantSecret{t.ApiKeys, t.JwkKeys} {
+		names := make(map[string]bool)
+		for _, secret := range secrets {
+			err := secret.Validate()
+			if err != nil {
+				return err
+			}
+
+			if names[secret.Name] {
+				return fmt.Errorf("secret name '%s' is not unique", secret.Name)
+			}
+
+			names[secret.Name] = true
+		}
+	}
+
+	return nil
+}
+
+func (s *TenantSecret) Validate() error {
+	if len(strings.TrimSpace(s.Name)) == 0 {
+		return errors.New("secret name must not be empty")
+	}
+
+	if (s.Env == "") == (s.File == "") {
+		return fmt.Errorf("secret '%s' must either reference an env variable or a file", s.Name)
+	}
+
+	return nil
+}
+
+// Resolve reads the secret from the referenced env variable or file
+func (s *TenantSecret) Resolve() (string, error) {
+	var value string
+	if s.Env != "" {
+		value = os.Getenv(s.Env)
+	} else {
+		content, err := os.ReadFile(s.File)
+		if err != nil {
+			return "", fmt.Errorf("unable to read secret '%s': %w", s.Name, err)
+		}
+
+		value = string(content)
+	}
+
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", fmt.Errorf("secret '%s' is empty", s.Name)
+	}
+
+	return value, nil
+}
+
+func validateTenants(tenants []Tenant) error {
+	ids := make(map[string]bool)
+	for i := range tenants {
+		err := tenants[i].Validate()
+		if err != nil {
+			return fmt.Errorf("tenant %d: %w", i, err)
+		}
+
+		if ids[tenants[i].ID] {
+			return fmt.Errorf("tenant id '%s' is not unique", tenants[i].ID)
+		}
+
+		ids[tenants[i].ID] = true
+	}
+
+	return nil
+}
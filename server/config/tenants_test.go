@@ -0,0 +1,92 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadingTenants(t *testing.T) {
+	// given
+	configPath := "./testdata/tenants-config.yaml"
+
+	// when
+	cfg, err := Load(&configPath)
+
+	// then
+	assert.NoError(t, err)
+	assert.Len(t, cfg.Tenants, 1)
+
+	tenant := cfg.Tenants[0]
+	assert.Equal(t, "0f3bf1c8-32b0-4a64-8f6a-3f0dbbd4a4c5", tenant.ID)
+	assert.Equal(t, "Example", tenant.DisplayName)
+	expiresAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, []TenantSecret{
+		{Name: "backend", Env: "EXAMPLE_API_KEY"},
+		{Name: "introspection", Env: "EXAMPLE_INTROSPECTION_KEY", Scopes: []string{"token:introspect"}, ExpiresAt: &expiresAt},
+	}, tenant.ApiKeys)
+
+	webauthn := tenant.Config["webauthn"].(map[string]interface{})
+	assert.Equal(t, "example.com", webauthn["relying_party"].(map[string]interface{})["id"])
+}
+
+func TestTenantValidation(t *testing.T) {
+	// given
+	tenants := []Tenant{
+		{ID: "not-a-uuid", DisplayName: "Example", Config: map[string]interface{}{}},
+		{ID: "0f3bf1c8-32b0-4a64-8f6a-3f0dbbd4a4c5", Config: map[string]interface{}{}},
+		{ID: "0f3bf1c8-32b0-4a64-8f6a-3f0dbbd4a4c5", DisplayName: "Example"},
+		{ID: "0f3bf1c8-32b0-4a64-8f6a-3f0dbbd4a4c5", DisplayName: "Example", Config: map[string]interface{}{}, ApiKeys: []TenantSecret{{Name: "backend"}}},
+		{ID: "0f3bf1c8-32b0-4a64-8f6a-3f0dbbd4a4c5", DisplayName: "Example", Config: map[string]interface{}{}, JwkKeys: []TenantSecret{{Name: "jwk", Env: "A"}, {Name: "jwk", Env: "B"}}},
+		{ID: "0f3bf1c8-32b0-4a64-8f6a-3f0dbbd4a4c5", DisplayName: "Example", Config: map[string]interface{}{}, JwkKeys: []TenantSecret{{Name: "jwk", Env: "A", Scopes: []string{"mfa"}}}},
+	}
+
+	expectedErrors := []string{
+		"id 'not-a-uuid' must be a uuid",
+		"display_name must not be empty",
+		"config must be set",
+		"secret 'backend' must either reference an env variable or a file",
+		"secret name 'jwk' is not unique",
+		"jwk key 'jwk' must not have scopes or an expiry",
+	}
+
+	for i := range tenants {
+		// when
+		err := tenants[i].Validate()
+
+		// then
+		assert.EqualError(t, err, expectedErrors[i])
+	}
+}
+
+func TestTenantIdsMustBeUnique(t *testing.T) {
+	// given
+	tenant := Tenant{ID: "0f3bf1c8-32b0-4a64-8f6a-3f0dbbd4a4c5", DisplayName: "Example", Config: map[string]interface{}{}}
+
+	// when
+	err := validateTenants([]Tenant{tenant, tenant})
+
+	// then
+	assert.EqualError(t, err, "tenant id '0f3bf1c8-32b0-4a64-8f6a-3f0dbbd4a4c5' is not unique")
+}
+
+func TestResolveTenantSecret(t *testing.T) {
+	// given
+	t.Setenv("EXAMPLE_API_KEY", "an-api-key")
+	envSecret := TenantSecret{Name: "backend", Env: "EXAMPLE_API_KEY"}
+	fileSecret := TenantSecret{Name: "jwk", File: "./testdata/jwk-key"}
+	emptySecret := TenantSecret{Name: "empty", Env: "EXAMPLE_EMPTY_SECRET"}
+
+	// when
+	envValue, envErr := envSecret.Resolve()
+	fileValue, fileErr := fileSecret.Resolve()
+	_, emptyErr := emptySecret.Resolve()
+
+	// then
+	assert.NoError(t, envErr)
+	assert.Equal(t, "an-api-key", envValue)
+	assert.NoError(t, fileErr)
+	assert.Equal(t, "a-jwk-key-with-enough-characters", fileValue)
+	assert.EqualError(t, emptyErr, "secret 'empty' is empty")
+}
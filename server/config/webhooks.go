@@ -0,0 +1,69 @@
+package config
+
+import (
+	"errors"
+	"time"
+)
+
+type Webhooks struct {
+	// Enabled starts the worker which delivers the webhooks. Deliveries are still queued while the worker is disabled.
+	Enabled bool `yaml:"enabled" json:"enabled" koanf:"enabled" jsonschema:"default=true"`
+	// PollInterval is the time in seconds between two checks for pending deliveries
+	PollInterval int `yaml:"poll_interval" json:"poll_interval,omitempty" koanf:"poll_interval" jsonschema:"default=5"`
+	// Timeout is the time in seconds a webhook endpoint has to respond
+	Timeout int `yaml:"timeout" json:"timeout,omitempty" koanf:"timeout" jsonschema:"default=10"`
+	// MaxAttempts is the number of attempts after which a delivery is marked as failed
+	MaxAttempts int `yaml:"max_attempts" json:"max_attempts,omitempty" koanf:"max_attempts" jsonschema:"default=8"`
+	// BackoffBase is the time in seconds before the first retry. The delay is doubled with every further attempt.
+	BackoffBase int `yaml:"backoff_base" json:"backoff_base,omitempty" koanf:"backoff_base" jsonschema:"default=30"`
+	// BackoffMax is the maximum time in seconds between two attempts
+	BackoffMax int `yaml:"backoff_max" json:"backoff_max,omitempty" koanf:"backoff_max" jsonschema:"default=3600"`
+}
+
+func (w *Webhooks) Validate() error {
+	if w.PollInterval <= 0 {
+		return errors.New("poll_interval must be greater than 0")
+	}
+
+	if w.Timeout <= 0 {
+		return errors.New("timeout must be greater than 0")
+	}
+
+	if w.MaxAttempts <= 0 {
+		return errors.New("max_attempts must be greater than 0")
+	}
+
+	if w.BackoffBase <= 0 {
+		return errors.New("backoff_base must be greater than 0")
+	}
+
+	if w.BackoffMax < w.BackoffBase {
+		return errors.New("backoff_max must not be less than backoff_base")
+	}
+
+	return nil
+}
+
+func (w *Webhooks) GetPollInterval() time.Duration {
+	return time.Duration(w.PollInterval) * time.Second
+}
+
+func (w *Webhooks) GetTimeout() time.Duration {
+	return time.Duration(w.Timeout) * time.Second
+}
+
+// GetBackoff returns the delay before the next attempt after the given number of failed attempts
+func (w *Webhooks) GetBackoff(attempts int) time.Duration {
+	delay := time.Duration(w.BackoffBase) * time.Second
+	maxDelay := time.Duration(w.BackoffMax) * time.Second
+
+	for i := 1; i < attempts && delay < maxDelay; i++ {
+		delay *= 2
+	}
+
+	if delay > maxDelay {
+		return maxDelay
+	}
+
+	return delay
+}
@@ -0,0 +1,32 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhooksBackoff(t *testing.T) {
+	// given
+	cfg := NewConfig().Webhooks
+
+	// when
+	delays := []time.Duration{cfg.GetBackoff(1), cfg.GetBackoff(2), cfg.GetBackoff(3), cfg.GetBackoff(20)}
+
+	// then
+	assert.Equal(t, []time.Duration{30 * time.Second, time.Minute, 2 * time.Minute, time.Hour}, delays)
+}
+
+func TestWebhooksValidation(t *testing.T) {
+	// given
+	cfg := NewConfig().Webhooks
+	cfg.BackoffMax = 10
+
+	// when
+	err := cfg.Validate()
+
+	// then
+	assert.NotNil(t, err)
+	assert.Equal(t, "backoff_max must not be less than backoff_base", err.Error())
+}
@@ -0,0 +1,13 @@
+package crypto
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// HashSecret returns the hex encoded SHA-256 hash of a secret.
+// It must only be used for high entropy secrets like generated api keys.
+func HashSecret(secret string) string {
+	hash := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(hash[:])
+}
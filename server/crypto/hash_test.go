@@ -0,0 +1,19 @@
+package crypto
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestHashSecret(t *testing.T) {
+	// given
+	secret := "test"
+
+	// when
+	hash := HashSecret(secret)
+
+	// then
+	assert.Equal(t, "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08", hash)
+	assert.Equal(t, hash, HashSecret(secret))
+	assert.NotEqual(t, hash, HashSecret("test2"))
+}
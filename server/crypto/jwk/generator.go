@@ -1,9 +1,41 @@
 package jwk
 
-import "github.com/lestrrat-go/jwx/v2/jwk"
+import (
+	"fmt"
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/lestrrat-go/jwx/v2/jwk"
+)
 
 // KeyGenerator Interface for JSON Web Key Generation
 type KeyGenerator interface {
 	// Generate a new JWK with a given id
 	Generate(id string) (jwk.Key, error)
 }
+
+// NewKeyGenerator returns the KeyGenerator for the given signature algorithm
+func NewKeyGenerator(algorithm jwa.SignatureAlgorithm) (KeyGenerator, error) {
+	switch algorithm {
+	case jwa.RS256, jwa.PS256:
+		return &RSAKeyGenerator{Algorithm: algorithm}, nil
+	case jwa.ES256:
+		return &ECDSAKeyGenerator{}, nil
+	case jwa.EdDSA:
+		return &EdDSAKeyGenerator{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported signature algorithm '%s'", algorithm)
+	}
+}
+
+func setKeyAttributes(key jwk.Key, id string, algorithm jwa.SignatureAlgorithm) error {
+	err := key.Set(jwk.KeyIDKey, id)
+	if err != nil {
+		return err
+	}
+
+	err = key.Set(jwk.AlgorithmKey, algorithm)
+	if err != nil {
+		return err
+	}
+
+	return key.Set(jwk.KeyUsageKey, jwk.ForSignature)
+}
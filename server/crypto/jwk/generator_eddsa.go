@@ -0,0 +1,31 @@
+package jwk
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/lestrrat-go/jwx/v2/jwk"
+)
+
+// EdDSAKeyGenerator generates Ed25519 keys which are used with EdDSA
+type EdDSAKeyGenerator struct {
+}
+
+func (g *EdDSAKeyGenerator) Generate(id string) (jwk.Key, error) {
+	_, rawKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := jwk.FromRaw(rawKey)
+	if err != nil {
+		return nil, err
+	}
+
+	err = setKeyAttributes(key, id, jwa.EdDSA)
+	if err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
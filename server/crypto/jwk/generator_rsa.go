@@ -7,7 +7,9 @@ import (
 	"github.com/lestrrat-go/jwx/v2/jwk"
 )
 
+// RSAKeyGenerator generates RSA keys which are used with RS256 or PS256
 type RSAKeyGenerator struct {
+	Algorithm jwa.SignatureAlgorithm
 }
 
 func (g *RSAKeyGenerator) Generate(id string) (jwk.Key, error) {
@@ -23,17 +25,12 @@ func (g *RSAKeyGenerator) Generate(id string) (jwk.Key, error) {
 		return nil, err
 	}
 
-	err = key.Set(jwk.KeyIDKey, id)
-	if err != nil {
-		return nil, err
-	}
-
-	err = key.Set(jwk.AlgorithmKey, jwa.RS256)
-	if err != nil {
-		return nil, err
+	algorithm := g.Algorithm
+	if algorithm == "" {
+		algorithm = jwa.RS256
 	}
 
-	err = key.Set(jwk.KeyUsageKey, jwk.ForSignature)
+	err = setKeyAttributes(key, id, algorithm)
 	if err != nil {
 		return nil, err
 	}
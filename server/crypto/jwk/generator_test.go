@@ -0,0 +1,48 @@
+package jwk
+
+import (
+	"testing"
+
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/lestrrat-go/jwx/v2/jwk"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyGenerators(t *testing.T) {
+	tests := []struct {
+		name            string
+		algorithm       jwa.SignatureAlgorithm
+		expectedKeyType jwa.KeyType
+	}{
+		{name: "RS256", algorithm: jwa.RS256, expectedKeyType: jwa.RSA},
+		{name: "PS256", algorithm: jwa.PS256, expectedKeyType: jwa.RSA},
+		{name: "ES256", algorithm: jwa.ES256, expectedKeyType: jwa.EC},
+		{name: "EdDSA", algorithm: jwa.EdDSA, expectedKeyType: jwa.OKP},
+	}
+
+	for _, testData := range tests {
+		t.Run(testData.name, func(t *testing.T) {
+			// given
+			generator, err := NewKeyGenerator(testData.algorithm)
+			assert.NoError(t, err)
+
+			// when
+			key, err := generator.Generate("test-key")
+
+			// then
+			assert.NoError(t, err)
+			assert.Equal(t, "test-key", key.KeyID())
+			assert.Equal(t, testData.algorithm, key.Algorithm())
+			assert.Equal(t, testData.expectedKeyType, key.KeyType())
+			assert.Equal(t, string(jwk.ForSignature), key.KeyUsage())
+		})
+	}
+}
+
+func TestKeyGeneratorUnsupportedAlgorithm(t *testing.T) {
+	// when
+	_, err := NewKeyGenerator(jwa.HS256)
+
+	// then
+	assert.Error(t, err)
+}
@@ -2,7 +2,10 @@ package jwk
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"github.com/gofrs/uuid"
+	"github.com/lestrrat-go/jwx/v2/jwa"
 	"github.com/lestrrat-go/jwx/v2/jwk"
 	"github.com/teamhanko/passkey-server/crypto/aes_gcm"
 	"github.com/teamhanko/passkey-server/persistence/models"
@@ -10,29 +13,60 @@ import (
 	"time"
 )
 
+var ErrRotationPending = errors.New("a key rotation is already pending")
+
 type Manager interface {
 	// GenerateKey is used to generate a jwk Key
 	GenerateKey(tenantId uuid.UUID) (*models.Jwk, error)
-	// GetPublicKeys returns all Public keys that are persisted
+	// GetPublicKeys returns all public keys that are pending, active or retiring
 	GetPublicKeys(tenantId uuid.UUID) (jwk.Set, error)
-	// GetSigningKey returns the last added private key that is used for signing
+	// GetSigningKey returns the newest active private key that is used for signing
 	GetSigningKey(tenantId uuid.UUID) (jwk.Key, error)
+	// GetKeys returns all persisted keys of a tenant together with their public key
+	GetKeys(tenantId uuid.UUID) ([]ManagedKey, error)
+	// ApplyLifecycle moves the keys of a tenant through their states and schedules a rotation when it is due
+	ApplyLifecycle(tenantId uuid.UUID, cfg models.JwtConfig) error
+	// Rotate generates a new pending key. When immediate is set the new key is activated right away.
+	Rotate(tenantId uuid.UUID, immediate bool) (*ManagedKey, error)
+}
+
+// ManagedKey is a persisted key together with its decrypted public key
+type ManagedKey struct {
+	Model     models.Jwk
+	PublicKey jwk.Key
 }
 
 type DefaultManager struct {
 	encrypter *aes_gcm.AESGCM
 	persister persisters.JwkPersister
+	algorithm jwa.SignatureAlgorithm
 }
 
-// NewDefaultManager returns a DefaultManager that reads and persists the jwks to database and generates jwks if a new secret gets added to the config.
-func NewDefaultManager(keys []string, tenantId uuid.UUID, persister persisters.JwkPersister) (Manager, error) {
+// NewManager returns a DefaultManager for the persisted jwks. Unlike NewDefaultManager it never generates keys, so it
+// is used on the request path, which only reads keys.
+func NewManager(keys []string, algorithm jwa.SignatureAlgorithm, persister persisters.JwkPersister) (Manager, error) {
 	encrypter, err := aes_gcm.NewAESGCM(keys)
 	if err != nil {
 		return nil, err
 	}
-	manager := &DefaultManager{
+
+	if _, err = NewKeyGenerator(algorithm); err != nil {
+		return nil, err
+	}
+
+	return &DefaultManager{
 		encrypter: encrypter,
 		persister: persister,
+		algorithm: algorithm,
+	}, nil
+}
+
+// NewDefaultManager returns a DefaultManager that reads and persists the jwks to database and generates jwks if a new secret gets added to the config.
+// New keys are generated for the given signature algorithm.
+func NewDefaultManager(keys []string, tenantId uuid.UUID, algorithm jwa.SignatureAlgorithm, persister persisters.JwkPersister) (Manager, error) {
+	manager, err := NewManager(keys, algorithm, persister)
+	if err != nil {
+		return nil, err
 	}
 
 	foundKeys, err := persister.GetAllForTenant(tenantId)
@@ -54,10 +88,30 @@ func NewDefaultManager(keys []string, tenantId uuid.UUID, persister persisters.J
 	return manager, nil
 }
 
+// GenerateKey generates an active key if the tenant has no active key yet, otherwise the key is pending until it
+// gets activated by the lifecycle.
 func (m *DefaultManager) GenerateKey(tenantId uuid.UUID) (*models.Jwk, error) {
-	rsa := &RSAKeyGenerator{}
+	keys, err := m.persister.GetAllForTenant(tenantId)
+	if err != nil {
+		return nil, err
+	}
+
+	state := models.JwkStatePending
+	if findNewest(keys, models.JwkStateActive) == nil {
+		state = models.JwkStateActive
+	}
+
+	return m.generateKey(tenantId, state)
+}
+
+func (m *DefaultManager) generateKey(tenantId uuid.UUID, state models.JwkState) (*models.Jwk, error) {
+	generator, err := NewKeyGenerator(m.algorithm)
+	if err != nil {
+		return nil, err
+	}
+
 	id, _ := uuid.NewV4()
-	key, err := rsa.Generate(id.String())
+	key, err := generator.Generate(id.String())
 	if err != nil {
 		return nil, err
 	}
@@ -70,13 +124,20 @@ func (m *DefaultManager) GenerateKey(tenantId uuid.UUID) (*models.Jwk, error) {
 		return nil, err
 	}
 
+	now := time.Now()
 	model := models.Jwk{
 		TenantID:  tenantId,
 		KeyData:   encryptedKey,
-		CreatedAt: time.Now(),
+		State:     state,
+		Algorithm: m.algorithm,
+		CreatedAt: now,
+	}
+
+	if state == models.JwkStateActive {
+		model.ActivatedAt = &now
 	}
 
-	err = m.persister.Create(model)
+	err = m.persister.Create(&model)
 	if err != nil {
 		return nil, err
 	}
@@ -85,11 +146,21 @@ func (m *DefaultManager) GenerateKey(tenantId uuid.UUID) (*models.Jwk, error) {
 }
 
 func (m *DefaultManager) GetSigningKey(tenantId uuid.UUID) (jwk.Key, error) {
-	sigModel, err := m.persister.GetLast(tenantId)
+	keys, err := m.persister.GetAllForTenant(tenantId)
 	if err != nil {
 		return nil, err
 	}
-	k, err := m.encrypter.Decrypt(sigModel.KeyData)
+
+	sigModel := findNewest(keys, models.JwkStateActive)
+	if sigModel == nil {
+		return nil, fmt.Errorf("no active signing key found for tenant %s", tenantId)
+	}
+
+	return m.decryptKey(*sigModel)
+}
+
+func (m *DefaultManager) decryptKey(model models.Jwk) (jwk.Key, error) {
+	k, err := m.encrypter.Decrypt(model.KeyData)
 	if err != nil {
 		return nil, err
 	}
@@ -109,13 +180,11 @@ func (m *DefaultManager) GetPublicKeys(tenantId uuid.UUID) (jwk.Set, error) {
 
 	publicKeys := jwk.NewSet()
 	for _, model := range modelList {
-		k, err := m.encrypter.Decrypt(model.KeyData)
-		if err != nil {
-			return nil, err
+		if !model.IsPublished() {
+			continue
 		}
 
-		key, err := jwk.ParseKey(k)
-
+		key, err := m.decryptKey(model)
 		if err != nil {
 			return nil, err
 		}
@@ -132,3 +201,207 @@ func (m *DefaultManager) GetPublicKeys(tenantId uuid.UUID) (jwk.Set, error) {
 
 	return publicKeys, nil
 }
+
+func (m *DefaultManager) GetKeys(tenantId uuid.UUID) ([]ManagedKey, error) {
+	modelList, err := m.persister.GetAllForTenant(tenantId)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]ManagedKey, 0, len(modelList))
+	for _, model := range modelList {
+		key, err := m.decryptKey(model)
+		if err != nil {
+			return nil, err
+		}
+
+		publicKey, err := jwk.PublicKeyOf(key)
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, ManagedKey{Model: model, PublicKey: publicKey})
+	}
+
+	return keys, nil
+}
+
+func (m *DefaultManager) ApplyLifecycle(tenantId uuid.UUID, cfg models.JwtConfig) error {
+	keys, err := m.persister.GetAllForTenant(tenantId)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	retiringDuration := time.Duration(cfg.KeyRetiringDuration) * time.Second
+	pendingDuration := time.Duration(cfg.KeyPendingDuration) * time.Second
+
+	// revoke retiring keys which are no longer needed to verify tokens
+	for i := range keys {
+		key := &keys[i]
+		if key.State == models.JwkStateRetiring && (key.RetiredAt == nil || !key.RetiredAt.Add(retiringDuration).After(now)) {
+			err = m.setState(key, models.JwkStateRevoked, now)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	// activate the newest pending key which was published long enough
+	var dueKey *models.Jwk
+	for i := range keys {
+		key := &keys[i]
+		if key.State == models.JwkStatePending && !key.CreatedAt.Add(pendingDuration).After(now) {
+			dueKey = key
+		}
+	}
+
+	if dueKey == nil {
+		dueKey = findNewest(keys, models.JwkStateActive)
+	}
+
+	if dueKey == nil {
+		dueKey = findNewest(keys, models.JwkStatePending)
+	}
+
+	if dueKey == nil {
+		_, err = m.generateKey(tenantId, models.JwkStateActive)
+		return err
+	}
+
+	if dueKey.State != models.JwkStateActive {
+		err = m.setState(dueKey, models.JwkStateActive, now)
+		if err != nil {
+			return err
+		}
+	}
+
+	// only one key is used for signing, all other active or outdated pending keys are retired
+	for i := range keys {
+		key := &keys[i]
+		if key.ID == dueKey.ID {
+			continue
+		}
+
+		if key.State == models.JwkStateActive || (key.State == models.JwkStatePending && key.ID < dueKey.ID) {
+			err = m.setState(key, models.JwkStateRetiring, now)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	pendingKey := findNewest(keys, models.JwkStatePending)
+	if pendingKey != nil && pendingKey.Algorithm == m.algorithm {
+		return nil
+	}
+
+	// a changed signing algorithm is handled like a rotation, so the new key is published before it is used
+	if dueKey.Algorithm != m.algorithm {
+		_, err = m.generateKey(tenantId, models.JwkStatePending)
+		return err
+	}
+
+	if cfg.KeyRotationInterval <= 0 || pendingKey != nil {
+		return nil
+	}
+
+	activatedAt := dueKey.CreatedAt
+	if dueKey.ActivatedAt != nil {
+		activatedAt = *dueKey.ActivatedAt
+	}
+
+	if !activatedAt.Add(time.Duration(cfg.KeyRotationInterval) * time.Second).After(now) {
+		_, err = m.generateKey(tenantId, models.JwkStatePending)
+		return err
+	}
+
+	return nil
+}
+
+func (m *DefaultManager) Rotate(tenantId uuid.UUID, immediate bool) (*ManagedKey, error) {
+	keys, err := m.persister.GetAllForTenant(tenantId)
+	if err != nil {
+		return nil, err
+	}
+
+	if !immediate {
+		if findNewest(keys, models.JwkStatePending) != nil {
+			return nil, ErrRotationPending
+		}
+
+		return m.toManagedKey(m.generateKey(tenantId, models.JwkStatePending))
+	}
+
+	now := time.Now()
+	for i := range keys {
+		key := &keys[i]
+		if key.State == models.JwkStateActive || key.State == models.JwkStatePending {
+			err = m.setState(key, models.JwkStateRetiring, now)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return m.toManagedKey(m.generateKey(tenantId, models.JwkStateActive))
+}
+
+func (m *DefaultManager) toManagedKey(model *models.Jwk, err error) (*ManagedKey, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := m.decryptKey(*model)
+	if err != nil {
+		return nil, err
+	}
+
+	publicKey, err := jwk.PublicKeyOf(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ManagedKey{Model: *model, PublicKey: publicKey}, nil
+}
+
+func (m *DefaultManager) setState(key *models.Jwk, state models.JwkState, now time.Time) error {
+	key.State = state
+	switch state {
+	case models.JwkStateActive:
+		key.ActivatedAt = &now
+	case models.JwkStateRetiring:
+		key.RetiredAt = &now
+	case models.JwkStateRevoked:
+		key.RevokedAt = &now
+	}
+
+	err := m.persister.Update(key)
+	if err != nil {
+		return fmt.Errorf("failed to set state of jwk %d to %s: %w", key.ID, state, err)
+	}
+
+	return nil
+}
+
+// HasSigningKey returns true if the tenant has an active key which is used for signing
+func HasSigningKey(persister persisters.JwkPersister, tenantId uuid.UUID) (bool, error) {
+	keys, err := persister.GetAllForTenant(tenantId)
+	if err != nil {
+		return false, err
+	}
+
+	return findNewest(keys, models.JwkStateActive) != nil, nil
+}
+
+// findNewest returns the most recently created key with the given state
+func findNewest(keys []models.Jwk, state models.JwkState) *models.Jwk {
+	var found *models.Jwk
+	for i := range keys {
+		if keys[i].State == state && (found == nil || keys[i].ID > found.ID) {
+			found = &keys[i]
+		}
+	}
+
+	return found
+}
@@ -0,0 +1,213 @@
+package jwk
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/stretchr/testify/assert"
+	"github.com/teamhanko/passkey-server/persistence/models"
+)
+
+type inMemoryJwkPersister struct {
+	keys []models.Jwk
+}
+
+func (p *inMemoryJwkPersister) GetAll() ([]models.Jwk, error) {
+	return p.copyKeys(), nil
+}
+
+func (p *inMemoryJwkPersister) GetAllForTenant(_ uuid.UUID) ([]models.Jwk, error) {
+	return p.copyKeys(), nil
+}
+
+func (p *inMemoryJwkPersister) GetLast(_ uuid.UUID) (*models.Jwk, error) {
+	if len(p.keys) == 0 {
+		return nil, nil
+	}
+
+	key := p.keys[len(p.keys)-1]
+	return &key, nil
+}
+
+func (p *inMemoryJwkPersister) Create(jwk *models.Jwk) error {
+	jwk.ID = len(p.keys) + 1
+	p.keys = append(p.keys, *jwk)
+	return nil
+}
+
+func (p *inMemoryJwkPersister) Update(jwk *models.Jwk) error {
+	p.keys[jwk.ID-1] = *jwk
+	return nil
+}
+
+func (p *inMemoryJwkPersister) copyKeys() []models.Jwk {
+	keys := make([]models.Jwk, len(p.keys))
+	copy(keys, p.keys)
+	return keys
+}
+
+func (p *inMemoryJwkPersister) states() []models.JwkState {
+	var states []models.JwkState
+	for _, key := range p.keys {
+		states = append(states, key.State)
+	}
+
+	return states
+}
+
+func newTestManager(t *testing.T) (*DefaultManager, *inMemoryJwkPersister, uuid.UUID) {
+	persister := &inMemoryJwkPersister{}
+	tenantId, _ := uuid.NewV4()
+
+	manager, err := NewDefaultManager([]string{"a-secret-which-is-long-enough"}, tenantId, jwa.RS256, persister)
+	assert.NoError(t, err)
+
+	return manager.(*DefaultManager), persister, tenantId
+}
+
+func TestManagerCreatesActiveKey(t *testing.T) {
+	// given
+	_, persister, _ := newTestManager(t)
+
+	// then
+	assert.Equal(t, []models.JwkState{models.JwkStateActive}, persister.states())
+	assert.NotNil(t, persister.keys[0].ActivatedAt)
+}
+
+func TestManagerSchedulesRotation(t *testing.T) {
+	// given
+	manager, persister, tenantId := newTestManager(t)
+	activatedAt := time.Now().Add(-2 * time.Hour)
+	persister.keys[0].ActivatedAt = &activatedAt
+
+	cfg := models.JwtConfig{KeyRotationInterval: 3600, KeyPendingDuration: 3600, KeyRetiringDuration: 3600}
+
+	// when
+	err := manager.ApplyLifecycle(tenantId, cfg)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []models.JwkState{models.JwkStateActive, models.JwkStatePending}, persister.states())
+
+	publicKeys, err := manager.GetPublicKeys(tenantId)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, publicKeys.Len())
+}
+
+func TestManagerActivatesDuePendingKey(t *testing.T) {
+	// given
+	manager, persister, tenantId := newTestManager(t)
+	_, err := manager.Rotate(tenantId, false)
+	assert.NoError(t, err)
+	persister.keys[1].CreatedAt = time.Now().Add(-2 * time.Hour)
+
+	cfg := models.JwtConfig{KeyRotationInterval: 0, KeyPendingDuration: 3600, KeyRetiringDuration: 3600}
+
+	// when
+	err = manager.ApplyLifecycle(tenantId, cfg)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []models.JwkState{models.JwkStateRetiring, models.JwkStateActive}, persister.states())
+
+	signingKey, err := manager.GetSigningKey(tenantId)
+	assert.NoError(t, err)
+
+	keys, err := manager.GetKeys(tenantId)
+	assert.NoError(t, err)
+	assert.Equal(t, keys[1].PublicKey.KeyID(), signingKey.KeyID())
+}
+
+func TestManagerRevokesRetiredKey(t *testing.T) {
+	// given
+	manager, persister, tenantId := newTestManager(t)
+	_, err := manager.Rotate(tenantId, true)
+	assert.NoError(t, err)
+	retiredAt := time.Now().Add(-2 * time.Hour)
+	persister.keys[0].RetiredAt = &retiredAt
+
+	cfg := models.JwtConfig{KeyRotationInterval: 0, KeyPendingDuration: 3600, KeyRetiringDuration: 3600}
+
+	// when
+	err = manager.ApplyLifecycle(tenantId, cfg)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []models.JwkState{models.JwkStateRevoked, models.JwkStateActive}, persister.states())
+
+	publicKeys, err := manager.GetPublicKeys(tenantId)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, publicKeys.Len())
+}
+
+func TestManagerRejectsSecondPendingRotation(t *testing.T) {
+	// given
+	manager, _, tenantId := newTestManager(t)
+	_, err := manager.Rotate(tenantId, false)
+	assert.NoError(t, err)
+
+	// when
+	_, err = manager.Rotate(tenantId, false)
+
+	// then
+	assert.ErrorIs(t, err, ErrRotationPending)
+}
+
+func TestManagerSwitchesSigningAlgorithm(t *testing.T) {
+	// given
+	manager, persister, tenantId := newTestManager(t)
+	manager.algorithm = jwa.ES256
+
+	cfg := models.JwtConfig{KeyRotationInterval: 0, KeyPendingDuration: 3600, KeyRetiringDuration: 3600}
+
+	// when
+	err := manager.ApplyLifecycle(tenantId, cfg)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []models.JwkState{models.JwkStateActive, models.JwkStatePending}, persister.states())
+	assert.Equal(t, jwa.ES256, persister.keys[1].Algorithm)
+
+	publicKeys, err := manager.GetPublicKeys(tenantId)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, publicKeys.Len())
+}
+
+func TestManagerWithoutKeyGeneration(t *testing.T) {
+	// given
+	persister := &inMemoryJwkPersister{}
+	tenantId, _ := uuid.NewV4()
+
+	// when
+	manager, err := NewManager([]string{"a-secret-which-is-long-enough"}, jwa.RS256, persister)
+
+	// then
+	assert.NoError(t, err)
+	assert.Empty(t, persister.keys)
+
+	_, err = manager.GetSigningKey(tenantId)
+	assert.Error(t, err)
+}
+
+func TestHasSigningKey(t *testing.T) {
+	// given
+	tenantId, _ := uuid.NewV4()
+	persister := &inMemoryJwkPersister{keys: []models.Jwk{{ID: 1, State: models.JwkStatePending}}}
+
+	// when
+	hasKey, err := HasSigningKey(persister, tenantId)
+
+	// then
+	assert.NoError(t, err)
+	assert.False(t, hasKey)
+
+	// when
+	persister.keys = append(persister.keys, models.Jwk{ID: 2, State: models.JwkStateActive})
+	hasKey, err = HasSigningKey(persister, tenantId)
+
+	// then
+	assert.NoError(t, err)
+	assert.True(t, hasKey)
+}
@@ -1,10 +1,11 @@
 package jwt
 
 import (
+	"context"
 	"fmt"
 	"github.com/gofrs/uuid"
-	"github.com/lestrrat-go/jwx/v2/jwa"
 	"github.com/lestrrat-go/jwx/v2/jwk"
+	"github.com/lestrrat-go/jwx/v2/jws"
 	"github.com/lestrrat-go/jwx/v2/jwt"
 	hankoJwk "github.com/teamhanko/passkey-server/crypto/jwk"
 	"github.com/teamhanko/passkey-server/persistence/models"
@@ -14,11 +15,27 @@ import (
 type Generator interface {
 	Sign(jwt.Token) ([]byte, error)
 	Verify([]byte) (jwt.Token, error)
+	// VerifyToken verifies a JWT like Verify and checks that it was generated with Generate or GenerateForTransaction.
+	// Other tokens signed with the keys of the tenant, e.g. OpenID Connect id_tokens, are rejected.
+	VerifyToken([]byte) (jwt.Token, error)
 	Generate(userId string, credentialId string) (string, error)
 	GenerateForTransaction(userId string, credentialId string, transactionIdentifier string) (string, error)
+	GenerateIdToken(params IdTokenParams) (string, error)
+	GetTokenLifetime() int
+}
+
+// IdTokenParams contains the claims of an OpenID Connect id_token which depend on the authorization request
+type IdTokenParams struct {
+	Issuer   string
+	Subject  string
+	Audience string
+	Nonce    *string
+	AuthTime time.Time
+	Amr      []string
 }
 
 const (
+	// JwtExpirationDuration is used when no token lifetime is configured for the tenant
 	JwtExpirationDuration = 300 // 5 Min from Creation to Expire
 )
 
@@ -27,10 +44,12 @@ type generator struct {
 	signatureKey jwk.Key
 	verKeys      jwk.Set
 	config       *models.WebauthnConfig
+	jwtConfig    *models.JwtConfig
 }
 
-// NewGenerator returns a new jwt generator which signs JWTs with the given signing key and verifies JWTs with the given verificationKeys
-func NewGenerator(cfg *models.WebauthnConfig, jwkManager hankoJwk.Manager, tenantId uuid.UUID) (Generator, error) {
+// NewGenerator returns a new jwt generator which signs JWTs with the given signing key and verifies JWTs with the given verificationKeys.
+// The claims of generated tokens are configured by the given jwt config.
+func NewGenerator(cfg *models.WebauthnConfig, jwtConfig *models.JwtConfig, jwkManager hankoJwk.Manager, tenantId uuid.UUID) (Generator, error) {
 	signatureKey, err := jwkManager.GetSigningKey(tenantId)
 	const jwkGenFailure = "failed to create jwk jwtGenerator: %w"
 	if err != nil {
@@ -50,12 +69,19 @@ func NewGenerator(cfg *models.WebauthnConfig, jwkManager hankoJwk.Manager, tenan
 		signatureKey: signatureKey,
 		verKeys:      pubKeySet,
 		config:       cfg,
+		jwtConfig:    jwtConfig,
 	}, nil
 }
 
-// Sign a JWT with the signing key and returns it
+// Sign a JWT with the signing key and returns it. The algorithm and key id of the signing key are set as header.
 func (g *generator) Sign(token jwt.Token) ([]byte, error) {
-	signed, err := jwt.Sign(token, jwt.WithKey(jwa.RS256, g.signatureKey))
+	headers := jws.NewHeaders()
+	err := headers.Set(jws.KeyIDKey, g.signatureKey.KeyID())
+	if err != nil {
+		return nil, fmt.Errorf("failed to set kid header: %w", err)
+	}
+
+	signed, err := jwt.Sign(token, jwt.WithKey(g.signatureKey.Algorithm(), g.signatureKey, jws.WithProtectedHeaders(headers)))
 	if err != nil {
 		return nil, fmt.Errorf("failed to sign jwt: %w", err)
 	}
@@ -64,24 +90,70 @@ func (g *generator) Sign(token jwt.Token) ([]byte, error) {
 
 // Verify verifies a JWT, using the verificationKeys and returns the parsed JWT
 func (g *generator) Verify(signed []byte) (jwt.Token, error) {
-	token, err := jwt.Parse(signed, jwt.WithKeySet(g.verKeys))
+	token, err := jwt.Parse(signed, jwt.WithKeySet(g.verKeys, jws.WithInferAlgorithmFromKey(true)))
 	if err != nil {
 		return nil, fmt.Errorf("failed to verify jwt: %w", err)
 	}
 	return token, nil
 }
 
+func (g *generator) VerifyToken(signed []byte) (jwt.Token, error) {
+	issuer := ""
+	if g.jwtConfig != nil && g.jwtConfig.Issuer != nil {
+		issuer = *g.jwtConfig.Issuer
+	}
+
+	token, err := jwt.Parse(
+		signed,
+		jwt.WithKeySet(g.verKeys, jws.WithInferAlgorithmFromKey(true)),
+		jwt.WithAudience(g.config.RelyingParty.RPId),
+		jwt.WithValidator(jwt.ValidatorFunc(func(_ context.Context, token jwt.Token) jwt.ValidationError {
+			// tokens have no issuer claim when no issuer is configured for the tenant
+			if token.Issuer() != issuer {
+				return jwt.NewValidationError(fmt.Errorf("issuer '%s' does not match '%s'", token.Issuer(), issuer))
+			}
+
+			return nil
+		})),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify jwt: %w", err)
+	}
+
+	return token, nil
+}
+
+// GetTokenLifetime returns the time in seconds a generated token is valid
+func (g *generator) GetTokenLifetime() int {
+	if g.jwtConfig != nil && g.jwtConfig.TokenLifetime > 0 {
+		return g.jwtConfig.TokenLifetime
+	}
+
+	return JwtExpirationDuration
+}
+
 func (g *generator) generateDefaultToken(userId string, credentialId string) jwt.Token {
+	audiences := []string{g.config.RelyingParty.RPId}
+	if g.jwtConfig != nil {
+		audiences = append(audiences, g.jwtConfig.Audiences.GetValues()...)
+	}
+
 	issuedAt := time.Now()
-	expiresAt := issuedAt.Add(time.Second * JwtExpirationDuration)
+	expiresAt := issuedAt.Add(time.Second * time.Duration(g.GetTokenLifetime()))
+	tokenId, _ := uuid.NewV4()
 
 	token := jwt.New()
+	_ = token.Set(jwt.JwtIDKey, tokenId.String())
 	_ = token.Set(jwt.SubjectKey, userId)
 	_ = token.Set(jwt.IssuedAtKey, issuedAt)
 	_ = token.Set(jwt.ExpirationKey, expiresAt)
-	_ = token.Set(jwt.AudienceKey, []string{g.config.RelyingParty.RPId})
+	_ = token.Set(jwt.AudienceKey, audiences)
 	_ = token.Set("cred", credentialId)
 
+	if g.jwtConfig != nil && g.jwtConfig.Issuer != nil && *g.jwtConfig.Issuer != "" {
+		_ = token.Set(jwt.IssuerKey, *g.jwtConfig.Issuer)
+	}
+
 	return token
 }
 
@@ -105,3 +177,26 @@ func (g *generator) GenerateForTransaction(userId string, credentialId string, t
 
 	return g.signToken(token)
 }
+
+// GenerateIdToken creates a signed OpenID Connect id_token. The token is only valid for the requesting client.
+func (g *generator) GenerateIdToken(params IdTokenParams) (string, error) {
+	issuedAt := time.Now()
+	expiresAt := issuedAt.Add(time.Second * time.Duration(g.GetTokenLifetime()))
+	tokenId, _ := uuid.NewV4()
+
+	token := jwt.New()
+	_ = token.Set(jwt.JwtIDKey, tokenId.String())
+	_ = token.Set(jwt.IssuerKey, params.Issuer)
+	_ = token.Set(jwt.SubjectKey, params.Subject)
+	_ = token.Set(jwt.AudienceKey, []string{params.Audience})
+	_ = token.Set(jwt.IssuedAtKey, issuedAt)
+	_ = token.Set(jwt.ExpirationKey, expiresAt)
+	_ = token.Set("auth_time", params.AuthTime.Unix())
+	_ = token.Set("amr", params.Amr)
+
+	if params.Nonce != nil {
+		_ = token.Set("nonce", *params.Nonce)
+	}
+
+	return g.signToken(token)
+}
@@ -0,0 +1,205 @@
+package jwt
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/lestrrat-go/jwx/v2/jwk"
+	"github.com/lestrrat-go/jwx/v2/jws"
+	"github.com/stretchr/testify/assert"
+	hankoJwk "github.com/teamhanko/passkey-server/crypto/jwk"
+	"github.com/teamhanko/passkey-server/persistence/models"
+)
+
+func newTestGenerator(t *testing.T, signingKey jwk.Key, verificationKeys ...jwk.Key) *generator {
+	keySet := jwk.NewSet()
+	for _, key := range append(verificationKeys, signingKey) {
+		publicKey, err := jwk.PublicKeyOf(key)
+		assert.NoError(t, err)
+		assert.NoError(t, keySet.AddKey(publicKey))
+	}
+
+	return &generator{
+		signatureKey: signingKey,
+		verKeys:      keySet,
+		config: &models.WebauthnConfig{
+			RelyingParty: models.RelyingParty{RPId: "localhost"},
+		},
+	}
+}
+
+func TestGeneratorAppliesClaimConfig(t *testing.T) {
+	// given
+	issuer := "https://passkeys.example.com"
+	g := newTestGenerator(t, generateKey(t, jwa.ES256, "signing-key"))
+	g.jwtConfig = &models.JwtConfig{
+		Issuer:        &issuer,
+		Audiences:     models.JwtAudiences{{Audience: "backend"}, {Audience: "edge"}},
+		TokenLifetime: 60,
+	}
+
+	// when
+	token, err := g.GenerateForTransaction("user", "credential", "transaction")
+
+	// then
+	assert.NoError(t, err)
+
+	parsed, err := g.Verify([]byte(token))
+	assert.NoError(t, err)
+	assert.Equal(t, issuer, parsed.Issuer())
+	assert.Equal(t, []string{"localhost", "backend", "edge"}, parsed.Audience())
+	assert.Equal(t, time.Minute, parsed.Expiration().Sub(parsed.IssuedAt()))
+	assert.NotEmpty(t, parsed.JwtID())
+
+	transaction, _ := parsed.Get("trans")
+	assert.Equal(t, "transaction", transaction)
+}
+
+func TestGeneratorUsesDefaultClaims(t *testing.T) {
+	// given
+	g := newTestGenerator(t, generateKey(t, jwa.ES256, "signing-key"))
+
+	// when
+	first, err := g.Generate("user", "credential")
+	assert.NoError(t, err)
+	second, err := g.Generate("user", "credential")
+	assert.NoError(t, err)
+
+	// then
+	firstToken, err := g.Verify([]byte(first))
+	assert.NoError(t, err)
+	secondToken, err := g.Verify([]byte(second))
+	assert.NoError(t, err)
+
+	assert.Empty(t, firstToken.Issuer())
+	assert.Equal(t, []string{"localhost"}, firstToken.Audience())
+	assert.Equal(t, time.Duration(JwtExpirationDuration)*time.Second, firstToken.Expiration().Sub(firstToken.IssuedAt()))
+	assert.NotEqual(t, firstToken.JwtID(), secondToken.JwtID())
+}
+
+func TestGeneratorCreatesIdToken(t *testing.T) {
+	// given
+	g := newTestGenerator(t, generateKey(t, jwa.ES256, "signing-key"))
+	nonce := "nonce"
+	authTime := time.Now().Add(-time.Minute).Truncate(time.Second)
+
+	// when
+	idToken, err := g.GenerateIdToken(IdTokenParams{
+		Issuer:   "https://passkeys.example.com/tenant",
+		Subject:  "user",
+		Audience: "client",
+		Nonce:    &nonce,
+		AuthTime: authTime,
+		Amr:      []string{models.OidcAmrHardwareKey},
+	})
+
+	// then
+	assert.NoError(t, err)
+
+	parsed, err := g.Verify([]byte(idToken))
+	assert.NoError(t, err)
+	assert.Equal(t, "https://passkeys.example.com/tenant", parsed.Issuer())
+	assert.Equal(t, "user", parsed.Subject())
+	assert.Equal(t, []string{"client"}, parsed.Audience())
+
+	parsedNonce, _ := parsed.Get("nonce")
+	assert.Equal(t, nonce, parsedNonce)
+
+	parsedAuthTime, _ := parsed.Get("auth_time")
+	assert.Equal(t, float64(authTime.Unix()), parsedAuthTime)
+}
+
+func generateKey(t *testing.T, algorithm jwa.SignatureAlgorithm, id string) jwk.Key {
+	keyGenerator, err := hankoJwk.NewKeyGenerator(algorithm)
+	assert.NoError(t, err)
+
+	key, err := keyGenerator.Generate(id)
+	assert.NoError(t, err)
+
+	return key
+}
+
+func TestGeneratorSetsAlgorithmAndKeyId(t *testing.T) {
+	for _, algorithm := range []jwa.SignatureAlgorithm{jwa.ES256, jwa.EdDSA} {
+		t.Run(algorithm.String(), func(t *testing.T) {
+			// given
+			g := newTestGenerator(t, generateKey(t, algorithm, "signing-key"))
+
+			// when
+			token, err := g.Generate("user", "credential")
+
+			// then
+			assert.NoError(t, err)
+
+			message, err := jws.ParseString(token)
+			assert.NoError(t, err)
+			headers := message.Signatures()[0].ProtectedHeaders()
+			assert.Equal(t, algorithm, headers.Algorithm())
+			assert.Equal(t, "signing-key", headers.KeyID())
+
+			_, err = g.Verify([]byte(token))
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestGeneratorVerifiesTokenOfPreviousKey(t *testing.T) {
+	// given
+	oldKey := generateKey(t, jwa.ES256, "old-key")
+	oldGenerator := newTestGenerator(t, oldKey)
+	token, err := oldGenerator.Generate("user", "credential")
+	assert.NoError(t, err)
+
+	newGenerator := newTestGenerator(t, generateKey(t, jwa.EdDSA, "new-key"), oldKey)
+
+	// when
+	parsed, err := newGenerator.Verify([]byte(token))
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, "user", parsed.Subject())
+}
+
+func TestGeneratorVerifiesTokenClaims(t *testing.T) {
+	// given
+	issuer := "https://passkeys.example.com"
+	g := newTestGenerator(t, generateKey(t, jwa.ES256, "signing-key"))
+	g.jwtConfig = &models.JwtConfig{Issuer: &issuer}
+
+	token, err := g.Generate("user", "credential")
+	assert.NoError(t, err)
+
+	idToken, err := g.GenerateIdToken(IdTokenParams{
+		Issuer:   issuer,
+		Subject:  "user",
+		Audience: "client",
+		AuthTime: time.Now(),
+	})
+	assert.NoError(t, err)
+
+	// when
+	_, tokenErr := g.VerifyToken([]byte(token))
+	_, idTokenErr := g.VerifyToken([]byte(idToken))
+
+	// then
+	assert.NoError(t, tokenErr)
+	assert.Error(t, idTokenErr)
+}
+
+func TestGeneratorRejectsTokenOfOtherIssuer(t *testing.T) {
+	// given
+	issuer := "https://passkeys.example.com"
+	g := newTestGenerator(t, generateKey(t, jwa.ES256, "signing-key"))
+	g.jwtConfig = &models.JwtConfig{Issuer: &issuer}
+
+	token, err := g.Generate("user", "credential")
+	assert.NoError(t, err)
+
+	// when
+	g.jwtConfig = nil
+	_, err = g.VerifyToken([]byte(token))
+
+	// then
+	assert.Error(t, err)
+}
@@ -0,0 +1,19 @@
+package crypto
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+)
+
+// VerifyCodeChallengeS256 checks a PKCE code verifier against a code challenge created with the S256 method (RFC 7636).
+func VerifyCodeChallengeS256(codeChallenge string, codeVerifier string) bool {
+	if codeChallenge == "" || codeVerifier == "" {
+		return false
+	}
+
+	hash := sha256.Sum256([]byte(codeVerifier))
+	computed := base64.RawURLEncoding.EncodeToString(hash[:])
+
+	return subtle.ConstantTimeCompare([]byte(computed), []byte(codeChallenge)) == 1
+}
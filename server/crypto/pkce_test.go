@@ -0,0 +1,21 @@
+package crypto
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestVerifyCodeChallengeS256(t *testing.T) {
+	// given - example values of RFC 7636 appendix B
+	verifier := "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	challenge := "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"
+
+	// when
+	valid := VerifyCodeChallengeS256(challenge, verifier)
+
+	// then
+	assert.True(t, valid)
+	assert.False(t, VerifyCodeChallengeS256(challenge, "another-verifier"))
+	assert.False(t, VerifyCodeChallengeS256(challenge, ""))
+	assert.False(t, VerifyCodeChallengeS256("", verifier))
+}
@@ -0,0 +1,99 @@
+package janitor
+
+import (
+	"context"
+	"time"
+
+	"github.com/gobuffalo/pop/v6"
+	zeroLogger "github.com/rs/zerolog/log"
+	"github.com/teamhanko/passkey-server/config"
+	"github.com/teamhanko/passkey-server/persistence"
+)
+
+// Result contains the number of removed rows of a cleanup
+type Result struct {
+	SessionData    int
+	Transactions   int
+	Users          int
+	RedeemedTokens int
+}
+
+// Janitor removes data of abandoned ceremonies of all tenants
+type Janitor struct {
+	persister persistence.Persister
+	cfg       config.Janitor
+}
+
+func NewJanitor(persister persistence.Persister, cfg config.Janitor) *Janitor {
+	return &Janitor{
+		persister: persister,
+		cfg:       cfg,
+	}
+}
+
+// Run cleans up in the configured interval until the context is cancelled
+func (j *Janitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.cfg.GetInterval())
+	defer ticker.Stop()
+
+	for {
+		result, err := j.RunOnce()
+		if err != nil {
+			zeroLogger.Error().Err(err).Msg("failed to clean up")
+		} else {
+			zeroLogger.Info().
+				Int("session_data", result.SessionData).
+				Int("transactions", result.Transactions).
+				Int("users", result.Users).
+				Int("redeemed_tokens", result.RedeemedTokens).
+				Msg("cleaned up")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce removes expired session data together with the transactions which were never finalized and expired redeemed
+// tokens. Users without credentials are removed after the grace period when enabled.
+func (j *Janitor) RunOnce() (*Result, error) {
+	now := time.Now().UTC()
+	result := &Result{}
+
+	err := j.persister.Transaction(func(tx *pop.Connection) error {
+		var err error
+
+		// transactions have to be removed first, as they are identified by their expired session data
+		result.Transactions, err = j.persister.GetTransactionPersister(tx).DeleteAbandoned(now)
+		if err != nil {
+			return err
+		}
+
+		result.SessionData, err = j.persister.GetWebauthnSessionDataPersister(tx).DeleteExpired(now)
+		if err != nil {
+			return err
+		}
+
+		result.RedeemedTokens, err = j.persister.GetRedeemedTokenPersister(tx).DeleteExpired(now)
+		if err != nil {
+			return err
+		}
+
+		if j.cfg.RemoveUsersWithoutCredentials {
+			result.Users, err = j.persister.GetWebauthnUserPersister(tx).DeleteWithoutCredentials(now.Add(-j.cfg.GetUserGracePeriod()))
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,109 @@
+package janitor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gobuffalo/pop/v6"
+	"github.com/stretchr/testify/assert"
+	"github.com/teamhanko/passkey-server/config"
+	"github.com/teamhanko/passkey-server/persistence"
+	"github.com/teamhanko/passkey-server/persistence/persisters"
+)
+
+type testPersister struct {
+	persistence.Persister
+	users *testUserPersister
+}
+
+func (p *testPersister) Transaction(fn func(tx *pop.Connection) error) error {
+	return fn(nil)
+}
+
+func (p *testPersister) GetTransactionPersister(_ *pop.Connection) persisters.TransactionPersister {
+	return testTransactionPersister{}
+}
+
+func (p *testPersister) GetWebauthnSessionDataPersister(_ *pop.Connection) persisters.WebauthnSessionDataPersister {
+	return testSessionDataPersister{}
+}
+
+func (p *testPersister) GetRedeemedTokenPersister(_ *pop.Connection) persisters.RedeemedTokenPersister {
+	return testRedeemedTokenPersister{}
+}
+
+func (p *testPersister) GetWebauthnUserPersister(_ *pop.Connection) persisters.WebauthnUserPersister {
+	return p.users
+}
+
+type testTransactionPersister struct {
+	persisters.TransactionPersister
+}
+
+func (testTransactionPersister) DeleteAbandoned(_ time.Time) (int, error) {
+	return 1, nil
+}
+
+type testSessionDataPersister struct {
+	persisters.WebauthnSessionDataPersister
+}
+
+func (testSessionDataPersister) DeleteExpired(_ time.Time) (int, error) {
+	return 2, nil
+}
+
+type testRedeemedTokenPersister struct {
+	persisters.RedeemedTokenPersister
+}
+
+func (testRedeemedTokenPersister) DeleteExpired(_ time.Time) (int, error) {
+	return 3, nil
+}
+
+type testUserPersister struct {
+	persisters.WebauthnUserPersister
+	inactiveSince *time.Time
+}
+
+func (p *testUserPersister) DeleteWithoutCredentials(inactiveSince time.Time) (int, error) {
+	p.inactiveSince = &inactiveSince
+	return 4, nil
+}
+
+func TestJanitorRemovesInactiveUsersAfterGracePeriod(t *testing.T) {
+	// given
+	users := &testUserPersister{}
+	j := NewJanitor(&testPersister{users: users}, config.Janitor{
+		Interval:                      3600,
+		RemoveUsersWithoutCredentials: true,
+		UserGracePeriod:               86400,
+	})
+
+	// when
+	before := time.Now().UTC()
+	result, err := j.RunOnce()
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, &Result{Transactions: 1, SessionData: 2, RedeemedTokens: 3, Users: 4}, result)
+	assert.NotNil(t, users.inactiveSince)
+	assert.WithinDuration(t, before.Add(-24*time.Hour), *users.inactiveSince, time.Second)
+}
+
+func TestJanitorKeepsUsersWhenDisabled(t *testing.T) {
+	// given
+	users := &testUserPersister{}
+	j := NewJanitor(&testPersister{users: users}, config.Janitor{
+		Interval:                      3600,
+		RemoveUsersWithoutCredentials: false,
+		UserGracePeriod:               86400,
+	})
+
+	// when
+	result, err := j.RunOnce()
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, 0, result.Users)
+	assert.Nil(t, users.inactiveSince)
+}
@@ -0,0 +1,112 @@
+package keyrotation
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gobuffalo/pop/v6"
+	"github.com/gofrs/uuid"
+	zeroLogger "github.com/rs/zerolog/log"
+	"github.com/teamhanko/passkey-server/config"
+	hankoJwk "github.com/teamhanko/passkey-server/crypto/jwk"
+	"github.com/teamhanko/passkey-server/persistence"
+	"github.com/teamhanko/passkey-server/persistence/models"
+)
+
+// Rotator moves the jwks of all tenants through their lifecycle
+type Rotator struct {
+	persister persistence.Persister
+	cfg       config.KeyRotation
+}
+
+func NewRotator(persister persistence.Persister, cfg config.KeyRotation) *Rotator {
+	return &Rotator{
+		persister: persister,
+		cfg:       cfg,
+	}
+}
+
+// Run applies the key lifecycle in the configured interval until the context is cancelled
+func (r *Rotator) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.cfg.GetInterval())
+	defer ticker.Stop()
+
+	for {
+		err := r.RunOnce()
+		if err != nil {
+			zeroLogger.Error().Err(err).Msg("failed to rotate jwks")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce applies the key lifecycle of all tenants. A failing tenant does not stop the lifecycle of the others.
+func (r *Rotator) RunOnce() error {
+	tenants, err := r.persister.GetTenantPersister(nil).List()
+	if err != nil {
+		return err
+	}
+
+	for _, tenant := range tenants {
+		err = r.persister.Transaction(func(tx *pop.Connection) error {
+			return r.applyLifecycle(tx, tenant.ID)
+		})
+
+		if err != nil {
+			zeroLogger.Error().Err(err).Str("tenant_id", tenant.ID.String()).Msg("failed to rotate jwks of tenant")
+		}
+	}
+
+	return nil
+}
+
+// applyLifecycle locks the tenant, so the keys are never changed by several instances or a manual rotation at the
+// same time
+func (r *Rotator) applyLifecycle(tx *pop.Connection, tenantId uuid.UUID) error {
+	tenantPersister := r.persister.GetTenantPersister(tx)
+
+	err := tenantPersister.Lock(tenantId)
+	if err != nil {
+		return err
+	}
+
+	tenant, err := tenantPersister.Get(tenantId)
+	if err != nil {
+		return err
+	}
+
+	// the tenant was deleted in the meantime
+	if tenant == nil {
+		return nil
+	}
+
+	jwtConfig := tenant.Config.JwtConfig
+	if jwtConfig == nil || jwtConfig.ID == uuid.Nil {
+		jwtConfig = models.NewDefaultJwtConfig(tenant.Config.ID)
+
+		err = r.persister.GetJwtConfigPersister(tx).Create(jwtConfig)
+		if err != nil {
+			return fmt.Errorf("unable to create default jwt config: %w", err)
+		}
+	}
+
+	var keys []string
+	for _, secret := range tenant.Config.Secrets {
+		if !secret.IsAPISecret {
+			keys = append(keys, secret.Key)
+		}
+	}
+
+	manager, err := hankoJwk.NewDefaultManager(keys, tenant.ID, jwtConfig.SigningAlgorithm, r.persister.GetJwkPersister(tx))
+	if err != nil {
+		return err
+	}
+
+	return manager.ApplyLifecycle(tenant.ID, *jwtConfig)
+}
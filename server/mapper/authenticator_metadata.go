@@ -4,6 +4,7 @@ import (
 	_ "embed"
 	"encoding/json"
 	"fmt"
+	"github.com/go-webauthn/webauthn/metadata"
 	"github.com/gofrs/uuid"
 	kjson "github.com/knadh/koanf/parsers/json"
 	"github.com/teamhanko/passkey-server/utils"
@@ -17,6 +18,8 @@ type Authenticator struct {
 	Name      string `json:"name"`
 	IconLight string `json:"icon_light"`
 	IconDark  string `json:"icon_dark"`
+	// Metadata is only present for authenticators loaded from a FIDO MDS3 BLOB
+	Metadata *metadata.MetadataBLOBPayloadEntry `json:"-" koanf:"-"`
 }
 
 type AuthenticatorMetadata map[string]Authenticator
@@ -31,6 +34,16 @@ func (w AuthenticatorMetadata) GetNameForAaguid(aaguid uuid.UUID) *string {
 	return nil
 }
 
+func (w AuthenticatorMetadata) GetForAaguid(aaguid uuid.UUID) *Authenticator {
+	if w != nil {
+		if authenticator, ok := w[aaguid.String()]; ok {
+			return &authenticator
+		}
+	}
+
+	return nil
+}
+
 func LoadAuthenticatorMetadata(authenticatorMetadataFile *string) AuthenticatorMetadata {
 	k, err := utils.LoadFile(authenticatorMetadataFile, kjson.Parser())
 
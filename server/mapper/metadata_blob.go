@@ -0,0 +1,222 @@
+package mapper
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/go-webauthn/webauthn/metadata"
+	"github.com/gofrs/uuid"
+	"github.com/lestrrat-go/jwx/v2/jws"
+)
+
+// LoadMetadataBlob reads a FIDO MDS3 BLOB from a local file and verifies its signature. The signing certificate has to
+// chain to the given root certificate (PEM) or to the production root of the FIDO Alliance when no file is provided.
+func LoadMetadataBlob(blobFile string, rootCertificateFile *string) (*metadata.MetadataBLOBPayload, error) {
+	blob, err := os.ReadFile(blobFile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read metadata blob: %w", err)
+	}
+
+	root, err := loadMetadataRoot(rootCertificateFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseMetadataBlob(blob, root, time.Now())
+}
+
+// ParseMetadataBlob verifies the JWS of a FIDO MDS3 BLOB against the root certificate and returns its payload
+func ParseMetadataBlob(blob []byte, root *x509.Certificate, now time.Time) (*metadata.MetadataBLOBPayload, error) {
+	blob = bytes.TrimSpace(blob)
+
+	message, err := jws.Parse(blob)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse metadata blob: %w", err)
+	}
+
+	if len(message.Signatures()) != 1 {
+		return nil, errors.New("metadata blob must contain exactly one signature")
+	}
+
+	headers := message.Signatures()[0].ProtectedHeaders()
+	chain := headers.X509CertChain()
+	if chain == nil || chain.Len() == 0 {
+		return nil, errors.New("metadata blob does not contain a certificate chain")
+	}
+
+	certificates := make([]*x509.Certificate, 0, chain.Len())
+	for i := 0; i < chain.Len(); i++ {
+		encoded, _ := chain.Get(i)
+		certificate, err := parseBase64Certificate(string(encoded))
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse certificate of metadata blob: %w", err)
+		}
+
+		certificates = append(certificates, certificate)
+	}
+
+	err = verifyCertificateChain(certificates[0], certificates[1:], []*x509.Certificate{root}, now)
+	if err != nil {
+		return nil, fmt.Errorf("unable to verify certificate chain of metadata blob: %w", err)
+	}
+
+	payload, err := jws.Verify(blob, jws.WithKey(headers.Algorithm(), certificates[0].PublicKey))
+	if err != nil {
+		return nil, fmt.Errorf("unable to verify signature of metadata blob: %w", err)
+	}
+
+	var metadataPayload metadata.MetadataBLOBPayload
+	err = json.Unmarshal(payload, &metadataPayload)
+	if err != nil {
+		return nil, fmt.Errorf("unable to unmarshal metadata blob: %w", err)
+	}
+
+	nextUpdate, err := time.Parse("2006-01-02", metadataPayload.NextUpdate)
+	if err == nil && now.After(nextUpdate) {
+		log.Printf("metadata blob no. %d is outdated since %s. Please provide a current blob.", metadataPayload.Number, metadataPayload.NextUpdate)
+	}
+
+	return &metadataPayload, nil
+}
+
+// WithMetadataBlob returns a copy of the authenticator metadata in which all authenticators of the blob are added or
+// replaced. Names and icons are taken from the metadata statements.
+func (w AuthenticatorMetadata) WithMetadataBlob(payload *metadata.MetadataBLOBPayload) AuthenticatorMetadata {
+	authenticatorMetadata := make(AuthenticatorMetadata, len(w)+len(payload.Entries))
+	for aaguid, authenticator := range w {
+		authenticatorMetadata[aaguid] = authenticator
+	}
+
+	for i := range payload.Entries {
+		entry := payload.Entries[i]
+
+		// entries without aaguid belong to U2F or UAF authenticators
+		aaguid, err := uuid.FromString(entry.AaGUID)
+		if err != nil || aaguid.IsNil() {
+			continue
+		}
+
+		authenticatorMetadata[aaguid.String()] = Authenticator{
+			Name:      entry.MetadataStatement.Description,
+			IconLight: entry.MetadataStatement.Icon,
+			IconDark:  entry.MetadataStatement.Icon,
+			Metadata:  &entry,
+		}
+	}
+
+	return authenticatorMetadata
+}
+
+func loadMetadataRoot(rootCertificateFile *string) (*x509.Certificate, error) {
+	if rootCertificateFile == nil || *rootCertificateFile == "" {
+		return parseBase64Certificate(metadata.ProductionMDSRoot)
+	}
+
+	content, err := os.ReadFile(*rootCertificateFile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read metadata root certificate: %w", err)
+	}
+
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return nil, errors.New("metadata root certificate must be PEM encoded")
+	}
+
+	return x509.ParseCertificate(block.Bytes)
+}
+
+func parseBase64Certificate(encoded string) (*x509.Certificate, error) {
+	der, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+
+	return x509.ParseCertificate(der)
+}
+
+func verifyCertificateChain(leaf *x509.Certificate, intermediates []*x509.Certificate, roots []*x509.Certificate, now time.Time) error {
+	rootPool := x509.NewCertPool()
+	for _, root := range roots {
+		rootPool.AddCert(root)
+	}
+
+	intermediatePool := x509.NewCertPool()
+	for _, intermediate := range intermediates {
+		intermediatePool.AddCert(intermediate)
+	}
+
+	_, err := leaf.Verify(x509.VerifyOptions{
+		Roots:         rootPool,
+		Intermediates: intermediatePool,
+		CurrentTime:   now,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+
+	return err
+}
+
+// IsRevoked returns true when the metadata service reported the authenticator as revoked or compromised
+func (a *Authenticator) IsRevoked() bool {
+	if a.Metadata == nil {
+		return false
+	}
+
+	for _, report := range a.Metadata.StatusReports {
+		if metadata.IsUndesiredAuthenticatorStatus(report.Status) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// VerifyAttestationCertificates checks that the attestation certificate (first element of x5c) chains to one of the
+// attestation root certificates of the metadata statement
+func (a *Authenticator) VerifyAttestationCertificates(x5c [][]byte, now time.Time) error {
+	if a.Metadata == nil {
+		return errors.New("no metadata statement available for authenticator")
+	}
+
+	if len(x5c) == 0 {
+		return errors.New("attestation does not contain a certificate chain")
+	}
+
+	roots := make([]*x509.Certificate, 0, len(a.Metadata.MetadataStatement.AttestationRootCertificates))
+	for _, encoded := range a.Metadata.MetadataStatement.AttestationRootCertificates {
+		root, err := parseBase64Certificate(encoded)
+		if err != nil {
+			return fmt.Errorf("unable to parse attestation root certificate: %w", err)
+		}
+
+		roots = append(roots, root)
+	}
+
+	if len(roots) == 0 {
+		return errors.New("metadata statement does not contain attestation root certificates")
+	}
+
+	certificates := make([]*x509.Certificate, 0, len(x5c))
+	for _, der := range x5c {
+		certificate, err := x509.ParseCertificate(der)
+		if err != nil {
+			return fmt.Errorf("unable to parse attestation certificate: %w", err)
+		}
+
+		certificates = append(certificates, certificate)
+	}
+
+	err := verifyCertificateChain(certificates[0], certificates[1:], roots, now)
+	if err != nil {
+		return fmt.Errorf("unable to verify attestation certificate chain: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,147 @@
+package mapper
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/go-webauthn/webauthn/metadata"
+	"github.com/gofrs/uuid"
+	"github.com/lestrrat-go/jwx/v2/cert"
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/lestrrat-go/jwx/v2/jws"
+	"github.com/stretchr/testify/assert"
+)
+
+const testAaguid = "cb69481e-8ff7-4039-93ec-0a2729a154a8"
+
+func createTestCertificate(t *testing.T, name string, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  parent == nil,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+	}
+
+	if parent == nil {
+		parent = template
+		parentKey = key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	assert.NoError(t, err)
+
+	certificate, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+
+	return certificate, key
+}
+
+func createTestBlob(t *testing.T, signer *x509.Certificate, signerKey *ecdsa.PrivateKey, payload metadata.MetadataBLOBPayload) []byte {
+	chain := &cert.Chain{}
+	assert.NoError(t, chain.AddString(base64.StdEncoding.EncodeToString(signer.Raw)))
+
+	headers := jws.NewHeaders()
+	assert.NoError(t, headers.Set(jws.X509CertChainKey, chain))
+
+	rawPayload, err := json.Marshal(payload)
+	assert.NoError(t, err)
+
+	blob, err := jws.Sign(rawPayload, jws.WithKey(jwa.ES256, signerKey, jws.WithProtectedHeaders(headers)))
+	assert.NoError(t, err)
+
+	return blob
+}
+
+func createTestPayload(attestationRoot *x509.Certificate, status metadata.AuthenticatorStatus) metadata.MetadataBLOBPayload {
+	return metadata.MetadataBLOBPayload{
+		Number:     1,
+		NextUpdate: time.Now().Add(24 * time.Hour).Format("2006-01-02"),
+		Entries: []metadata.MetadataBLOBPayloadEntry{
+			{
+				AaGUID: testAaguid,
+				MetadataStatement: metadata.MetadataStatement{
+					AaGUID:                      testAaguid,
+					Description:                 "Test Authenticator",
+					Icon:                        "data:image/png;base64,iVBORw0KGgo=",
+					AttestationRootCertificates: []string{base64.StdEncoding.EncodeToString(attestationRoot.Raw)},
+				},
+				StatusReports: []metadata.StatusReport{{Status: status}},
+			},
+		},
+	}
+}
+
+func TestParseMetadataBlob(t *testing.T) {
+	// given
+	root, rootKey := createTestCertificate(t, "MDS Root", nil, nil)
+	signer, signerKey := createTestCertificate(t, "MDS Signer", root, rootKey)
+	attestationRoot, _ := createTestCertificate(t, "Attestation Root", nil, nil)
+
+	blob := createTestBlob(t, signer, signerKey, createTestPayload(attestationRoot, metadata.FidoCertified))
+
+	// when
+	payload, err := ParseMetadataBlob(blob, root, time.Now())
+
+	// then
+	assert.NoError(t, err)
+	assert.Len(t, payload.Entries, 1)
+	assert.Equal(t, testAaguid, payload.Entries[0].AaGUID)
+}
+
+func TestParseMetadataBlobWithUntrustedSigner(t *testing.T) {
+	// given
+	root, _ := createTestCertificate(t, "MDS Root", nil, nil)
+	otherRoot, otherRootKey := createTestCertificate(t, "Other Root", nil, nil)
+	signer, signerKey := createTestCertificate(t, "MDS Signer", otherRoot, otherRootKey)
+	attestationRoot, _ := createTestCertificate(t, "Attestation Root", nil, nil)
+
+	blob := createTestBlob(t, signer, signerKey, createTestPayload(attestationRoot, metadata.FidoCertified))
+
+	// when
+	payload, err := ParseMetadataBlob(blob, root, time.Now())
+
+	// then
+	assert.Error(t, err)
+	assert.Nil(t, payload)
+}
+
+func TestWithMetadataBlob(t *testing.T) {
+	// given
+	attestationRoot, attestationRootKey := createTestCertificate(t, "Attestation Root", nil, nil)
+	attestationCertificate, _ := createTestCertificate(t, "Attestation", attestationRoot, attestationRootKey)
+	otherRoot, otherRootKey := createTestCertificate(t, "Other Root", nil, nil)
+	otherCertificate, _ := createTestCertificate(t, "Other Attestation", otherRoot, otherRootKey)
+
+	existing := AuthenticatorMetadata{
+		testAaguid: Authenticator{Name: "Outdated Name"},
+	}
+	payload := createTestPayload(attestationRoot, metadata.Revoked)
+
+	// when
+	authenticatorMetadata := existing.WithMetadataBlob(&payload)
+	authenticator := authenticatorMetadata.GetForAaguid(uuid.FromStringOrNil(testAaguid))
+
+	// then
+	assert.Equal(t, "Outdated Name", existing[testAaguid].Name)
+	assert.NotNil(t, authenticator)
+	assert.Equal(t, "Test Authenticator", authenticator.Name)
+	assert.Equal(t, "data:image/png;base64,iVBORw0KGgo=", authenticator.IconLight)
+	assert.True(t, authenticator.IsRevoked())
+	assert.NoError(t, authenticator.VerifyAttestationCertificates([][]byte{attestationCertificate.Raw}, time.Now()))
+	assert.Error(t, authenticator.VerifyAttestationCertificates([][]byte{otherCertificate.Raw}, time.Now()))
+	assert.Error(t, authenticator.VerifyAttestationCertificates(nil, time.Now()))
+}
@@ -0,0 +1,17 @@
+-- existing api keys keep access to all routes
+INSERT INTO secret_scopes (id, name, secret_id)
+SELECT UUID(), scopes.name, s.id
+FROM secrets s
+CROSS JOIN (
+    SELECT 'credentials:read' AS name
+    UNION ALL SELECT 'credentials:write'
+    UNION ALL SELECT 'registration:init'
+    UNION ALL SELECT 'login:init'
+    UNION ALL SELECT 'transaction:write'
+    UNION ALL SELECT 'mfa'
+    UNION ALL SELECT 'audit_logs:read'
+) scopes
+WHERE s.is_api_secret = true;
+
+-- api keys are only stored as sha256 hash
+UPDATE secrets SET `key` = SHA2(`key`, 256) WHERE is_api_secret = true;
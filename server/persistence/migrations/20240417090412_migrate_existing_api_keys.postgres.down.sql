@@ -0,0 +1,2 @@
+-- api keys can't be restored from their hash, only their scopes are removed
+DELETE FROM secret_scopes;
@@ -0,0 +1,92 @@
+package models
+
+import (
+	"time"
+
+	"github.com/gobuffalo/pop/v6"
+	"github.com/gobuffalo/validate/v3"
+	"github.com/gobuffalo/validate/v3/validators"
+	"github.com/gofrs/uuid"
+)
+
+// AaguidPolicyFlow is the ceremony an AAGUID policy applies to
+type AaguidPolicyFlow string
+
+var (
+	AaguidPolicyFlowPasskey AaguidPolicyFlow = "passkey"
+	AaguidPolicyFlowMfa     AaguidPolicyFlow = "mfa"
+)
+
+// AaguidPolicyType defines whether an authenticator model is allowed or denied
+type AaguidPolicyType string
+
+var (
+	// AaguidPolicyTypeAllow restricts the registration to the allowed authenticator models, as soon as one is configured
+	AaguidPolicyTypeAllow AaguidPolicyType = "allow"
+	// AaguidPolicyTypeDeny rejects the registration of the authenticator model
+	AaguidPolicyTypeDeny AaguidPolicyType = "deny"
+)
+
+// AaguidPolicy is used by pop to map your aaguid_policies database table to your go code.
+type AaguidPolicy struct {
+	ID        uuid.UUID        `json:"id" db:"id"`
+	Aaguid    uuid.UUID        `json:"aaguid" db:"aaguid"`
+	Flow      AaguidPolicyFlow `json:"flow" db:"flow"`
+	Type      AaguidPolicyType `json:"type" db:"type"`
+	Config    *Config          `json:"config" belongs_to:"configs"`
+	ConfigID  uuid.UUID        `json:"config_id" db:"config_id"`
+	CreatedAt time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time        `json:"updated_at" db:"updated_at"`
+}
+
+type AaguidPolicies []AaguidPolicy
+
+// GetAaguids returns the AAGUIDs of the given flow and type
+func (policies AaguidPolicies) GetAaguids(flow AaguidPolicyFlow, policyType AaguidPolicyType) []string {
+	aaguids := make([]string, 0)
+	for _, policy := range policies {
+		if policy.Flow == flow && policy.Type == policyType {
+			aaguids = append(aaguids, policy.Aaguid.String())
+		}
+	}
+
+	return aaguids
+}
+
+// IsAllowed returns false if the AAGUID is denied for the flow or if an allowlist exists for the flow which does not
+// contain the AAGUID
+func (policies AaguidPolicies) IsAllowed(aaguid uuid.UUID, flow AaguidPolicyFlow) bool {
+	hasAllowlist := false
+	isAllowed := false
+
+	for _, policy := range policies {
+		if policy.Flow != flow {
+			continue
+		}
+
+		switch policy.Type {
+		case AaguidPolicyTypeDeny:
+			if policy.Aaguid == aaguid {
+				return false
+			}
+		case AaguidPolicyTypeAllow:
+			hasAllowlist = true
+			if policy.Aaguid == aaguid {
+				isAllowed = true
+			}
+		}
+	}
+
+	return !hasAllowlist || isAllowed
+}
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+func (policy *AaguidPolicy) Validate(_ *pop.Connection) (*validate.Errors, error) {
+	return validate.Validate(
+		&validators.UUIDIsPresent{Name: "ID", Field: policy.ID},
+		&validators.StringInclusion{Name: "Flow", Field: string(policy.Flow), List: []string{string(AaguidPolicyFlowPasskey), string(AaguidPolicyFlowMfa)}},
+		&validators.StringInclusion{Name: "Type", Field: string(policy.Type), List: []string{string(AaguidPolicyTypeAllow), string(AaguidPolicyTypeDeny)}},
+		&validators.TimeIsPresent{Name: "UpdatedAt", Field: policy.UpdatedAt},
+		&validators.TimeIsPresent{Name: "CreatedAt", Field: policy.CreatedAt},
+	), nil
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAaguidPoliciesDenylist(t *testing.T) {
+	// given
+	denied, _ := uuid.NewV4()
+	other, _ := uuid.NewV4()
+	policies := AaguidPolicies{
+		{Aaguid: denied, Flow: AaguidPolicyFlowPasskey, Type: AaguidPolicyTypeDeny},
+	}
+
+	// when
+	deniedResult := policies.IsAllowed(denied, AaguidPolicyFlowPasskey)
+	otherResult := policies.IsAllowed(other, AaguidPolicyFlowPasskey)
+	mfaResult := policies.IsAllowed(denied, AaguidPolicyFlowMfa)
+
+	// then
+	assert.False(t, deniedResult)
+	assert.True(t, otherResult)
+	assert.True(t, mfaResult)
+}
+
+func TestAaguidPoliciesAllowlist(t *testing.T) {
+	// given
+	allowed, _ := uuid.NewV4()
+	other, _ := uuid.NewV4()
+	policies := AaguidPolicies{
+		{Aaguid: allowed, Flow: AaguidPolicyFlowMfa, Type: AaguidPolicyTypeAllow},
+	}
+
+	// when
+	allowedResult := policies.IsAllowed(allowed, AaguidPolicyFlowMfa)
+	otherResult := policies.IsAllowed(other, AaguidPolicyFlowMfa)
+	unknownResult := policies.IsAllowed(uuid.Nil, AaguidPolicyFlowMfa)
+	passkeyResult := policies.IsAllowed(other, AaguidPolicyFlowPasskey)
+
+	// then
+	assert.True(t, allowedResult)
+	assert.False(t, otherResult)
+	assert.False(t, unknownResult)
+	assert.True(t, passkeyResult)
+}
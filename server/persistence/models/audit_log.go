@@ -36,9 +36,23 @@ var (
 	AuditLogWebAuthnAuthenticationFinalSucceeded AuditLogType = "webauthn_authentication_final_succeeded"
 	AuditLogWebAuthnAuthenticationFinalFailed    AuditLogType = "webauthn_authentication_final_failed"
 
+	AuditLogWebAuthnConditionalAuthenticationInitSucceeded  AuditLogType = "webauthn_conditional_authentication_init_succeeded"
+	AuditLogWebAuthnConditionalAuthenticationInitFailed     AuditLogType = "webauthn_conditional_authentication_init_failed"
+	AuditLogWebAuthnConditionalAuthenticationFinalSucceeded AuditLogType = "webauthn_conditional_authentication_final_succeeded"
+	AuditLogWebAuthnConditionalAuthenticationFinalFailed    AuditLogType = "webauthn_conditional_authentication_final_failed"
+
 	AuditLogWebAuthnCredentialUpdated AuditLogType = "webauthn_credential_updated"
 	AuditLogWebAuthnCredentialDeleted AuditLogType = "webauthn_credential_deleted"
 
+	AuditLogWebAuthnCredentialCloneWarning AuditLogType = "webauthn_credential_clone_warning"
+
+	AuditLogWebAuthnCredentialBackupEligibilityChanged AuditLogType = "webauthn_credential_backup_eligibility_changed"
+	AuditLogWebAuthnCredentialBackupStateChanged       AuditLogType = "webauthn_credential_backup_state_changed"
+
+	AuditLogWebAuthnAuthenticatorRejected AuditLogType = "webauthn_authenticator_rejected"
+
+	AuditLogWebAuthnBackupPolicyViolated AuditLogType = "webauthn_backup_policy_violated"
+
 	AuditLogWebAuthnTransactionInitFailed    AuditLogType = "webauthn_transaction_init_failed"
 	AuditLogWebAuthnTransactionInitSucceeded AuditLogType = "webauthn_transaction_init_succeeded"
 
@@ -54,4 +68,60 @@ var (
 	AuditLogMfaAuthenticationInitFailed     AuditLogType = "mfa_authentication_init_failed"
 	AuditLogMfaAuthenticationFinalSucceeded AuditLogType = "mfa_authentication_final_succeeded"
 	AuditLogMfaAuthenticationFinalFailed    AuditLogType = "mfa_authentication_final_failed"
+
+	AuditLogTokenRedeemSucceeded AuditLogType = "token_redeem_succeeded"
+	AuditLogTokenRedeemFailed    AuditLogType = "token_redeem_failed"
+
+	AuditLogOidcTokenExchangeSucceeded AuditLogType = "oidc_token_exchange_succeeded"
+	AuditLogOidcTokenExchangeFailed    AuditLogType = "oidc_token_exchange_failed"
 )
+
+// AllAuditLogTypes contains every type an audit log can have
+var AllAuditLogTypes = []AuditLogType{
+	AuditLogWebAuthnRegistrationInitSucceeded,
+	AuditLogWebAuthnRegistrationInitFailed,
+	AuditLogWebAuthnRegistrationFinalSucceeded,
+	AuditLogWebAuthnRegistrationFinalFailed,
+	AuditLogWebAuthnAuthenticationInitSucceeded,
+	AuditLogWebAuthnAuthenticationInitFailed,
+	AuditLogWebAuthnAuthenticationFinalSucceeded,
+	AuditLogWebAuthnAuthenticationFinalFailed,
+	AuditLogWebAuthnConditionalAuthenticationInitSucceeded,
+	AuditLogWebAuthnConditionalAuthenticationInitFailed,
+	AuditLogWebAuthnConditionalAuthenticationFinalSucceeded,
+	AuditLogWebAuthnConditionalAuthenticationFinalFailed,
+	AuditLogWebAuthnCredentialUpdated,
+	AuditLogWebAuthnCredentialDeleted,
+	AuditLogWebAuthnCredentialCloneWarning,
+	AuditLogWebAuthnCredentialBackupEligibilityChanged,
+	AuditLogWebAuthnCredentialBackupStateChanged,
+	AuditLogWebAuthnAuthenticatorRejected,
+	AuditLogWebAuthnBackupPolicyViolated,
+	AuditLogWebAuthnTransactionInitFailed,
+	AuditLogWebAuthnTransactionInitSucceeded,
+	AuditLogWebAuthnTransactionFinalFailed,
+	AuditLogWebAuthnTransactionFinalSucceeded,
+	AuditLogMfaRegistrationInitFailed,
+	AuditLogMfaRegistrationInitSucceeded,
+	AuditLogMfaRegistrationFinalSucceeded,
+	AuditLogMfaRegistrationFinalFailed,
+	AuditLogMfaAuthenticationInitSucceeded,
+	AuditLogMfaAuthenticationInitFailed,
+	AuditLogMfaAuthenticationFinalSucceeded,
+	AuditLogMfaAuthenticationFinalFailed,
+	AuditLogTokenRedeemSucceeded,
+	AuditLogTokenRedeemFailed,
+	AuditLogOidcTokenExchangeSucceeded,
+	AuditLogOidcTokenExchangeFailed,
+}
+
+// IsValidAuditLogType returns true if the type is a known audit log type
+func IsValidAuditLogType(auditLogType AuditLogType) bool {
+	for _, knownType := range AllAuditLogTypes {
+		if knownType == auditLogType {
+			return true
+		}
+	}
+
+	return false
+}
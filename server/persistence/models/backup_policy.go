@@ -0,0 +1,79 @@
+package models
+
+import (
+	"time"
+
+	"github.com/gobuffalo/pop/v6"
+	"github.com/gobuffalo/validate/v3"
+	"github.com/gobuffalo/validate/v3/validators"
+	"github.com/gofrs/uuid"
+)
+
+// BackupPolicyFlow is the ceremony a backup policy applies to
+type BackupPolicyFlow string
+
+var (
+	BackupPolicyFlowPasskey     BackupPolicyFlow = "passkey"
+	BackupPolicyFlowMfa         BackupPolicyFlow = "mfa"
+	BackupPolicyFlowTransaction BackupPolicyFlow = "transaction"
+)
+
+// BackupRequirement defines which credentials are accepted based on the backup eligibility (BE) and backup state (BS)
+// flags of the authenticator data
+type BackupRequirement string
+
+var (
+	// BackupRequirementAny accepts all credentials
+	BackupRequirementAny BackupRequirement = "any"
+	// BackupRequirementDeviceBound only accepts credentials which can not be backed up (BE and BS not set)
+	BackupRequirementDeviceBound BackupRequirement = "device_bound"
+	// BackupRequirementSynced only accepts credentials which are backed up (BE and BS set)
+	BackupRequirementSynced BackupRequirement = "synced"
+)
+
+// IsSatisfiedBy returns true if the flags of the authenticator data fulfill the requirement
+func (requirement BackupRequirement) IsSatisfiedBy(backupEligible bool, backupState bool) bool {
+	switch requirement {
+	case BackupRequirementDeviceBound:
+		return !backupEligible && !backupState
+	case BackupRequirementSynced:
+		return backupEligible && backupState
+	default:
+		return true
+	}
+}
+
+// BackupPolicy is used by pop to map your backup_policies database table to your go code.
+type BackupPolicy struct {
+	ID          uuid.UUID         `json:"id" db:"id"`
+	Flow        BackupPolicyFlow  `json:"flow" db:"flow"`
+	Requirement BackupRequirement `json:"requirement" db:"requirement"`
+	Config      *Config           `json:"config" belongs_to:"configs"`
+	ConfigID    uuid.UUID         `json:"config_id" db:"config_id"`
+	CreatedAt   time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at" db:"updated_at"`
+}
+
+type BackupPolicies []BackupPolicy
+
+// GetRequirement returns the requirement of the flow or BackupRequirementAny when no policy exists for the flow
+func (policies BackupPolicies) GetRequirement(flow BackupPolicyFlow) BackupRequirement {
+	for _, policy := range policies {
+		if policy.Flow == flow {
+			return policy.Requirement
+		}
+	}
+
+	return BackupRequirementAny
+}
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+func (policy *BackupPolicy) Validate(_ *pop.Connection) (*validate.Errors, error) {
+	return validate.Validate(
+		&validators.UUIDIsPresent{Name: "ID", Field: policy.ID},
+		&validators.StringInclusion{Name: "Flow", Field: string(policy.Flow), List: []string{string(BackupPolicyFlowPasskey), string(BackupPolicyFlowMfa), string(BackupPolicyFlowTransaction)}},
+		&validators.StringInclusion{Name: "Requirement", Field: string(policy.Requirement), List: []string{string(BackupRequirementAny), string(BackupRequirementDeviceBound), string(BackupRequirementSynced)}},
+		&validators.TimeIsPresent{Name: "UpdatedAt", Field: policy.UpdatedAt},
+		&validators.TimeIsPresent{Name: "CreatedAt", Field: policy.CreatedAt},
+	), nil
+}
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackupPoliciesGetRequirement(t *testing.T) {
+	// given
+	policies := BackupPolicies{
+		{Flow: BackupPolicyFlowMfa, Requirement: BackupRequirementDeviceBound},
+	}
+
+	// when
+	mfaRequirement := policies.GetRequirement(BackupPolicyFlowMfa)
+	passkeyRequirement := policies.GetRequirement(BackupPolicyFlowPasskey)
+
+	// then
+	assert.Equal(t, BackupRequirementDeviceBound, mfaRequirement)
+	assert.Equal(t, BackupRequirementAny, passkeyRequirement)
+}
+
+func TestBackupRequirementIsSatisfiedBy(t *testing.T) {
+	// given
+	tests := []struct {
+		requirement    BackupRequirement
+		backupEligible bool
+		backupState    bool
+		expected       bool
+	}{
+		{BackupRequirementAny, false, false, true},
+		{BackupRequirementAny, true, true, true},
+		{BackupRequirementDeviceBound, false, false, true},
+		{BackupRequirementDeviceBound, true, false, false},
+		{BackupRequirementDeviceBound, true, true, false},
+		{BackupRequirementSynced, true, true, true},
+		{BackupRequirementSynced, true, false, false},
+		{BackupRequirementSynced, false, false, false},
+	}
+
+	for _, test := range tests {
+		// when
+		result := test.requirement.IsSatisfiedBy(test.backupEligible, test.backupState)
+
+		// then
+		assert.Equal(t, test.expected, result, "requirement '%s' with BE '%t' and BS '%t'", test.requirement, test.backupEligible, test.backupState)
+	}
+}
@@ -16,11 +16,17 @@ type Config struct {
 	TenantID uuid.UUID `json:"tenant_id" db:"tenant_id"`
 	Tenant   *Tenant   `json:"tenant,omitempty" belongs_to:"tenant"`
 
-	WebauthnConfig WebauthnConfig `json:"webauthn_config,omitempty" has_one:"webauthn_config"`
-	MfaConfig      *MfaConfig     `json:"mfa_config,omitempty" has_one:"mfa_config"`
-	Cors           Cors           `json:"cors,omitempty" has_one:"cor"`
-	AuditLogConfig AuditLogConfig `json:"audit_log_config,omitempty" has_one:"audit_log_config"`
-	Secrets        Secrets        `json:"secrets,omitempty" has_many:"secrets"`
+	WebauthnConfig        WebauthnConfig          `json:"webauthn_config,omitempty" has_one:"webauthn_config"`
+	MfaConfig             *MfaConfig              `json:"mfa_config,omitempty" has_one:"mfa_config"`
+	JwtConfig             *JwtConfig              `json:"jwt_config,omitempty" has_one:"jwt_config"`
+	Cors                  Cors                    `json:"cors,omitempty" has_one:"cor"`
+	AuditLogConfig        AuditLogConfig          `json:"audit_log_config,omitempty" has_one:"audit_log_config"`
+	Secrets               Secrets                 `json:"secrets,omitempty" has_many:"secrets"`
+	OidcClients           OidcClients             `json:"oidc_clients,omitempty" has_many:"oidc_clients"`
+	AaguidPolicies        AaguidPolicies          `json:"aaguid_policies,omitempty" has_many:"aaguid_policies"`
+	BackupPolicies        BackupPolicies          `json:"backup_policies,omitempty" has_many:"backup_policies"`
+	CeremonyConfigs       WebauthnCeremonyConfigs `json:"ceremony_configs,omitempty" has_many:"webauthn_ceremony_configs"`
+	RegistrationOverrides RegistrationOverrides   `json:"registration_overrides,omitempty" has_many:"registration_overrides"`
 
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"github.com/gobuffalo/pop/v6"
+	"github.com/gobuffalo/validate/v3"
+	"github.com/gobuffalo/validate/v3/validators"
+	"github.com/gofrs/uuid"
+)
+
+// ConfigVersion is used by pop to map your config_versions database table to your go code. Versions are immutable
+// snapshots of the tenant config in the format of the update config request.
+type ConfigVersion struct {
+	ID      uuid.UUID `json:"id" db:"id"`
+	Version int       `json:"version" db:"version"`
+	Config  string    `json:"config" db:"config"`
+	// Actor is the subject of the admin credentials which made the change, nil if the version was taken from a config
+	// which existed before the versioning
+	Actor *string `json:"actor" db:"actor"`
+	// RollbackOf is the version which was restored by this version
+	RollbackOf *int      `json:"rollback_of" db:"rollback_of"`
+	Tenant     *Tenant   `json:"-" belongs_to:"tenants"`
+	TenantID   uuid.UUID `json:"tenant_id" db:"tenant_id"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
+
+type ConfigVersions []ConfigVersion
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+func (version *ConfigVersion) Validate(_ *pop.Connection) (*validate.Errors, error) {
+	return validate.Validate(
+		&validators.UUIDIsPresent{Name: "ID", Field: version.ID},
+		&validators.IntIsGreaterThan{Name: "Version", Field: version.Version, Compared: 0},
+		&validators.StringIsPresent{Name: "Config", Field: version.Config},
+		&validators.UUIDIsPresent{Name: "TenantID", Field: version.TenantID},
+		&validators.TimeIsPresent{Name: "CreatedAt", Field: version.CreatedAt},
+	), nil
+}
@@ -2,6 +2,7 @@ package models
 
 import (
 	"github.com/gofrs/uuid"
+	"github.com/lestrrat-go/jwx/v2/jwa"
 	"time"
 
 	"github.com/gobuffalo/validate/v3/validators"
@@ -16,15 +17,42 @@ type Jwk struct {
 	TenantID uuid.UUID `json:"tenant_id" db:"tenant_id"`
 	Tenant   *Tenant   `json:"tenant" belongs_to:"tenants"`
 	KeyData  string    `json:"key_data" db:"key_data"`
+	State    JwkState  `json:"state" db:"state"`
+	// Algorithm is the signature algorithm the key is used with
+	Algorithm jwa.SignatureAlgorithm `json:"algorithm" db:"algorithm"`
 
-	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	ActivatedAt *time.Time `json:"activated_at" db:"activated_at"`
+	RetiredAt   *time.Time `json:"retired_at" db:"retired_at"`
+	RevokedAt   *time.Time `json:"revoked_at" db:"revoked_at"`
 }
 
 type Jwks []Jwk
 
+// JwkState describes the lifecycle of a jwk
+type JwkState string
+
+var (
+	// JwkStatePending keys are published but not used for signing yet
+	JwkStatePending JwkState = "pending"
+	// JwkStateActive keys are published and used for signing
+	JwkStateActive JwkState = "active"
+	// JwkStateRetiring keys are still published to verify tokens but not used for signing anymore
+	JwkStateRetiring JwkState = "retiring"
+	// JwkStateRevoked keys are neither published nor used for signing
+	JwkStateRevoked JwkState = "revoked"
+)
+
+// IsPublished returns true if the key is part of the public key set
+func (jwk *Jwk) IsPublished() bool {
+	return jwk.State == JwkStatePending || jwk.State == JwkStateActive || jwk.State == JwkStateRetiring
+}
+
 func (jwk *Jwk) Validate(tx *pop.Connection) (*validate.Errors, error) {
 	return validate.Validate(
 		&validators.StringIsPresent{Name: "KeyData", Field: jwk.KeyData},
+		&validators.StringIsPresent{Name: "Algorithm", Field: jwk.Algorithm.String()},
+		&validators.StringInclusion{Name: "State", Field: string(jwk.State), List: []string{string(JwkStatePending), string(JwkStateActive), string(JwkStateRetiring), string(JwkStateRevoked)}},
 		&validators.TimeIsPresent{Name: "CreatedAt", Field: jwk.CreatedAt},
 	), nil
 }
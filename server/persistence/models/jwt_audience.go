@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"github.com/gobuffalo/pop/v6"
+	"github.com/gobuffalo/validate/v3"
+	"github.com/gobuffalo/validate/v3/validators"
+	"github.com/gofrs/uuid"
+)
+
+// JwtAudience is used by pop to map your jwt_audiences database table to your go code.
+type JwtAudience struct {
+	ID          uuid.UUID  `json:"id" db:"id"`
+	JwtConfig   *JwtConfig `json:"jwt_config" belongs_to:"jwt_configs"`
+	JwtConfigID uuid.UUID  `json:"jwt_config_id" db:"jwt_config_id"`
+	Audience    string     `json:"audience" db:"audience"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+type JwtAudiences []JwtAudience
+
+// GetValues returns the audiences as a list of strings
+func (audiences JwtAudiences) GetValues() []string {
+	values := make([]string, 0, len(audiences))
+	for _, audience := range audiences {
+		values = append(values, audience.Audience)
+	}
+
+	return values
+}
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+func (audience *JwtAudience) Validate(_ *pop.Connection) (*validate.Errors, error) {
+	return validate.Validate(
+		&validators.UUIDIsPresent{Name: "ID", Field: audience.ID},
+		&validators.StringIsPresent{Name: "Audience", Field: audience.Audience},
+		&validators.TimeIsPresent{Name: "UpdatedAt", Field: audience.UpdatedAt},
+		&validators.TimeIsPresent{Name: "CreatedAt", Field: audience.CreatedAt},
+	), nil
+}
@@ -0,0 +1,83 @@
+package models
+
+import (
+	"time"
+
+	"github.com/gobuffalo/pop/v6"
+	"github.com/gobuffalo/validate/v3"
+	"github.com/gobuffalo/validate/v3/validators"
+	"github.com/gofrs/uuid"
+	"github.com/lestrrat-go/jwx/v2/jwa"
+)
+
+const (
+	DefaultJwkRotationInterval = 90 * 24 * 60 * 60 // 90 days
+	DefaultJwkPendingDuration  = 60 * 60           // 1 hour
+	DefaultJwkRetiringDuration = 24 * 60 * 60      // 1 day
+	DefaultJwtTokenLifetime    = 5 * 60            // 5 minutes
+)
+
+// JwtConfig is used by pop to map your jwt_configs database table to your go code.
+type JwtConfig struct {
+	ID       uuid.UUID `json:"id" db:"id"`
+	Config   *Config   `json:"config" belongs_to:"configs"`
+	ConfigID uuid.UUID `json:"config_id" db:"config_id"`
+	// KeyRotationInterval is the time in seconds after which a new signing key is generated. 0 disables the rotation.
+	KeyRotationInterval int `json:"key_rotation_interval" db:"key_rotation_interval"`
+	// KeyPendingDuration is the time in seconds a new key is published before it is used for signing.
+	KeyPendingDuration int `json:"key_pending_duration" db:"key_pending_duration"`
+	// KeyRetiringDuration is the time in seconds a replaced key is still published for verification before it gets revoked.
+	KeyRetiringDuration int `json:"key_retiring_duration" db:"key_retiring_duration"`
+	// SigningAlgorithm is the algorithm of newly generated signing keys.
+	SigningAlgorithm jwa.SignatureAlgorithm `json:"signing_algorithm" db:"signing_algorithm"`
+	// Issuer is set as iss claim. The claim is omitted when no issuer is configured.
+	Issuer *string `json:"issuer" db:"issuer"`
+	// Audiences are added to the aud claim in addition to the relying party id.
+	Audiences JwtAudiences `json:"audiences" has_many:"jwt_audiences"`
+	// TokenLifetime is the time in seconds a token is valid after it was issued.
+	TokenLifetime int       `json:"token_lifetime" db:"token_lifetime"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// SupportedJwtSigningAlgorithms contains all algorithms which can be used to sign tokens
+var SupportedJwtSigningAlgorithms = []jwa.SignatureAlgorithm{jwa.RS256, jwa.PS256, jwa.ES256, jwa.EdDSA}
+
+const DefaultJwtSigningAlgorithm = jwa.RS256
+
+func NewDefaultJwtConfig(configId uuid.UUID) *JwtConfig {
+	id, _ := uuid.NewV4()
+	now := time.Now()
+
+	return &JwtConfig{
+		ID:                  id,
+		ConfigID:            configId,
+		KeyRotationInterval: DefaultJwkRotationInterval,
+		KeyPendingDuration:  DefaultJwkPendingDuration,
+		KeyRetiringDuration: DefaultJwkRetiringDuration,
+		SigningAlgorithm:    DefaultJwtSigningAlgorithm,
+		Audiences:           make(JwtAudiences, 0),
+		TokenLifetime:       DefaultJwtTokenLifetime,
+		CreatedAt:           now,
+		UpdatedAt:           now,
+	}
+}
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+func (jwtConfig *JwtConfig) Validate(_ *pop.Connection) (*validate.Errors, error) {
+	var algorithms []string
+	for _, algorithm := range SupportedJwtSigningAlgorithms {
+		algorithms = append(algorithms, algorithm.String())
+	}
+
+	return validate.Validate(
+		&validators.UUIDIsPresent{Name: "ID", Field: jwtConfig.ID},
+		&validators.IntIsGreaterThan{Name: "KeyRotationInterval", Field: jwtConfig.KeyRotationInterval, Compared: -1},
+		&validators.IntIsGreaterThan{Name: "KeyPendingDuration", Field: jwtConfig.KeyPendingDuration, Compared: -1},
+		&validators.IntIsGreaterThan{Name: "KeyRetiringDuration", Field: jwtConfig.KeyRetiringDuration, Compared: -1},
+		&validators.IntIsGreaterThan{Name: "TokenLifetime", Field: jwtConfig.TokenLifetime, Compared: 0},
+		&validators.StringInclusion{Name: "SigningAlgorithm", Field: jwtConfig.SigningAlgorithm.String(), List: algorithms},
+		&validators.TimeIsPresent{Name: "UpdatedAt", Field: jwtConfig.UpdatedAt},
+		&validators.TimeIsPresent{Name: "CreatedAt", Field: jwtConfig.CreatedAt},
+	), nil
+}
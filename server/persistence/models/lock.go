@@ -0,0 +1,6 @@
+package models
+
+// Lock is used by pop to map your locks database table to your go code.
+type Lock struct {
+	Name string `json:"name" db:"name"`
+}
@@ -22,6 +22,9 @@ type MfaConfig struct {
 	Attachment             protocol.AuthenticatorAttachment     `json:"attachment" db:"attachment"`
 	AttestationPreference  protocol.ConveyancePreference        `json:"attestation_preference" db:"attestation_preference"`
 	ResidentKeyRequirement protocol.ResidentKeyRequirement      `json:"resident_key_requirement" db:"resident_key_requirement"`
+
+	RequireTrustedAttestation   bool `json:"require_trusted_attestation" db:"require_trusted_attestation"`
+	RejectRevokedAuthenticators bool `json:"reject_revoked_authenticators" db:"reject_revoked_authenticators"`
 }
 
 // Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
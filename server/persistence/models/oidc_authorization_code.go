@@ -0,0 +1,61 @@
+package models
+
+import (
+	"time"
+
+	"github.com/gobuffalo/pop/v6"
+	"github.com/gobuffalo/validate/v3"
+	"github.com/gobuffalo/validate/v3/validators"
+	"github.com/gofrs/uuid"
+)
+
+// OidcAuthorizationCode is used by pop to map your oidc_authorization_codes database table to your go code.
+// The code is only stored as hash and can be exchanged once for an id_token.
+type OidcAuthorizationCode struct {
+	ID            uuid.UUID `json:"id" db:"id"`
+	Code          string    `json:"-" db:"code"`
+	ClientID      string    `json:"client_id" db:"client_id"`
+	RedirectUri   string    `json:"redirect_uri" db:"redirect_uri"`
+	UserID        string    `json:"user_id" db:"user_id"`
+	CredentialID  string    `json:"credential_id" db:"credential_id"`
+	Nonce         *string   `json:"nonce" db:"nonce"`
+	CodeChallenge string    `json:"code_challenge" db:"code_challenge"`
+	Amr           string    `json:"amr" db:"amr"`
+	AuthTime      time.Time `json:"auth_time" db:"auth_time"`
+	ExpiresAt     time.Time `json:"expires_at" db:"expires_at"`
+	Tenant        *Tenant   `json:"-" belongs_to:"tenants"`
+	TenantID      uuid.UUID `json:"tenant_id" db:"tenant_id"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`
+}
+
+const (
+	// OidcAmrHardwareKey is used for credentials which are bound to a single authenticator
+	OidcAmrHardwareKey = "hwk"
+	// OidcAmrSoftwareKey is used for credentials which can be synced between authenticators
+	OidcAmrSoftwareKey = "swk"
+)
+
+// IsExpired returns true if the code can not be exchanged anymore
+func (code *OidcAuthorizationCode) IsExpired(now time.Time) bool {
+	return !code.ExpiresAt.After(now)
+}
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+func (code *OidcAuthorizationCode) Validate(_ *pop.Connection) (*validate.Errors, error) {
+	return validate.Validate(
+		&validators.UUIDIsPresent{Name: "ID", Field: code.ID},
+		&validators.StringIsPresent{Name: "Code", Field: code.Code},
+		&validators.StringIsPresent{Name: "ClientID", Field: code.ClientID},
+		&validators.StringIsPresent{Name: "RedirectUri", Field: code.RedirectUri},
+		&validators.StringIsPresent{Name: "UserID", Field: code.UserID},
+		&validators.StringIsPresent{Name: "CredentialID", Field: code.CredentialID},
+		&validators.StringIsPresent{Name: "CodeChallenge", Field: code.CodeChallenge},
+		&validators.StringInclusion{Name: "Amr", Field: code.Amr, List: []string{OidcAmrHardwareKey, OidcAmrSoftwareKey}},
+		&validators.UUIDIsPresent{Name: "TenantID", Field: code.TenantID},
+		&validators.TimeIsPresent{Name: "AuthTime", Field: code.AuthTime},
+		&validators.TimeIsPresent{Name: "ExpiresAt", Field: code.ExpiresAt},
+		&validators.TimeIsPresent{Name: "UpdatedAt", Field: code.UpdatedAt},
+		&validators.TimeIsPresent{Name: "CreatedAt", Field: code.CreatedAt},
+	), nil
+}
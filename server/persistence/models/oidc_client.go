@@ -0,0 +1,87 @@
+package models
+
+import (
+	"time"
+
+	"github.com/gobuffalo/pop/v6"
+	"github.com/gobuffalo/validate/v3"
+	"github.com/gobuffalo/validate/v3/validators"
+	"github.com/gofrs/uuid"
+)
+
+// OidcClient is used by pop to map your oidc_clients database table to your go code.
+type OidcClient struct {
+	ID           uuid.UUID        `json:"id" db:"id"`
+	ClientID     string           `json:"client_id" db:"client_id"`
+	Config       *Config          `json:"config" belongs_to:"configs"`
+	ConfigID     uuid.UUID        `json:"config_id" db:"config_id"`
+	RedirectUris OidcRedirectUris `json:"redirect_uris" has_many:"oidc_redirect_uris"`
+	CreatedAt    time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time        `json:"updated_at" db:"updated_at"`
+}
+
+type OidcClients []OidcClient
+
+// FindByClientId returns the client with the given client id or nil if the client is not registered
+func (clients OidcClients) FindByClientId(clientId string) *OidcClient {
+	for i := range clients {
+		if clients[i].ClientID == clientId {
+			return &clients[i]
+		}
+	}
+
+	return nil
+}
+
+// HasRedirectUri returns true if the redirect uri is registered for the client. Uris are compared by simple string comparison.
+func (client *OidcClient) HasRedirectUri(redirectUri string) bool {
+	for _, uri := range client.RedirectUris {
+		if uri.RedirectUri == redirectUri {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+func (client *OidcClient) Validate(_ *pop.Connection) (*validate.Errors, error) {
+	return validate.Validate(
+		&validators.UUIDIsPresent{Name: "ID", Field: client.ID},
+		&validators.StringIsPresent{Name: "ClientID", Field: client.ClientID},
+		&validators.TimeIsPresent{Name: "UpdatedAt", Field: client.UpdatedAt},
+		&validators.TimeIsPresent{Name: "CreatedAt", Field: client.CreatedAt},
+	), nil
+}
+
+// OidcRedirectUri is used by pop to map your oidc_redirect_uris database table to your go code.
+type OidcRedirectUri struct {
+	ID           uuid.UUID   `json:"id" db:"id"`
+	RedirectUri  string      `json:"redirect_uri" db:"redirect_uri"`
+	OidcClient   *OidcClient `json:"oidc_client" belongs_to:"oidc_clients"`
+	OidcClientID uuid.UUID   `json:"oidc_client_id" db:"oidc_client_id"`
+	CreatedAt    time.Time   `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time   `json:"updated_at" db:"updated_at"`
+}
+
+type OidcRedirectUris []OidcRedirectUri
+
+// GetValues returns the redirect uris as a list of strings
+func (uris OidcRedirectUris) GetValues() []string {
+	values := make([]string, 0, len(uris))
+	for _, uri := range uris {
+		values = append(values, uri.RedirectUri)
+	}
+
+	return values
+}
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+func (uri *OidcRedirectUri) Validate(_ *pop.Connection) (*validate.Errors, error) {
+	return validate.Validate(
+		&validators.UUIDIsPresent{Name: "ID", Field: uri.ID},
+		&validators.StringIsPresent{Name: "RedirectUri", Field: uri.RedirectUri},
+		&validators.TimeIsPresent{Name: "UpdatedAt", Field: uri.UpdatedAt},
+		&validators.TimeIsPresent{Name: "CreatedAt", Field: uri.CreatedAt},
+	), nil
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/gobuffalo/pop/v6"
+	"github.com/gobuffalo/validate/v3"
+	"github.com/gobuffalo/validate/v3/validators"
+	"github.com/gofrs/uuid"
+)
+
+// RedeemedToken is used by pop to map your redeemed_tokens database table to your go code.
+// It marks a token (identified by its jti) as consumed, so it can not be redeemed a second time.
+type RedeemedToken struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	TokenID   string    `json:"token_id" db:"token_id"`
+	UserID    *string   `json:"user_id" db:"user_id"`
+	ExpiresAt time.Time `json:"expires_at" db:"expires_at"`
+	Tenant    *Tenant   `json:"-" belongs_to:"tenants"`
+	TenantID  uuid.UUID `json:"tenant_id" db:"tenant_id"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+func (token *RedeemedToken) Validate(_ *pop.Connection) (*validate.Errors, error) {
+	return validate.Validate(
+		&validators.UUIDIsPresent{Name: "ID", Field: token.ID},
+		&validators.StringIsPresent{Name: "TokenID", Field: token.TokenID},
+		&validators.UUIDIsPresent{Name: "TenantID", Field: token.TenantID},
+		&validators.TimeIsPresent{Name: "ExpiresAt", Field: token.ExpiresAt},
+		&validators.TimeIsPresent{Name: "UpdatedAt", Field: token.UpdatedAt},
+		&validators.TimeIsPresent{Name: "CreatedAt", Field: token.CreatedAt},
+	), nil
+}
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"time"
+
+	"github.com/gobuffalo/pop/v6"
+	"github.com/gobuffalo/validate/v3"
+	"github.com/gobuffalo/validate/v3/validators"
+	"github.com/gofrs/uuid"
+)
+
+// RegistrationOverrideOption is an option of the credential creation which can be set per registration
+type RegistrationOverrideOption string
+
+var (
+	RegistrationOverrideOptionAuthenticatorAttachment RegistrationOverrideOption = "authenticator_attachment"
+	RegistrationOverrideOptionResidentKey             RegistrationOverrideOption = "resident_key"
+	RegistrationOverrideOptionUserVerification        RegistrationOverrideOption = "user_verification"
+	RegistrationOverrideOptionAttestation             RegistrationOverrideOption = "attestation"
+	RegistrationOverrideOptionHints                   RegistrationOverrideOption = "hints"
+)
+
+// RegistrationOverride is used by pop to map your registration_overrides database table to your go code. Each entry
+// allows one value of an option to be requested on registration.
+type RegistrationOverride struct {
+	ID        uuid.UUID                  `json:"id" db:"id"`
+	Option    RegistrationOverrideOption `json:"option" db:"option_name"`
+	Value     string                     `json:"value" db:"value"`
+	Config    *Config                    `json:"config" belongs_to:"configs"`
+	ConfigID  uuid.UUID                  `json:"config_id" db:"config_id"`
+	CreatedAt time.Time                  `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time                  `json:"updated_at" db:"updated_at"`
+}
+
+type RegistrationOverrides []RegistrationOverride
+
+// GetValues returns the allowed values of the option
+func (overrides RegistrationOverrides) GetValues(option RegistrationOverrideOption) []string {
+	values := make([]string, 0)
+	for _, override := range overrides {
+		if override.Option == option {
+			values = append(values, override.Value)
+		}
+	}
+
+	return values
+}
+
+// IsAllowed returns true if the value of the option may be requested on registration
+func (overrides RegistrationOverrides) IsAllowed(option RegistrationOverrideOption, value string) bool {
+	for _, override := range overrides {
+		if override.Option == option && override.Value == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+func (override *RegistrationOverride) Validate(_ *pop.Connection) (*validate.Errors, error) {
+	return validate.Validate(
+		&validators.UUIDIsPresent{Name: "ID", Field: override.ID},
+		&validators.StringInclusion{Name: "Option", Field: string(override.Option), List: []string{string(RegistrationOverrideOptionAuthenticatorAttachment), string(RegistrationOverrideOptionResidentKey), string(RegistrationOverrideOptionUserVerification), string(RegistrationOverrideOptionAttestation), string(RegistrationOverrideOptionHints)}},
+		&validators.StringIsPresent{Name: "Value", Field: override.Value},
+		&validators.TimeIsPresent{Name: "UpdatedAt", Field: override.UpdatedAt},
+		&validators.TimeIsPresent{Name: "CreatedAt", Field: override.CreatedAt},
+	), nil
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistrationOverridesIsAllowed(t *testing.T) {
+	// given
+	overrides := RegistrationOverrides{
+		{Option: RegistrationOverrideOptionAuthenticatorAttachment, Value: "platform"},
+		{Option: RegistrationOverrideOptionAuthenticatorAttachment, Value: "cross-platform"},
+		{Option: RegistrationOverrideOptionHints, Value: "security-key"},
+	}
+
+	// when
+	platformAllowed := overrides.IsAllowed(RegistrationOverrideOptionAuthenticatorAttachment, "platform")
+	hybridAllowed := overrides.IsAllowed(RegistrationOverrideOptionHints, "hybrid")
+	userVerificationAllowed := overrides.IsAllowed(RegistrationOverrideOptionUserVerification, "required")
+
+	// then
+	assert.True(t, platformAllowed)
+	assert.False(t, hybridAllowed)
+	assert.False(t, userVerificationAllowed)
+	assert.Equal(t, []string{"platform", "cross-platform"}, overrides.GetValues(RegistrationOverrideOptionAuthenticatorAttachment))
+	assert.Empty(t, overrides.GetValues(RegistrationOverrideOptionResidentKey))
+}
@@ -2,6 +2,8 @@ package models
 
 import (
 	"github.com/gobuffalo/validate/v3/validators"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/gobuffalo/pop/v6"
@@ -25,6 +27,32 @@ type RelyingParty struct {
 // RelyingParties is not required by pop and may be deleted
 type RelyingParties []RelyingParty
 
+// GetRelatedOrigins returns the origins which are published in the related origins document
+func (rp *RelyingParty) GetRelatedOrigins() []string {
+	relatedOrigins := make([]string, 0)
+	for _, origin := range rp.Origins {
+		if origin.IsRelated {
+			relatedOrigins = append(relatedOrigins, origin.Origin)
+		}
+	}
+
+	return relatedOrigins
+}
+
+// IsOriginOfRPId returns true if the host of a web origin is the RP ID or one of its subdomains. Origins with other
+// schemes, e.g. of android apps, are not bound to the RP ID and always return true.
+func IsOriginOfRPId(origin string, rpId string) bool {
+	originUrl, err := url.Parse(origin)
+	if err != nil || (originUrl.Scheme != "http" && originUrl.Scheme != "https") {
+		return true
+	}
+
+	host := strings.ToLower(originUrl.Hostname())
+	rpId = strings.ToLower(rpId)
+
+	return host == rpId || strings.HasSuffix(host, "."+rpId)
+}
+
 // Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
 // This method is not required and may be deleted.
 func (rp *RelyingParty) Validate(tx *pop.Connection) (*validate.Errors, error) {
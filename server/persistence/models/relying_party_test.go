@@ -0,0 +1,47 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsOriginOfRPId(t *testing.T) {
+	// given
+	tests := []struct {
+		origin   string
+		expected bool
+	}{
+		{"https://example.com", true},
+		{"https://login.example.com", true},
+		{"https://Login.Example.com:8443", true},
+		{"http://localhost:3000", false},
+		{"https://example.co.uk", false},
+		{"https://notexample.com", false},
+		{"android:apk-key-hash:abc", true},
+	}
+
+	for _, test := range tests {
+		// when
+		result := IsOriginOfRPId(test.origin, "example.com")
+
+		// then
+		assert.Equal(t, test.expected, result, "origin '%s'", test.origin)
+	}
+}
+
+func TestRelyingPartyGetRelatedOrigins(t *testing.T) {
+	// given
+	rp := RelyingParty{
+		Origins: WebauthnOrigins{
+			{Origin: "https://example.com"},
+			{Origin: "https://example.co.uk", IsRelated: true},
+		},
+	}
+
+	// when
+	relatedOrigins := rp.GetRelatedOrigins()
+
+	// then
+	assert.Equal(t, []string{"https://example.co.uk"}, relatedOrigins)
+}
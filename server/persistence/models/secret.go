@@ -10,19 +10,27 @@ import (
 
 // Secret is used by pop to map your api_keys database table to your go code.
 type Secret struct {
-	ID          uuid.UUID `json:"id" db:"id"`
-	Name        string    `json:"name" db:"name"`
-	Key         string    `json:"key" db:"key"`
-	IsAPISecret bool      `json:"is_api_secret" db:"is_api_secret"`
-	ConfigID    uuid.UUID `json:"config_id" db:"config_id"`
-	Config      *Config   `json:"config" belongs_to:"configs"`
-	CreatedAt   time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+	ID          uuid.UUID    `json:"id" db:"id"`
+	Name        string       `json:"name" db:"name"`
+	Key         string       `json:"key" db:"key"`
+	IsAPISecret bool         `json:"is_api_secret" db:"is_api_secret"`
+	Scopes      SecretScopes `json:"scopes" has_many:"secret_scopes"`
+	ExpiresAt   *time.Time   `json:"expires_at" db:"expires_at"`
+	LastUsedAt  *time.Time   `json:"last_used_at" db:"last_used_at"`
+	ConfigID    uuid.UUID    `json:"config_id" db:"config_id"`
+	Config      *Config      `json:"config" belongs_to:"configs"`
+	CreatedAt   time.Time    `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at" db:"updated_at"`
 }
 
 // Secrets is not required by pop and may be deleted
 type Secrets []Secret
 
+// IsExpired returns true when the secret has an expiry date which lies in the past
+func (secret *Secret) IsExpired(now time.Time) bool {
+	return secret.ExpiresAt != nil && !secret.ExpiresAt.After(now)
+}
+
 // Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
 // This method is not required and may be deleted.
 func (secret *Secret) Validate(tx *pop.Connection) (*validate.Errors, error) {
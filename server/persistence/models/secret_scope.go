@@ -0,0 +1,89 @@
+package models
+
+import (
+	"github.com/gobuffalo/pop/v6"
+	"github.com/gobuffalo/validate/v3"
+	"github.com/gobuffalo/validate/v3/validators"
+	"github.com/gofrs/uuid"
+)
+
+// SecretScope is used by pop to map your secret_scopes database table to your go code.
+type SecretScope struct {
+	ID       uuid.UUID   `json:"id" db:"id"`
+	Name     ApiKeyScope `json:"name" db:"name"`
+	SecretID uuid.UUID   `json:"secret_id" db:"secret_id"`
+	Secret   *Secret     `json:"-" belongs_to:"secrets"`
+}
+
+type SecretScopes []SecretScope
+
+type ApiKeyScope string
+
+var (
+	ApiKeyScopeCredentialsRead  ApiKeyScope = "credentials:read"
+	ApiKeyScopeCredentialsWrite ApiKeyScope = "credentials:write"
+	ApiKeyScopeRegistrationInit ApiKeyScope = "registration:init"
+	ApiKeyScopeLoginInit        ApiKeyScope = "login:init"
+	ApiKeyScopeTransactionWrite ApiKeyScope = "transaction:write"
+	ApiKeyScopeMfa              ApiKeyScope = "mfa"
+	ApiKeyScopeAuditLogsRead    ApiKeyScope = "audit_logs:read"
+	ApiKeyScopeTokenIntrospect  ApiKeyScope = "token:introspect"
+)
+
+// AllApiKeyScopes contains every scope an api key can be granted
+var AllApiKeyScopes = []ApiKeyScope{
+	ApiKeyScopeCredentialsRead,
+	ApiKeyScopeCredentialsWrite,
+	ApiKeyScopeRegistrationInit,
+	ApiKeyScopeLoginInit,
+	ApiKeyScopeTransactionWrite,
+	ApiKeyScopeMfa,
+	ApiKeyScopeAuditLogsRead,
+	ApiKeyScopeTokenIntrospect,
+}
+
+func NewSecretScopes(secretId uuid.UUID, names []ApiKeyScope) SecretScopes {
+	scopes := make(SecretScopes, 0, len(names))
+	for _, name := range names {
+		id, _ := uuid.NewV4()
+		scopes = append(scopes, SecretScope{
+			ID:       id,
+			Name:     name,
+			SecretID: secretId,
+		})
+	}
+
+	return scopes
+}
+
+func (scopes SecretScopes) GetNames() []string {
+	names := make([]string, len(scopes))
+	for i, scope := range scopes {
+		names[i] = string(scope.Name)
+	}
+	return names
+}
+
+func (scopes SecretScopes) Contains(scope ApiKeyScope) bool {
+	for _, s := range scopes {
+		if s.Name == scope {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+func (scope *SecretScope) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	names := make([]string, len(AllApiKeyScopes))
+	for i, s := range AllApiKeyScopes {
+		names[i] = string(s)
+	}
+
+	return validate.Validate(
+		&validators.UUIDIsPresent{Name: "ID", Field: scope.ID},
+		&validators.UUIDIsPresent{Name: "SecretID", Field: scope.SecretID},
+		&validators.StringInclusion{Name: "Name", Field: string(scope.Name), List: names},
+	), nil
+}
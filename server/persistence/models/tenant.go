@@ -13,6 +13,8 @@ import (
 type Tenant struct {
 	ID          uuid.UUID `json:"id" db:"id"`
 	DisplayName string    `json:"display_name" db:"display_name"`
+	// UserEnumerationKey derives the fake credentials of unknown users. It is created on first use.
+	UserEnumerationKey *string `json:"-" db:"user_enumeration_key"`
 
 	Config        Config                `json:"config" has_one:"config"`
 	AuditLogs     AuditLogs             `json:"audit_logs,omitempty" has_many:"audit_logs"`
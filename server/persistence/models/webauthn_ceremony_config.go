@@ -0,0 +1,91 @@
+package models
+
+import (
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/gobuffalo/pop/v6"
+	"github.com/gobuffalo/validate/v3"
+	"github.com/gobuffalo/validate/v3/validators"
+	"github.com/gofrs/uuid"
+)
+
+// WebauthnCeremony is the passkey ceremony a ceremony config applies to
+type WebauthnCeremony string
+
+var (
+	WebauthnCeremonyRegistration     WebauthnCeremony = "registration"
+	WebauthnCeremonyLogin            WebauthnCeremony = "login"
+	WebauthnCeremonyConditionalLogin WebauthnCeremony = "conditional_login"
+	WebauthnCeremonyTransaction      WebauthnCeremony = "transaction"
+)
+
+// WebauthnCeremonyConfig is used by pop to map your webauthn_ceremony_configs database table to your go code. Fields
+// which are not set fall back to the values of the WebauthnConfig.
+type WebauthnCeremonyConfig struct {
+	ID               uuid.UUID                             `json:"id" db:"id"`
+	Ceremony         WebauthnCeremony                      `json:"ceremony" db:"ceremony"`
+	Timeout          *int                                  `json:"timeout" db:"timeout"`
+	UserVerification *protocol.UserVerificationRequirement `json:"user_verification" db:"user_verification"`
+	Attachment       *protocol.AuthenticatorAttachment     `json:"attachment" db:"attachment"`
+	Config           *Config                               `json:"config" belongs_to:"configs"`
+	ConfigID         uuid.UUID                             `json:"config_id" db:"config_id"`
+	CreatedAt        time.Time                             `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time                             `json:"updated_at" db:"updated_at"`
+}
+
+type WebauthnCeremonyConfigs []WebauthnCeremonyConfig
+
+// WebauthnCeremonySettings are the resolved settings of a ceremony
+type WebauthnCeremonySettings struct {
+	Timeout          int
+	UserVerification protocol.UserVerificationRequirement
+	// Attachment is nil when every authenticator attachment is accepted
+	Attachment *protocol.AuthenticatorAttachment
+}
+
+// GetSettings returns the settings of the ceremony. Missing values are taken from the webauthn config: conditional
+// logins use the conditional mediation timeout and only registrations use the attachment of the webauthn config.
+func (configs WebauthnCeremonyConfigs) GetSettings(ceremony WebauthnCeremony, webauthnConfig WebauthnConfig) WebauthnCeremonySettings {
+	settings := WebauthnCeremonySettings{
+		Timeout:          webauthnConfig.Timeout,
+		UserVerification: webauthnConfig.UserVerification,
+	}
+
+	switch ceremony {
+	case WebauthnCeremonyRegistration:
+		settings.Attachment = webauthnConfig.Attachment
+	case WebauthnCeremonyConditionalLogin:
+		settings.Timeout = webauthnConfig.ConditionalMediationTimeout
+	}
+
+	for _, config := range configs {
+		if config.Ceremony != ceremony {
+			continue
+		}
+
+		if config.Timeout != nil {
+			settings.Timeout = *config.Timeout
+		}
+
+		if config.UserVerification != nil {
+			settings.UserVerification = *config.UserVerification
+		}
+
+		if config.Attachment != nil {
+			settings.Attachment = config.Attachment
+		}
+	}
+
+	return settings
+}
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+func (config *WebauthnCeremonyConfig) Validate(_ *pop.Connection) (*validate.Errors, error) {
+	return validate.Validate(
+		&validators.UUIDIsPresent{Name: "ID", Field: config.ID},
+		&validators.StringInclusion{Name: "Ceremony", Field: string(config.Ceremony), List: []string{string(WebauthnCeremonyRegistration), string(WebauthnCeremonyLogin), string(WebauthnCeremonyConditionalLogin), string(WebauthnCeremonyTransaction)}},
+		&validators.TimeIsPresent{Name: "UpdatedAt", Field: config.UpdatedAt},
+		&validators.TimeIsPresent{Name: "CreatedAt", Field: config.CreatedAt},
+	), nil
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebauthnCeremonyConfigsGetSettings(t *testing.T) {
+	// given
+	platform := protocol.Platform
+	transactionTimeout := 120000
+	transactionUserVerification := protocol.VerificationRequired
+
+	webauthnConfig := WebauthnConfig{
+		Timeout:                     60000,
+		ConditionalMediationTimeout: 600000,
+		UserVerification:            protocol.VerificationPreferred,
+		Attachment:                  &platform,
+	}
+
+	configs := WebauthnCeremonyConfigs{
+		{Ceremony: WebauthnCeremonyTransaction, Timeout: &transactionTimeout, UserVerification: &transactionUserVerification},
+	}
+
+	// when
+	registration := configs.GetSettings(WebauthnCeremonyRegistration, webauthnConfig)
+	login := configs.GetSettings(WebauthnCeremonyLogin, webauthnConfig)
+	conditionalLogin := configs.GetSettings(WebauthnCeremonyConditionalLogin, webauthnConfig)
+	transaction := configs.GetSettings(WebauthnCeremonyTransaction, webauthnConfig)
+
+	// then
+	assert.Equal(t, WebauthnCeremonySettings{Timeout: 60000, UserVerification: protocol.VerificationPreferred, Attachment: &platform}, registration)
+	assert.Equal(t, WebauthnCeremonySettings{Timeout: 60000, UserVerification: protocol.VerificationPreferred}, login)
+	assert.Equal(t, WebauthnCeremonySettings{Timeout: 600000, UserVerification: protocol.VerificationPreferred}, conditionalLogin)
+	assert.Equal(t, WebauthnCeremonySettings{Timeout: 120000, UserVerification: protocol.VerificationRequired}, transaction)
+}
@@ -24,17 +24,53 @@ type WebauthnConfig struct {
 	Attachment             *protocol.AuthenticatorAttachment    `json:"attachment" db:"attachment"`
 	AttestationPreference  protocol.ConveyancePreference        `json:"attestation_preference" db:"attestation_preference"`
 	ResidentKeyRequirement protocol.ResidentKeyRequirement      `json:"resident_key_requirement" db:"resident_key_requirement"`
+	SignCounterPolicy      SignCounterPolicy                    `json:"sign_counter_policy" db:"sign_counter_policy"`
+
+	ConditionalMediationTimeout int `json:"conditional_mediation_timeout" db:"conditional_mediation_timeout"`
+
+	RequireTrustedAttestation   bool `json:"require_trusted_attestation" db:"require_trusted_attestation"`
+	RejectRevokedAuthenticators bool `json:"reject_revoked_authenticators" db:"reject_revoked_authenticators"`
+
+	BackupEligibilityPolicy BackupEligibilityPolicy `json:"backup_eligibility_policy" db:"backup_eligibility_policy"`
+
+	PreventUserEnumeration bool `json:"prevent_user_enumeration" db:"prevent_user_enumeration"`
 }
 
+// SignCounterPolicy defines how a signature counter which did not increase (possible cloned authenticator) is handled.
+type SignCounterPolicy string
+
+var (
+	// SignCounterPolicyLog only writes an audit log entry and continues.
+	SignCounterPolicyLog SignCounterPolicy = "log"
+	// SignCounterPolicyFlag writes an audit log entry and marks the credential as suspicious.
+	SignCounterPolicyFlag SignCounterPolicy = "flag"
+	// SignCounterPolicyReject writes an audit log entry and rejects the assertion.
+	SignCounterPolicyReject SignCounterPolicy = "reject"
+)
+
+// BackupEligibilityPolicy defines how a credential whose backup eligibility flag changed is handled. The flag is set at
+// creation of a credential and must never change.
+type BackupEligibilityPolicy string
+
+var (
+	// BackupEligibilityPolicyFlag writes an audit log entry and marks the credential as suspicious.
+	BackupEligibilityPolicyFlag BackupEligibilityPolicy = "flag"
+	// BackupEligibilityPolicyReject writes an audit log entry and rejects the assertion.
+	BackupEligibilityPolicyReject BackupEligibilityPolicy = "reject"
+)
+
 // Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
 // This method is not required and may be deleted.
 func (webauthn *WebauthnConfig) Validate(_ *pop.Connection) (*validate.Errors, error) {
 	return validate.Validate(
 		&validators.UUIDIsPresent{Name: "ID", Field: webauthn.ID},
 		&validators.IntIsPresent{Name: "Timeout", Field: webauthn.Timeout},
+		&validators.IntIsPresent{Name: "ConditionalMediationTimeout", Field: webauthn.ConditionalMediationTimeout},
 		&validators.StringIsPresent{Name: "UserVerification", Field: string(webauthn.UserVerification)},
 		&validators.StringIsPresent{Name: "AttestationPreference", Field: string(webauthn.AttestationPreference)},
 		&validators.StringIsPresent{Name: "ResidentKeyRequirement", Field: string(webauthn.ResidentKeyRequirement)},
+		&validators.StringInclusion{Name: "SignCounterPolicy", Field: string(webauthn.SignCounterPolicy), List: []string{string(SignCounterPolicyLog), string(SignCounterPolicyFlag), string(SignCounterPolicyReject)}},
+		&validators.StringInclusion{Name: "BackupEligibilityPolicy", Field: string(webauthn.BackupEligibilityPolicy), List: []string{string(BackupEligibilityPolicyFlag), string(BackupEligibilityPolicyReject)}},
 		&validators.TimeIsPresent{Name: "UpdatedAt", Field: webauthn.UpdatedAt},
 		&validators.TimeIsPresent{Name: "CreatedAt", Field: webauthn.CreatedAt},
 	), nil
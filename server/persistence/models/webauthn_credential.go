@@ -25,6 +25,8 @@ type WebauthnCredential struct {
 	BackupEligible  bool       `db:"backup_eligible" json:"-"`
 	BackupState     bool       `db:"backup_state" json:"-"`
 	IsMFA           bool       `db:"is_mfa" json:"-"`
+	FlaggedAt       *time.Time `db:"flagged_at" json:"-"`
+	FlagReason      *string    `db:"flag_reason" json:"-"`
 
 	WebauthnUserID uuid.UUID     `db:"webauthn_user_id"`
 	WebauthnUser   *WebauthnUser `belongs_to:"webauthn_user"`
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"github.com/gobuffalo/pop/v6"
+	"github.com/gobuffalo/validate/v3"
+	"github.com/gobuffalo/validate/v3/validators"
+	"github.com/gofrs/uuid"
+)
+
+// WebauthnCredentialHistoryType is the change of a credential which was recorded
+type WebauthnCredentialHistoryType string
+
+var (
+	// WebauthnCredentialHistoryTypeBackupStateChanged is recorded when an assertion reports a different backup state,
+	// e.g. when a credential got synced for the first time
+	WebauthnCredentialHistoryTypeBackupStateChanged WebauthnCredentialHistoryType = "backup_state_changed"
+)
+
+// WebauthnCredentialHistory is used by pop to map your webauthn_credential_histories database table to your go code.
+// It contains the backup flags of the credential after the change.
+type WebauthnCredentialHistory struct {
+	ID                   uuid.UUID                     `json:"id" db:"id"`
+	WebauthnCredentialID string                        `json:"webauthn_credential_id" db:"webauthn_credential_id"`
+	Type                 WebauthnCredentialHistoryType `json:"type" db:"type"`
+	BackupEligible       bool                          `json:"backup_eligible" db:"backup_eligible"`
+	BackupState          bool                          `json:"backup_state" db:"backup_state"`
+	CreatedAt            time.Time                     `json:"created_at" db:"created_at"`
+	UpdatedAt            time.Time                     `json:"updated_at" db:"updated_at"`
+}
+
+type WebauthnCredentialHistories []WebauthnCredentialHistory
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+func (history *WebauthnCredentialHistory) Validate(_ *pop.Connection) (*validate.Errors, error) {
+	return validate.Validate(
+		&validators.UUIDIsPresent{Name: "ID", Field: history.ID},
+		&validators.StringIsPresent{Name: "WebauthnCredentialID", Field: history.WebauthnCredentialID},
+		&validators.StringInclusion{Name: "Type", Field: string(history.Type), List: []string{string(WebauthnCredentialHistoryTypeBackupStateChanged)}},
+		&validators.TimeIsPresent{Name: "UpdatedAt", Field: history.UpdatedAt},
+		&validators.TimeIsPresent{Name: "CreatedAt", Field: history.CreatedAt},
+	), nil
+}
@@ -15,6 +15,7 @@ type WebauthnOrigin struct {
 	RelyingParty   *RelyingParty `json:"relying_party" belongs_to:"relying_parties"`
 	RelyingPartyID uuid.UUID     `json:"relying_party_id" db:"relying_party_id"`
 	Origin         string        `json:"origin" db:"origin"`
+	IsRelated      bool          `json:"is_related" db:"is_related"`
 	CreatedAt      time.Time     `json:"created_at" db:"created_at"`
 	UpdatedAt      time.Time     `json:"updated_at" db:"updated_at"`
 }
@@ -30,6 +30,9 @@ type WebauthnSessionData struct {
 	AllowedCredentials []WebauthnSessionDataAllowedCredential `has_many:"webauthn_session_data_allowed_credentials"`
 	ExpiresAt          nulls.Time                             `db:"expires_at"`
 	IsDiscoverable     bool                                   `db:"is_discoverable"`
+	IsConditional      bool                                   `db:"is_conditional"`
+	// AttestationPreference is the preference requested from the authenticator on registration, including overrides
+	AttestationPreference nulls.String `db:"attestation_preference"`
 
 	TenantID uuid.UUID `db:"tenant_id"`
 	Tenant   *Tenant   `belongs_to:"tenants"`
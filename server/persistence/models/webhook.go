@@ -0,0 +1,81 @@
+package models
+
+import (
+	"time"
+
+	"github.com/gobuffalo/pop/v6"
+	"github.com/gobuffalo/validate/v3"
+	"github.com/gobuffalo/validate/v3/validators"
+	"github.com/gofrs/uuid"
+)
+
+// Webhook is used by pop to map your webhooks database table to your go code.
+// The secret is used to sign the deliveries, so it is stored in plain text.
+type Webhook struct {
+	ID        uuid.UUID     `json:"id" db:"id"`
+	Url       string        `json:"url" db:"url"`
+	Secret    string        `json:"-" db:"secret"`
+	Enabled   bool          `json:"enabled" db:"enabled"`
+	Events    WebhookEvents `json:"events" has_many:"webhook_events"`
+	Tenant    *Tenant       `json:"-" belongs_to:"tenants"`
+	TenantID  uuid.UUID     `json:"tenant_id" db:"tenant_id"`
+	CreatedAt time.Time     `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at" db:"updated_at"`
+}
+
+type Webhooks []Webhook
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+func (webhook *Webhook) Validate(_ *pop.Connection) (*validate.Errors, error) {
+	return validate.Validate(
+		&validators.UUIDIsPresent{Name: "ID", Field: webhook.ID},
+		&validators.URLIsPresent{Name: "Url", Field: webhook.Url},
+		&validators.StringIsPresent{Name: "Secret", Field: webhook.Secret},
+		&validators.UUIDIsPresent{Name: "TenantID", Field: webhook.TenantID},
+		&validators.TimeIsPresent{Name: "UpdatedAt", Field: webhook.UpdatedAt},
+		&validators.TimeIsPresent{Name: "CreatedAt", Field: webhook.CreatedAt},
+	), nil
+}
+
+// WebhookEvent is used by pop to map your webhook_events database table to your go code.
+// Each event is an audit log type the webhook is subscribed to.
+type WebhookEvent struct {
+	ID        uuid.UUID    `json:"id" db:"id"`
+	Event     AuditLogType `json:"event" db:"event"`
+	WebhookID uuid.UUID    `json:"webhook_id" db:"webhook_id"`
+	Webhook   *Webhook     `json:"-" belongs_to:"webhooks"`
+}
+
+type WebhookEvents []WebhookEvent
+
+func NewWebhookEvents(webhookId uuid.UUID, events []AuditLogType) WebhookEvents {
+	webhookEvents := make(WebhookEvents, 0, len(events))
+	for _, event := range events {
+		id, _ := uuid.NewV4()
+		webhookEvents = append(webhookEvents, WebhookEvent{
+			ID:        id,
+			Event:     event,
+			WebhookID: webhookId,
+		})
+	}
+
+	return webhookEvents
+}
+
+func (events WebhookEvents) GetValues() []AuditLogType {
+	values := make([]AuditLogType, 0, len(events))
+	for _, event := range events {
+		values = append(values, event.Event)
+	}
+
+	return values
+}
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+func (event *WebhookEvent) Validate(_ *pop.Connection) (*validate.Errors, error) {
+	return validate.Validate(
+		&validators.UUIDIsPresent{Name: "ID", Field: event.ID},
+		&validators.StringIsPresent{Name: "Event", Field: string(event.Event)},
+		&validators.UUIDIsPresent{Name: "WebhookID", Field: event.WebhookID},
+	), nil
+}
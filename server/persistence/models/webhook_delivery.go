@@ -0,0 +1,82 @@
+package models
+
+import (
+	"time"
+
+	"github.com/gobuffalo/pop/v6"
+	"github.com/gobuffalo/validate/v3"
+	"github.com/gobuffalo/validate/v3/validators"
+	"github.com/gofrs/uuid"
+)
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryStatusPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliveryStatusSucceeded WebhookDeliveryStatus = "succeeded"
+	WebhookDeliveryStatusFailed    WebhookDeliveryStatus = "failed"
+)
+
+// WebhookDelivery is used by pop to map your webhook_deliveries database table to your go code.
+// Deliveries are created in the same transaction as the audit log and act as outbox for the webhook worker.
+type WebhookDelivery struct {
+	ID            uuid.UUID               `json:"id" db:"id"`
+	Event         AuditLogType            `json:"event" db:"event"`
+	Payload       string                  `json:"payload" db:"payload"`
+	Status        WebhookDeliveryStatus   `json:"status" db:"status"`
+	Attempts      int                     `json:"attempts" db:"attempts"`
+	NextAttemptAt time.Time               `json:"next_attempt_at" db:"next_attempt_at"`
+	AttemptLog    WebhookDeliveryAttempts `json:"attempt_log" has_many:"webhook_delivery_attempts" order_by:"created_at asc"`
+	Webhook       *Webhook                `json:"-" belongs_to:"webhooks"`
+	WebhookID     uuid.UUID               `json:"webhook_id" db:"webhook_id"`
+	Tenant        *Tenant                 `json:"-" belongs_to:"tenants"`
+	TenantID      uuid.UUID               `json:"tenant_id" db:"tenant_id"`
+	CreatedAt     time.Time               `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time               `json:"updated_at" db:"updated_at"`
+}
+
+type WebhookDeliveries []WebhookDelivery
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+func (delivery *WebhookDelivery) Validate(_ *pop.Connection) (*validate.Errors, error) {
+	return validate.Validate(
+		&validators.UUIDIsPresent{Name: "ID", Field: delivery.ID},
+		&validators.StringIsPresent{Name: "Event", Field: string(delivery.Event)},
+		&validators.StringIsPresent{Name: "Payload", Field: delivery.Payload},
+		&validators.StringInclusion{Name: "Status", Field: string(delivery.Status), List: []string{
+			string(WebhookDeliveryStatusPending),
+			string(WebhookDeliveryStatusSucceeded),
+			string(WebhookDeliveryStatusFailed),
+		}},
+		&validators.UUIDIsPresent{Name: "WebhookID", Field: delivery.WebhookID},
+		&validators.UUIDIsPresent{Name: "TenantID", Field: delivery.TenantID},
+		&validators.TimeIsPresent{Name: "NextAttemptAt", Field: delivery.NextAttemptAt},
+		&validators.TimeIsPresent{Name: "UpdatedAt", Field: delivery.UpdatedAt},
+		&validators.TimeIsPresent{Name: "CreatedAt", Field: delivery.CreatedAt},
+	), nil
+}
+
+// WebhookDeliveryAttempt is used by pop to map your webhook_delivery_attempts database table to your go code.
+type WebhookDeliveryAttempt struct {
+	ID         uuid.UUID `json:"id" db:"id"`
+	StatusCode *int      `json:"status_code" db:"status_code"`
+	Error      *string   `json:"error" db:"error"`
+	// Duration of the request in milliseconds
+	Duration          int              `json:"duration" db:"duration"`
+	WebhookDelivery   *WebhookDelivery `json:"-" belongs_to:"webhook_deliveries"`
+	WebhookDeliveryID uuid.UUID        `json:"webhook_delivery_id" db:"webhook_delivery_id"`
+	CreatedAt         time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time        `json:"updated_at" db:"updated_at"`
+}
+
+type WebhookDeliveryAttempts []WebhookDeliveryAttempt
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+func (attempt *WebhookDeliveryAttempt) Validate(_ *pop.Connection) (*validate.Errors, error) {
+	return validate.Validate(
+		&validators.UUIDIsPresent{Name: "ID", Field: attempt.ID},
+		&validators.UUIDIsPresent{Name: "WebhookDeliveryID", Field: attempt.WebhookDeliveryID},
+		&validators.TimeIsPresent{Name: "UpdatedAt", Field: attempt.UpdatedAt},
+		&validators.TimeIsPresent{Name: "CreatedAt", Field: attempt.CreatedAt},
+	), nil
+}
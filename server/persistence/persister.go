@@ -32,6 +32,19 @@ type Persister interface {
 	GetAuditLogConfigPersister(tx *pop.Connection) persisters.AuditLogConfigPersister
 	GetTransactionPersister(tx *pop.Connection) persisters.TransactionPersister
 	GetMFAConfigPersister(tx *pop.Connection) persisters.MFAConfigPersister
+	GetJwtConfigPersister(tx *pop.Connection) persisters.JwtConfigPersister
+	GetRedeemedTokenPersister(tx *pop.Connection) persisters.RedeemedTokenPersister
+	GetOidcClientPersister(tx *pop.Connection) persisters.OidcClientPersister
+	GetOidcAuthorizationCodePersister(tx *pop.Connection) persisters.OidcAuthorizationCodePersister
+	GetWebhookPersister(tx *pop.Connection) persisters.WebhookPersister
+	GetWebhookDeliveryPersister(tx *pop.Connection) persisters.WebhookDeliveryPersister
+	GetAaguidPolicyPersister(tx *pop.Connection) persisters.AaguidPolicyPersister
+	GetBackupPolicyPersister(tx *pop.Connection) persisters.BackupPolicyPersister
+	GetWebauthnCredentialHistoryPersister(tx *pop.Connection) persisters.WebauthnCredentialHistoryPersister
+	GetWebauthnCeremonyConfigPersister(tx *pop.Connection) persisters.WebauthnCeremonyConfigPersister
+	GetRegistrationOverridePersister(tx *pop.Connection) persisters.RegistrationOverridePersister
+	GetConfigVersionPersister(tx *pop.Connection) persisters.ConfigVersionPersister
+	GetLockPersister(tx *pop.Connection) persisters.LockPersister
 }
 
 type Migrator interface {
@@ -220,3 +233,107 @@ func (p *persister) GetMFAConfigPersister(tx *pop.Connection) persisters.MFAConf
 
 	return persisters.NewMFAConfigPersister(tx)
 }
+
+func (p *persister) GetJwtConfigPersister(tx *pop.Connection) persisters.JwtConfigPersister {
+	if tx == nil {
+		return persisters.NewJwtConfigPersister(p.Database)
+	}
+
+	return persisters.NewJwtConfigPersister(tx)
+}
+
+func (p *persister) GetRedeemedTokenPersister(tx *pop.Connection) persisters.RedeemedTokenPersister {
+	if tx == nil {
+		return persisters.NewRedeemedTokenPersister(p.Database)
+	}
+
+	return persisters.NewRedeemedTokenPersister(tx)
+}
+
+func (p *persister) GetOidcClientPersister(tx *pop.Connection) persisters.OidcClientPersister {
+	if tx == nil {
+		return persisters.NewOidcClientPersister(p.Database)
+	}
+
+	return persisters.NewOidcClientPersister(tx)
+}
+
+func (p *persister) GetOidcAuthorizationCodePersister(tx *pop.Connection) persisters.OidcAuthorizationCodePersister {
+	if tx == nil {
+		return persisters.NewOidcAuthorizationCodePersister(p.Database)
+	}
+
+	return persisters.NewOidcAuthorizationCodePersister(tx)
+}
+
+func (p *persister) GetWebhookPersister(tx *pop.Connection) persisters.WebhookPersister {
+	if tx == nil {
+		return persisters.NewWebhookPersister(p.Database)
+	}
+
+	return persisters.NewWebhookPersister(tx)
+}
+
+func (p *persister) GetWebhookDeliveryPersister(tx *pop.Connection) persisters.WebhookDeliveryPersister {
+	if tx == nil {
+		return persisters.NewWebhookDeliveryPersister(p.Database)
+	}
+
+	return persisters.NewWebhookDeliveryPersister(tx)
+}
+
+func (p *persister) GetAaguidPolicyPersister(tx *pop.Connection) persisters.AaguidPolicyPersister {
+	if tx == nil {
+		return persisters.NewAaguidPolicyPersister(p.Database)
+	}
+
+	return persisters.NewAaguidPolicyPersister(tx)
+}
+
+func (p *persister) GetBackupPolicyPersister(tx *pop.Connection) persisters.BackupPolicyPersister {
+	if tx == nil {
+		return persisters.NewBackupPolicyPersister(p.Database)
+	}
+
+	return persisters.NewBackupPolicyPersister(tx)
+}
+
+func (p *persister) GetWebauthnCredentialHistoryPersister(tx *pop.Connection) persisters.WebauthnCredentialHistoryPersister {
+	if tx == nil {
+		return persisters.NewWebauthnCredentialHistoryPersister(p.Database)
+	}
+
+	return persisters.NewWebauthnCredentialHistoryPersister(tx)
+}
+
+func (p *persister) GetWebauthnCeremonyConfigPersister(tx *pop.Connection) persisters.WebauthnCeremonyConfigPersister {
+	if tx == nil {
+		return persisters.NewWebauthnCeremonyConfigPersister(p.Database)
+	}
+
+	return persisters.NewWebauthnCeremonyConfigPersister(tx)
+}
+
+func (p *persister) GetRegistrationOverridePersister(tx *pop.Connection) persisters.RegistrationOverridePersister {
+	if tx == nil {
+		return persisters.NewRegistrationOverridePersister(p.Database)
+	}
+
+	return persisters.NewRegistrationOverridePersister(tx)
+}
+
+func (p *persister) GetConfigVersionPersister(tx *pop.Connection) persisters.ConfigVersionPersister {
+	if tx == nil {
+		return persisters.NewConfigVersionPersister(p.Database)
+	}
+
+	return persisters.NewConfigVersionPersister(tx)
+}
+
+func (p *persister) GetLockPersister(tx *pop.Connection) persisters.LockPersister {
+	if tx == nil {
+		return persisters.NewLockPersister(p.Database)
+	}
+
+	return persisters.NewLockPersister(tx)
+}
@@ -0,0 +1,33 @@
+package persisters
+
+import (
+	"fmt"
+
+	"github.com/gobuffalo/pop/v6"
+	"github.com/teamhanko/passkey-server/persistence/models"
+)
+
+type AaguidPolicyPersister interface {
+	Create(policy *models.AaguidPolicy) error
+}
+
+type aaguidPolicyPersister struct {
+	database *pop.Connection
+}
+
+func NewAaguidPolicyPersister(database *pop.Connection) AaguidPolicyPersister {
+	return &aaguidPolicyPersister{database: database}
+}
+
+func (ap *aaguidPolicyPersister) Create(policy *models.AaguidPolicy) error {
+	validationErr, err := ap.database.ValidateAndCreate(policy)
+	if err != nil {
+		return fmt.Errorf("failed to store aaguid policy: %w", err)
+	}
+
+	if validationErr != nil && validationErr.HasAny() {
+		return fmt.Errorf("aaguid policy validation failed: %w", validationErr)
+	}
+
+	return nil
+}
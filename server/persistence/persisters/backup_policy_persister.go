@@ -0,0 +1,33 @@
+package persisters
+
+import (
+	"fmt"
+
+	"github.com/gobuffalo/pop/v6"
+	"github.com/teamhanko/passkey-server/persistence/models"
+)
+
+type BackupPolicyPersister interface {
+	Create(policy *models.BackupPolicy) error
+}
+
+type backupPolicyPersister struct {
+	database *pop.Connection
+}
+
+func NewBackupPolicyPersister(database *pop.Connection) BackupPolicyPersister {
+	return &backupPolicyPersister{database: database}
+}
+
+func (bp *backupPolicyPersister) Create(policy *models.BackupPolicy) error {
+	validationErr, err := bp.database.ValidateAndCreate(policy)
+	if err != nil {
+		return fmt.Errorf("failed to store backup policy: %w", err)
+	}
+
+	if validationErr != nil && validationErr.HasAny() {
+		return fmt.Errorf("backup policy validation failed: %w", validationErr)
+	}
+
+	return nil
+}
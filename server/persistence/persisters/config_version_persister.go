@@ -0,0 +1,83 @@
+package persisters
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/gobuffalo/pop/v6"
+	"github.com/gofrs/uuid"
+	"github.com/teamhanko/passkey-server/persistence/models"
+)
+
+// ConfigVersionPersister stores the versions of tenant configs. Versions are immutable, so they can't be updated or
+// removed on their own.
+type ConfigVersionPersister interface {
+	Create(version *models.ConfigVersion) error
+	Get(tenantId uuid.UUID, version int) (*models.ConfigVersion, error)
+	GetLatest(tenantId uuid.UUID) (*models.ConfigVersion, error)
+	List(tenantId uuid.UUID) (models.ConfigVersions, error)
+}
+
+type configVersionPersister struct {
+	database *pop.Connection
+}
+
+func NewConfigVersionPersister(database *pop.Connection) ConfigVersionPersister {
+	return &configVersionPersister{database: database}
+}
+
+func (cvp *configVersionPersister) Create(version *models.ConfigVersion) error {
+	validationErr, err := cvp.database.ValidateAndCreate(version)
+	if err != nil {
+		return fmt.Errorf("failed to store config version: %w", err)
+	}
+
+	if validationErr != nil && validationErr.HasAny() {
+		return fmt.Errorf("config version validation failed: %w", validationErr)
+	}
+
+	return nil
+}
+
+func (cvp *configVersionPersister) Get(tenantId uuid.UUID, version int) (*models.ConfigVersion, error) {
+	configVersion := models.ConfigVersion{}
+	err := cvp.database.Where("tenant_id = ? AND version = ?", tenantId, version).First(&configVersion)
+	if err != nil && errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get config version: %w", err)
+	}
+
+	return &configVersion, nil
+}
+
+func (cvp *configVersionPersister) GetLatest(tenantId uuid.UUID) (*models.ConfigVersion, error) {
+	configVersion := models.ConfigVersion{}
+	err := cvp.database.Where("tenant_id = ?", tenantId).Order("version desc").First(&configVersion)
+	if err != nil && errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest config version: %w", err)
+	}
+
+	return &configVersion, nil
+}
+
+func (cvp *configVersionPersister) List(tenantId uuid.UUID) (models.ConfigVersions, error) {
+	versions := models.ConfigVersions{}
+	err := cvp.database.Where("tenant_id = ?", tenantId).Order("version desc").All(&versions)
+	if err != nil && errors.Is(err, sql.ErrNoRows) {
+		return versions, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list config versions: %w", err)
+	}
+
+	return versions, nil
+}
@@ -15,7 +15,8 @@ type JwkPersister interface {
 	GetAll() ([]models.Jwk, error)
 	GetAllForTenant(tenantId uuid.UUID) ([]models.Jwk, error)
 	GetLast(tenantId uuid.UUID) (*models.Jwk, error)
-	Create(models.Jwk) error
+	Create(*models.Jwk) error
+	Update(*models.Jwk) error
 }
 
 const (
@@ -44,7 +45,7 @@ func (p *jwkPersister) GetAll() ([]models.Jwk, error) {
 
 func (p *jwkPersister) GetAllForTenant(tenantId uuid.UUID) ([]models.Jwk, error) {
 	var jwks []models.Jwk
-	err := p.db.Where("tenant_id = ?", &tenantId).All(&jwks)
+	err := p.db.Where("tenant_id = ?", &tenantId).Order("id asc").All(&jwks)
 	if err != nil && errors.Is(err, sql.ErrNoRows) {
 		return nil, nil
 	}
@@ -66,8 +67,8 @@ func (p *jwkPersister) GetLast(tenantId uuid.UUID) (*models.Jwk, error) {
 	return &jwk, nil
 }
 
-func (p *jwkPersister) Create(jwk models.Jwk) error {
-	vErr, err := p.db.ValidateAndCreate(&jwk)
+func (p *jwkPersister) Create(jwk *models.Jwk) error {
+	vErr, err := p.db.ValidateAndCreate(jwk)
 	if err != nil {
 		return fmt.Errorf("failed to store jwk: %w", err)
 	}
@@ -78,3 +79,16 @@ func (p *jwkPersister) Create(jwk models.Jwk) error {
 
 	return nil
 }
+
+func (p *jwkPersister) Update(jwk *models.Jwk) error {
+	vErr, err := p.db.ValidateAndUpdate(jwk)
+	if err != nil {
+		return fmt.Errorf("failed to update jwk: %w", err)
+	}
+
+	if vErr != nil && vErr.HasAny() {
+		return fmt.Errorf("jwk object validation failed: %w", vErr)
+	}
+
+	return nil
+}
@@ -0,0 +1,33 @@
+package persisters
+
+import (
+	"fmt"
+
+	"github.com/gobuffalo/pop/v6"
+	"github.com/teamhanko/passkey-server/persistence/models"
+)
+
+type JwtConfigPersister interface {
+	Create(jwtConfig *models.JwtConfig) error
+}
+
+type jwtConfigPersister struct {
+	database *pop.Connection
+}
+
+func NewJwtConfigPersister(database *pop.Connection) JwtConfigPersister {
+	return &jwtConfigPersister{database: database}
+}
+
+func (jp *jwtConfigPersister) Create(jwtConfig *models.JwtConfig) error {
+	validationErr, err := jp.database.Eager().ValidateAndCreate(jwtConfig)
+	if err != nil {
+		return fmt.Errorf("failed to store jwt config: %w", err)
+	}
+
+	if validationErr != nil && validationErr.HasAny() {
+		return fmt.Errorf("jwt config validation failed: %w", validationErr)
+	}
+
+	return nil
+}
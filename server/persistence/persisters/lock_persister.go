@@ -0,0 +1,42 @@
+package persisters
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/gobuffalo/pop/v6"
+	"github.com/teamhanko/passkey-server/persistence/models"
+)
+
+// TenantProvisioningLock serializes the provisioning of the tenants of the config across replicas
+const TenantProvisioningLock = "tenant_provisioning"
+
+// LockPersister takes locks which are shared by all replicas of the server. Unlike advisory locks, they work with
+// every supported database, but each lock needs a row in the locks table.
+type LockPersister interface {
+	// Lock waits until the lock is free and holds it until the end of the current transaction
+	Lock(name string) error
+}
+
+type lockPersister struct {
+	database *pop.Connection
+}
+
+func NewLockPersister(database *pop.Connection) LockPersister {
+	return &lockPersister{database: database}
+}
+
+func (lp *lockPersister) Lock(name string) error {
+	lock := models.Lock{}
+	err := lp.database.RawQuery("SELECT name FROM locks WHERE name = ? FOR UPDATE", name).First(&lock)
+	if err != nil && errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("failed to take lock '%s': lock does not exist", name)
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to take lock '%s': %w", name, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package persisters
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/gobuffalo/pop/v6"
+	"github.com/gofrs/uuid"
+	"github.com/teamhanko/passkey-server/persistence/models"
+)
+
+type OidcAuthorizationCodePersister interface {
+	Create(code *models.OidcAuthorizationCode) error
+	GetByCode(tenantId uuid.UUID, hashedCode string) (*models.OidcAuthorizationCode, error)
+	// Delete removes the code and returns false when it was already removed, e.g. by a concurrent exchange
+	Delete(code *models.OidcAuthorizationCode) (bool, error)
+}
+
+type oidcAuthorizationCodePersister struct {
+	database *pop.Connection
+}
+
+func NewOidcAuthorizationCodePersister(database *pop.Connection) OidcAuthorizationCodePersister {
+	return &oidcAuthorizationCodePersister{database: database}
+}
+
+func (op *oidcAuthorizationCodePersister) Create(code *models.OidcAuthorizationCode) error {
+	validationErr, err := op.database.ValidateAndCreate(code)
+	if err != nil {
+		return fmt.Errorf("failed to store oidc authorization code: %w", err)
+	}
+
+	if validationErr != nil && validationErr.HasAny() {
+		return fmt.Errorf("oidc authorization code validation failed: %w", validationErr)
+	}
+
+	return nil
+}
+
+func (op *oidcAuthorizationCodePersister) GetByCode(tenantId uuid.UUID, hashedCode string) (*models.OidcAuthorizationCode, error) {
+	code := models.OidcAuthorizationCode{}
+	err := op.database.Where("tenant_id = ? AND code = ?", tenantId, hashedCode).First(&code)
+	if err != nil && errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get oidc authorization code: %w", err)
+	}
+
+	return &code, nil
+}
+
+func (op *oidcAuthorizationCodePersister) Delete(code *models.OidcAuthorizationCode) (bool, error) {
+	count, err := op.database.RawQuery("DELETE FROM oidc_authorization_codes WHERE id = ?", code.ID).ExecWithCount()
+	if err != nil {
+		return false, fmt.Errorf("failed to delete oidc authorization code: %w", err)
+	}
+
+	return count == 1, nil
+}
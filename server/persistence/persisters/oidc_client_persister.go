@@ -0,0 +1,33 @@
+package persisters
+
+import (
+	"fmt"
+
+	"github.com/gobuffalo/pop/v6"
+	"github.com/teamhanko/passkey-server/persistence/models"
+)
+
+type OidcClientPersister interface {
+	Create(client *models.OidcClient) error
+}
+
+type oidcClientPersister struct {
+	database *pop.Connection
+}
+
+func NewOidcClientPersister(database *pop.Connection) OidcClientPersister {
+	return &oidcClientPersister{database: database}
+}
+
+func (op *oidcClientPersister) Create(client *models.OidcClient) error {
+	validationErr, err := op.database.Eager().ValidateAndCreate(client)
+	if err != nil {
+		return fmt.Errorf("failed to store oidc client: %w", err)
+	}
+
+	if validationErr != nil && validationErr.HasAny() {
+		return fmt.Errorf("oidc client validation failed: %w", validationErr)
+	}
+
+	return nil
+}
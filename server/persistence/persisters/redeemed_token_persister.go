@@ -0,0 +1,69 @@
+package persisters
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gobuffalo/pop/v6"
+	"github.com/gobuffalo/validate/v3"
+	"github.com/gofrs/uuid"
+	"github.com/teamhanko/passkey-server/persistence/models"
+)
+
+type RedeemedTokenPersister interface {
+	Create(token *models.RedeemedToken) error
+	Get(tenantId uuid.UUID, tokenId string) (*models.RedeemedToken, error)
+	// DeleteExpired removes redeemed tokens which can't be verified anymore and returns the number of removed tokens
+	DeleteExpired(now time.Time) (int, error)
+}
+
+type redeemedTokenPersister struct {
+	database *pop.Connection
+}
+
+func NewRedeemedTokenPersister(database *pop.Connection) RedeemedTokenPersister {
+	return &redeemedTokenPersister{database: database}
+}
+
+func (rp *redeemedTokenPersister) Create(token *models.RedeemedToken) error {
+	var validationErr *validate.Errors
+	err := withSavepoint(rp.database, "redeem_token", func() error {
+		var err error
+		validationErr, err = rp.database.ValidateAndCreate(token)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to store redeemed token: %w", err)
+	}
+
+	if validationErr != nil && validationErr.HasAny() {
+		return fmt.Errorf("redeemed token validation failed: %w", validationErr)
+	}
+
+	return nil
+}
+
+func (rp *redeemedTokenPersister) Get(tenantId uuid.UUID, tokenId string) (*models.RedeemedToken, error) {
+	token := models.RedeemedToken{}
+	err := rp.database.Where("tenant_id = ? AND token_id = ?", tenantId, tokenId).First(&token)
+	if err != nil && errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get redeemed token: %w", err)
+	}
+
+	return &token, nil
+}
+
+func (rp *redeemedTokenPersister) DeleteExpired(now time.Time) (int, error) {
+	count, err := rp.database.RawQuery("DELETE FROM redeemed_tokens WHERE expires_at < ?", now).ExecWithCount()
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired redeemed tokens: %w", err)
+	}
+
+	return count, nil
+}
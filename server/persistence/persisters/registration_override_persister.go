@@ -0,0 +1,33 @@
+package persisters
+
+import (
+	"fmt"
+
+	"github.com/gobuffalo/pop/v6"
+	"github.com/teamhanko/passkey-server/persistence/models"
+)
+
+type RegistrationOverridePersister interface {
+	Create(override *models.RegistrationOverride) error
+}
+
+type registrationOverridePersister struct {
+	database *pop.Connection
+}
+
+func NewRegistrationOverridePersister(database *pop.Connection) RegistrationOverridePersister {
+	return &registrationOverridePersister{database: database}
+}
+
+func (ro *registrationOverridePersister) Create(override *models.RegistrationOverride) error {
+	validationErr, err := ro.database.ValidateAndCreate(override)
+	if err != nil {
+		return fmt.Errorf("failed to store registration override: %w", err)
+	}
+
+	if validationErr != nil && validationErr.HasAny() {
+		return fmt.Errorf("registration override validation failed: %w", validationErr)
+	}
+
+	return nil
+}
@@ -11,6 +11,9 @@ type SecretsPersister interface {
 	Create(secret *models.Secret) error
 	Delete(secret *models.Secret) error
 	Update(secret *models.Secret) error
+	UpdateLastUsedAt(secret *models.Secret) error
+	// ReplaceScopes removes the current scopes of the secret and stores the new ones
+	ReplaceScopes(secret *models.Secret, scopes models.SecretScopes) error
 }
 
 type secretsPersister struct {
@@ -31,6 +34,17 @@ func (sp secretsPersister) Create(secret *models.Secret) error {
 		return fmt.Errorf("secret validation failed: %w", validationErr)
 	}
 
+	// Eager creation seems to be broken, so we need to store the scopes separately.
+	// See: https://github.com/gobuffalo/pop/issues/608
+	validationErr, err = sp.database.ValidateAndCreate(secret.Scopes)
+	if err != nil {
+		return fmt.Errorf("failed to store secret scopes: %w", err)
+	}
+
+	if validationErr != nil && validationErr.HasAny() {
+		return fmt.Errorf("secret scope validation failed: %w", validationErr)
+	}
+
 	return nil
 }
 
@@ -55,3 +69,34 @@ func (sp secretsPersister) Update(secret *models.Secret) error {
 
 	return nil
 }
+
+func (sp secretsPersister) UpdateLastUsedAt(secret *models.Secret) error {
+	err := sp.database.UpdateColumns(secret, "last_used_at", "updated_at")
+	if err != nil {
+		return fmt.Errorf("failed to update last usage of secret: %w", err)
+	}
+
+	return nil
+}
+
+func (sp secretsPersister) ReplaceScopes(secret *models.Secret, scopes models.SecretScopes) error {
+	err := sp.database.RawQuery("DELETE FROM secret_scopes WHERE secret_id = ?", secret.ID).Exec()
+	if err != nil {
+		return fmt.Errorf("failed to delete secret scopes: %w", err)
+	}
+
+	if len(scopes) > 0 {
+		validationErr, err := sp.database.ValidateAndCreate(scopes)
+		if err != nil {
+			return fmt.Errorf("failed to store secret scopes: %w", err)
+		}
+
+		if validationErr != nil && validationErr.HasAny() {
+			return fmt.Errorf("secret scope validation failed: %w", validationErr)
+		}
+	}
+
+	secret.Scopes = scopes
+
+	return nil
+}
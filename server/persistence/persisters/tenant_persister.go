@@ -16,6 +16,10 @@ type TenantPersister interface {
 	List() (models.Tenants, error)
 	Update(tenant *models.Tenant) error
 	Delete(tenant *models.Tenant) error
+	// Lock locks the row of the tenant until the end of the current transaction
+	Lock(tenantId uuid.UUID) error
+	// SetUserEnumerationKey stores the key if the tenant has none yet and returns the stored key
+	SetUserEnumerationKey(tenantId uuid.UUID, key string) (string, error)
 }
 
 type tenantPersister struct {
@@ -42,9 +46,15 @@ func (t tenantPersister) Create(tenant *models.Tenant) error {
 func (t tenantPersister) Get(tenantId uuid.UUID) (*models.Tenant, error) {
 	tenant := models.Tenant{}
 	err := t.database.Eager(
-		"Config.Secrets",
+		"Config.Secrets.Scopes",
 		"Config.WebauthnConfig.RelyingParty.Origins",
 		"Config.MfaConfig",
+		"Config.JwtConfig.Audiences",
+		"Config.OidcClients.RedirectUris",
+		"Config.AaguidPolicies",
+		"Config.BackupPolicies",
+		"Config.CeremonyConfigs",
+		"Config.RegistrationOverrides",
 		"Config.Cors.Origins",
 		"Config.AuditLogConfig",
 	).Find(&tenant, tenantId)
@@ -88,6 +98,35 @@ func (t tenantPersister) Update(tenant *models.Tenant) error {
 	return nil
 }
 
+func (t tenantPersister) Lock(tenantId uuid.UUID) error {
+	err := t.database.RawQuery("SELECT id FROM tenants WHERE id = ? FOR UPDATE", tenantId).Exec()
+	if err != nil {
+		return fmt.Errorf("failed to lock tenant: %w", err)
+	}
+
+	return nil
+}
+
+func (t tenantPersister) SetUserEnumerationKey(tenantId uuid.UUID, key string) (string, error) {
+	// concurrent requests keep the key of the first one
+	err := t.database.RawQuery("UPDATE tenants SET user_enumeration_key = ? WHERE id = ? AND user_enumeration_key IS NULL", key, tenantId).Exec()
+	if err != nil {
+		return "", fmt.Errorf("failed to store user enumeration key: %w", err)
+	}
+
+	tenant := models.Tenant{}
+	err = t.database.Select("id", "user_enumeration_key").Find(&tenant, tenantId)
+	if err != nil {
+		return "", fmt.Errorf("failed to get user enumeration key: %w", err)
+	}
+
+	if tenant.UserEnumerationKey == nil {
+		return "", errors.New("failed to get user enumeration key: tenant has no key")
+	}
+
+	return *tenant.UserEnumerationKey, nil
+}
+
 func (t tenantPersister) Delete(tenant *models.Tenant) error {
 	err := t.database.Destroy(tenant)
 	if err != nil {
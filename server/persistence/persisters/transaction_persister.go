@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/gobuffalo/pop/v6"
 	"github.com/gofrs/uuid"
@@ -16,6 +17,8 @@ type TransactionPersister interface {
 	ListByUserId(userId uuid.UUID, tenantId uuid.UUID) (*models.Transactions, error)
 	GetByUserId(userId uuid.UUID, tenantId uuid.UUID) (*models.Transaction, error)
 	GetByChallenge(challenge string, tenantId uuid.UUID) (*models.Transaction, error)
+	// DeleteAbandoned removes all transactions which were not finalized before their session data expired
+	DeleteAbandoned(now time.Time) (int, error)
 }
 
 type transactionPersister struct {
@@ -91,3 +94,16 @@ func (p *transactionPersister) GetByChallenge(challenge string, tenantId uuid.UU
 
 	return &transaction, nil
 }
+
+func (p *transactionPersister) DeleteAbandoned(now time.Time) (int, error) {
+	// finalizing a transaction removes its session data, so only abandoned transactions still have one
+	count, err := p.database.RawQuery(
+		"DELETE FROM transactions WHERE EXISTS (SELECT 1 FROM webauthn_session_data WHERE webauthn_session_data.challenge = transactions.challenge AND webauthn_session_data.tenant_id = transactions.tenant_id AND webauthn_session_data.operation = ? AND webauthn_session_data.expires_at < ?)",
+		models.WebauthnOperationTransaction, now,
+	).ExecWithCount()
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete abandoned transactions: %w", err)
+	}
+
+	return count, nil
+}
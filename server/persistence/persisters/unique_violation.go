@@ -0,0 +1,56 @@
+package persisters
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/gobuffalo/pop/v6"
+	"github.com/jackc/pgconn"
+)
+
+const (
+	postgresUniqueViolation = "23505"
+	mysqlDuplicateEntry     = 1062
+)
+
+// IsUniqueViolation returns true if the error was caused by a row which conflicts with an existing one on a unique
+// index or primary key, e.g. when a concurrent request stored the same row first
+func IsUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == postgresUniqueViolation
+	}
+
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == mysqlDuplicateEntry
+	}
+
+	return false
+}
+
+// withSavepoint runs fn in a savepoint when the connection is a transaction. A failing statement aborts the whole
+// transaction in postgres, the savepoint keeps it usable, e.g. to write the audit log of a conflict.
+func withSavepoint(database *pop.Connection, name string, fn func() error) error {
+	if database.TX == nil {
+		return fn()
+	}
+
+	err := database.RawQuery(fmt.Sprintf("SAVEPOINT %s", name)).Exec()
+	if err != nil {
+		return fmt.Errorf("failed to create savepoint: %w", err)
+	}
+
+	err = fn()
+	if err != nil {
+		rollbackErr := database.RawQuery(fmt.Sprintf("ROLLBACK TO SAVEPOINT %s", name)).Exec()
+		if rollbackErr != nil {
+			return fmt.Errorf("failed to roll back to savepoint: %w", rollbackErr)
+		}
+
+		return err
+	}
+
+	return database.RawQuery(fmt.Sprintf("RELEASE SAVEPOINT %s", name)).Exec()
+}
@@ -0,0 +1,30 @@
+package persisters
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsUniqueViolation(t *testing.T) {
+	// given
+	errs := map[error]bool{
+		fmt.Errorf("failed to store: %w", &pgconn.PgError{Code: "23505"}):  true,
+		fmt.Errorf("failed to store: %w", &pgconn.PgError{Code: "23503"}):  false,
+		fmt.Errorf("failed to store: %w", &mysql.MySQLError{Number: 1062}): true,
+		fmt.Errorf("failed to store: %w", &mysql.MySQLError{Number: 1452}): false,
+		errors.New("failed to store"):                                      false,
+	}
+
+	for err, expected := range errs {
+		// when
+		isUniqueViolation := IsUniqueViolation(err)
+
+		// then
+		assert.Equal(t, expected, isUniqueViolation, err.Error())
+	}
+}
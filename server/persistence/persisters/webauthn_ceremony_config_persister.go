@@ -0,0 +1,33 @@
+package persisters
+
+import (
+	"fmt"
+
+	"github.com/gobuffalo/pop/v6"
+	"github.com/teamhanko/passkey-server/persistence/models"
+)
+
+type WebauthnCeremonyConfigPersister interface {
+	Create(ceremonyConfig *models.WebauthnCeremonyConfig) error
+}
+
+type webauthnCeremonyConfigPersister struct {
+	database *pop.Connection
+}
+
+func NewWebauthnCeremonyConfigPersister(database *pop.Connection) WebauthnCeremonyConfigPersister {
+	return &webauthnCeremonyConfigPersister{database: database}
+}
+
+func (wc *webauthnCeremonyConfigPersister) Create(ceremonyConfig *models.WebauthnCeremonyConfig) error {
+	validationErr, err := wc.database.ValidateAndCreate(ceremonyConfig)
+	if err != nil {
+		return fmt.Errorf("failed to store webauthn ceremony config: %w", err)
+	}
+
+	if validationErr != nil && validationErr.HasAny() {
+		return fmt.Errorf("webauthn ceremony config validation failed: %w", validationErr)
+	}
+
+	return nil
+}
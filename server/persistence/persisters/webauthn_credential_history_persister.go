@@ -0,0 +1,33 @@
+package persisters
+
+import (
+	"fmt"
+
+	"github.com/gobuffalo/pop/v6"
+	"github.com/teamhanko/passkey-server/persistence/models"
+)
+
+type WebauthnCredentialHistoryPersister interface {
+	Create(history *models.WebauthnCredentialHistory) error
+}
+
+type webauthnCredentialHistoryPersister struct {
+	database *pop.Connection
+}
+
+func NewWebauthnCredentialHistoryPersister(database *pop.Connection) WebauthnCredentialHistoryPersister {
+	return &webauthnCredentialHistoryPersister{database: database}
+}
+
+func (ch *webauthnCredentialHistoryPersister) Create(history *models.WebauthnCredentialHistory) error {
+	validationErr, err := ch.database.ValidateAndCreate(history)
+	if err != nil {
+		return fmt.Errorf("failed to store credential history: %w", err)
+	}
+
+	if validationErr != nil && validationErr.HasAny() {
+		return fmt.Errorf("credential history validation failed: %w", validationErr)
+	}
+
+	return nil
+}
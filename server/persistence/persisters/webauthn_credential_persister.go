@@ -4,11 +4,13 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/gofrs/uuid"
 	"github.com/teamhanko/passkey-server/api/dto/request"
 
 	"github.com/gobuffalo/pop/v6"
+	"github.com/gobuffalo/validate/v3"
 	"github.com/teamhanko/passkey-server/persistence/models"
 )
 
@@ -75,7 +77,12 @@ func (w *webauthnCredentialPersister) Get(id string, tenantId uuid.UUID) (*model
 }
 
 func (w *webauthnCredentialPersister) Create(credential *models.WebauthnCredential) error {
-	vErr, err := w.database.ValidateAndCreate(credential)
+	var vErr *validate.Errors
+	err := withSavepoint(w.database, "create_credential", func() error {
+		var err error
+		vErr, err = w.database.ValidateAndCreate(credential)
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("failed to store credential: %w", err)
 	}
@@ -117,6 +124,12 @@ func (w *webauthnCredentialPersister) Delete(credential *models.WebauthnCredenti
 		return fmt.Errorf("failed to delete credential: %w", err)
 	}
 
+	// the deletion counts as activity of the user, so the janitor keeps users which just removed their last credential
+	err = w.database.RawQuery("UPDATE webauthn_users SET updated_at = ? WHERE id = ?", time.Now().UTC(), credential.WebauthnUserID).Exec()
+	if err != nil {
+		return fmt.Errorf("failed to update user of deleted credential: %w", err)
+	}
+
 	return nil
 }
 
@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/gobuffalo/pop/v6"
 	"github.com/gofrs/uuid"
@@ -14,6 +15,8 @@ type WebauthnSessionDataPersister interface {
 	GetByChallenge(challenge string, tenantId uuid.UUID) (*models.WebauthnSessionData, error)
 	Create(sessionData models.WebauthnSessionData) error
 	Delete(sessionData models.WebauthnSessionData) error
+	// DeleteExpired removes the session data of all tenants which expired before the given time
+	DeleteExpired(now time.Time) (int, error)
 }
 
 type sessionDataPersister struct {
@@ -62,3 +65,15 @@ func (ws *sessionDataPersister) Delete(sessionData models.WebauthnSessionData) e
 
 	return nil
 }
+
+func (ws *sessionDataPersister) DeleteExpired(now time.Time) (int, error) {
+	count, err := ws.database.RawQuery(
+		"DELETE FROM webauthn_session_data WHERE expires_at IS NOT NULL AND expires_at < ?",
+		now,
+	).ExecWithCount()
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired sessionData: %w", err)
+	}
+
+	return count, nil
+}
@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/gobuffalo/pop/v6"
 	"github.com/gofrs/uuid"
@@ -16,10 +17,19 @@ type WebauthnUserPersister interface {
 	Count(tenantId uuid.UUID) (int, error)
 	GetById(id uuid.UUID) (*models.WebauthnUser, error)
 	GetByUserId(userId string, tenantId uuid.UUID) (*models.WebauthnUser, error)
+	// GetByName returns the user with the given name. Names are not unique, so an error wrapping ErrAmbiguousName is
+	// returned when more than one user of the tenant has the name.
+	GetByName(name string, tenantId uuid.UUID) (*models.WebauthnUser, error)
 	Update(webauthnUser *models.WebauthnUser) error
 	Delete(user *models.WebauthnUser) error
+	// DeleteWithoutCredentials removes the users of all tenants which were not updated since the given time and have
+	// neither credentials nor transactions
+	DeleteWithoutCredentials(inactiveSince time.Time) (int, error)
 }
 
+// ErrAmbiguousName is returned when a name does not identify a single user
+var ErrAmbiguousName = errors.New("name is used by more than one user")
+
 type webauthnUserPersister struct {
 	database *pop.Connection
 }
@@ -88,6 +98,26 @@ func (p *webauthnUserPersister) GetByUserId(userId string, tenantId uuid.UUID) (
 	return &webauthnUser, nil
 }
 
+func (p *webauthnUserPersister) GetByName(name string, tenantId uuid.UUID) (*models.WebauthnUser, error) {
+	webauthnUsers := models.WebauthnUsers{}
+	err := p.database.Eager().Where("name = ? AND tenant_id = ?", name, tenantId).Limit(2).All(&webauthnUsers)
+	if err != nil && errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webauthn user by name: %w", err)
+	}
+
+	switch len(webauthnUsers) {
+	case 0:
+		return nil, nil
+	case 1:
+		return &webauthnUsers[0], nil
+	default:
+		return nil, fmt.Errorf("failed to get webauthn user by name: %w", ErrAmbiguousName)
+	}
+}
+
 func (p *webauthnUserPersister) Update(webauthnUser *models.WebauthnUser) error {
 	vErr, err := p.database.ValidateAndUpdate(webauthnUser)
 	if err != nil {
@@ -118,3 +148,16 @@ func (p *webauthnUserPersister) Count(tenantId uuid.UUID) (int, error) {
 
 	return count, nil
 }
+
+func (p *webauthnUserPersister) DeleteWithoutCredentials(inactiveSince time.Time) (int, error) {
+	// users with transactions are kept, as removing them would cascade to the transaction history
+	count, err := p.database.RawQuery(
+		"DELETE FROM webauthn_users WHERE updated_at < ? AND NOT EXISTS (SELECT 1 FROM webauthn_credentials WHERE webauthn_credentials.webauthn_user_id = webauthn_users.id) AND NOT EXISTS (SELECT 1 FROM transactions WHERE transactions.webauthn_user_id = webauthn_users.id)",
+		inactiveSince,
+	).ExecWithCount()
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete webauthn users without credentials: %w", err)
+	}
+
+	return count, nil
+}
@@ -0,0 +1,150 @@
+package persisters
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gobuffalo/pop/v6"
+	"github.com/gofrs/uuid"
+	"github.com/teamhanko/passkey-server/persistence/models"
+)
+
+type WebhookDeliveryPersister interface {
+	Create(delivery *models.WebhookDelivery) error
+	Get(webhookId uuid.UUID, deliveryId uuid.UUID) (*models.WebhookDelivery, error)
+	List(webhookId uuid.UUID, page int, perPage int) (models.WebhookDeliveries, error)
+	Count(webhookId uuid.UUID) (int, error)
+	// ListDue returns pending deliveries whose next attempt is due, including their webhook
+	ListDue(now time.Time, limit int) (models.WebhookDeliveries, error)
+	// Claim moves the next attempt of a due delivery to the given time. It returns false when the delivery has already
+	// been claimed by another worker.
+	Claim(delivery *models.WebhookDelivery, until time.Time) (bool, error)
+	Update(delivery *models.WebhookDelivery) error
+	CreateAttempt(attempt *models.WebhookDeliveryAttempt) error
+}
+
+type webhookDeliveryPersister struct {
+	database *pop.Connection
+}
+
+func NewWebhookDeliveryPersister(database *pop.Connection) WebhookDeliveryPersister {
+	return &webhookDeliveryPersister{database: database}
+}
+
+func (wp *webhookDeliveryPersister) Create(delivery *models.WebhookDelivery) error {
+	validationErr, err := wp.database.ValidateAndCreate(delivery)
+	if err != nil {
+		return fmt.Errorf("failed to store webhook delivery: %w", err)
+	}
+
+	if validationErr != nil && validationErr.HasAny() {
+		return fmt.Errorf("webhook delivery validation failed: %w", validationErr)
+	}
+
+	return nil
+}
+
+func (wp *webhookDeliveryPersister) Get(webhookId uuid.UUID, deliveryId uuid.UUID) (*models.WebhookDelivery, error) {
+	delivery := models.WebhookDelivery{}
+	err := wp.database.Eager("AttemptLog").Where("webhook_id = ?", webhookId).Find(&delivery, deliveryId)
+	if err != nil && errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook delivery: %w", err)
+	}
+
+	return &delivery, nil
+}
+
+func (wp *webhookDeliveryPersister) List(webhookId uuid.UUID, page int, perPage int) (models.WebhookDeliveries, error) {
+	deliveries := models.WebhookDeliveries{}
+	err := wp.database.Eager("AttemptLog").
+		Where("webhook_id = ?", webhookId).
+		Paginate(page, perPage).
+		Order("created_at desc").
+		All(&deliveries)
+	if err != nil && errors.Is(err, sql.ErrNoRows) {
+		return deliveries, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+func (wp *webhookDeliveryPersister) Count(webhookId uuid.UUID) (int, error) {
+	count, err := wp.database.Where("webhook_id = ?", webhookId).Count(&models.WebhookDelivery{})
+	if err != nil {
+		return 0, fmt.Errorf("failed to count webhook deliveries: %w", err)
+	}
+
+	return count, nil
+}
+
+func (wp *webhookDeliveryPersister) ListDue(now time.Time, limit int) (models.WebhookDeliveries, error) {
+	deliveries := models.WebhookDeliveries{}
+	err := wp.database.Eager("Webhook").
+		Where("status = ? AND next_attempt_at <= ?", models.WebhookDeliveryStatusPending, now).
+		Order("next_attempt_at asc").
+		Limit(limit).
+		All(&deliveries)
+	if err != nil && errors.Is(err, sql.ErrNoRows) {
+		return deliveries, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list due webhook deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+func (wp *webhookDeliveryPersister) Claim(delivery *models.WebhookDelivery, until time.Time) (bool, error) {
+	count, err := wp.database.RawQuery(
+		"UPDATE webhook_deliveries SET next_attempt_at = ?, updated_at = ? WHERE id = ? AND status = ? AND next_attempt_at = ?",
+		until, time.Now().UTC(), delivery.ID, models.WebhookDeliveryStatusPending, delivery.NextAttemptAt,
+	).ExecWithCount()
+	if err != nil {
+		return false, fmt.Errorf("failed to claim webhook delivery: %w", err)
+	}
+
+	if count == 0 {
+		return false, nil
+	}
+
+	delivery.NextAttemptAt = until
+
+	return true, nil
+}
+
+func (wp *webhookDeliveryPersister) Update(delivery *models.WebhookDelivery) error {
+	validationErr, err := wp.database.ValidateAndUpdate(delivery)
+	if err != nil {
+		return fmt.Errorf("failed to update webhook delivery: %w", err)
+	}
+
+	if validationErr != nil && validationErr.HasAny() {
+		return fmt.Errorf("webhook delivery validation failed: %w", validationErr)
+	}
+
+	return nil
+}
+
+func (wp *webhookDeliveryPersister) CreateAttempt(attempt *models.WebhookDeliveryAttempt) error {
+	validationErr, err := wp.database.ValidateAndCreate(attempt)
+	if err != nil {
+		return fmt.Errorf("failed to store webhook delivery attempt: %w", err)
+	}
+
+	if validationErr != nil && validationErr.HasAny() {
+		return fmt.Errorf("webhook delivery attempt validation failed: %w", validationErr)
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+package persisters
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/gobuffalo/pop/v6"
+	"github.com/gofrs/uuid"
+	"github.com/teamhanko/passkey-server/persistence/models"
+)
+
+type WebhookPersister interface {
+	Create(webhook *models.Webhook) error
+	Get(tenantId uuid.UUID, webhookId uuid.UUID) (*models.Webhook, error)
+	List(tenantId uuid.UUID) (models.Webhooks, error)
+	// ListForEvent returns all enabled webhooks of the tenant which are subscribed to the event
+	ListForEvent(tenantId uuid.UUID, event models.AuditLogType) (models.Webhooks, error)
+	Delete(webhook *models.Webhook) error
+}
+
+type webhookPersister struct {
+	database *pop.Connection
+}
+
+func NewWebhookPersister(database *pop.Connection) WebhookPersister {
+	return &webhookPersister{database: database}
+}
+
+func (wp *webhookPersister) Create(webhook *models.Webhook) error {
+	validationErr, err := wp.database.ValidateAndCreate(webhook)
+	if err != nil {
+		return fmt.Errorf("failed to store webhook: %w", err)
+	}
+
+	if validationErr != nil && validationErr.HasAny() {
+		return fmt.Errorf("webhook validation failed: %w", validationErr)
+	}
+
+	// Eager creation seems to be broken, so we need to store the events separately.
+	// See: https://github.com/gobuffalo/pop/issues/608
+	validationErr, err = wp.database.ValidateAndCreate(webhook.Events)
+	if err != nil {
+		return fmt.Errorf("failed to store webhook events: %w", err)
+	}
+
+	if validationErr != nil && validationErr.HasAny() {
+		return fmt.Errorf("webhook event validation failed: %w", validationErr)
+	}
+
+	return nil
+}
+
+func (wp *webhookPersister) Get(tenantId uuid.UUID, webhookId uuid.UUID) (*models.Webhook, error) {
+	webhook := models.Webhook{}
+	err := wp.database.Eager("Events").Where("tenant_id = ?", tenantId).Find(&webhook, webhookId)
+	if err != nil && errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook: %w", err)
+	}
+
+	return &webhook, nil
+}
+
+func (wp *webhookPersister) List(tenantId uuid.UUID) (models.Webhooks, error) {
+	webhooks := models.Webhooks{}
+	err := wp.database.Eager("Events").Where("tenant_id = ?", tenantId).Order("created_at asc").All(&webhooks)
+	if err != nil && errors.Is(err, sql.ErrNoRows) {
+		return webhooks, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhooks: %w", err)
+	}
+
+	return webhooks, nil
+}
+
+func (wp *webhookPersister) ListForEvent(tenantId uuid.UUID, event models.AuditLogType) (models.Webhooks, error) {
+	webhooks := models.Webhooks{}
+	err := wp.database.
+		Where("webhooks.tenant_id = ? AND webhooks.enabled = ?", tenantId, true).
+		Where("EXISTS (SELECT 1 FROM webhook_events WHERE webhook_events.webhook_id = webhooks.id AND webhook_events.event = ?)", event).
+		All(&webhooks)
+	if err != nil && errors.Is(err, sql.ErrNoRows) {
+		return webhooks, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhooks for event: %w", err)
+	}
+
+	return webhooks, nil
+}
+
+func (wp *webhookPersister) Delete(webhook *models.Webhook) error {
+	err := wp.database.Destroy(webhook)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+
+	return nil
+}
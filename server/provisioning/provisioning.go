@@ -0,0 +1,204 @@
+package provisioning
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/gobuffalo/pop/v6"
+	"github.com/gofrs/uuid"
+	"github.com/labstack/echo/v4"
+	zeroLogger "github.com/rs/zerolog/log"
+	"github.com/teamhanko/passkey-server/api/dto/admin/request"
+	"github.com/teamhanko/passkey-server/api/services/admin"
+	"github.com/teamhanko/passkey-server/api/validators"
+	"github.com/teamhanko/passkey-server/config"
+	"github.com/teamhanko/passkey-server/persistence"
+	"github.com/teamhanko/passkey-server/persistence/models"
+	"github.com/teamhanko/passkey-server/persistence/persisters"
+)
+
+// actor is stored with the config versions created by the provisioner
+const actor = "provisioning"
+
+var errDryRun = errors.New("dry run")
+
+// Result contains the planned or applied changes of a tenant
+type Result struct {
+	TenantID    string
+	DisplayName string
+	Changes     []string
+}
+
+// Provisioner reconciles the tenants of the config with the database
+type Provisioner struct {
+	persister persistence.Persister
+	tenants   []config.Tenant
+}
+
+func NewProvisioner(persister persistence.Persister, tenants []config.Tenant) *Provisioner {
+	return &Provisioner{
+		persister: persister,
+		tenants:   tenants,
+	}
+}
+
+// Apply creates missing tenants and updates existing ones in a single transaction. With dryRun the transaction is
+// rolled back, so only the planned changes are returned.
+func (p *Provisioner) Apply(dryRun bool) ([]Result, error) {
+	dtos := make([]request.ProvisionTenantDto, 0)
+	for i := range p.tenants {
+		dto, err := toProvisionTenantDto(p.tenants[i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid tenant '%s': %w", p.tenants[i].ID, err)
+		}
+
+		dtos = append(dtos, *dto)
+	}
+
+	results := make([]Result, 0)
+	logger := echo.New().Logger
+
+	err := p.persister.Transaction(func(tx *pop.Connection) error {
+		// replicas which start at the same time wait for each other instead of creating the same tenants
+		err := p.persister.GetLockPersister(tx).Lock(persisters.TenantProvisioningLock)
+		if err != nil {
+			return err
+		}
+
+		for _, dto := range dtos {
+			service := admin.NewTenantService(admin.CreateTenantServiceParams{
+				Logger: logger,
+				Actor:  actor,
+
+				TenantPersister:               p.persister.GetTenantPersister(tx),
+				ConfigPersister:               p.persister.GetConfigPersister(tx),
+				CorsPersister:                 p.persister.GetCorsPersister(tx),
+				WebauthnConfigPersister:       p.persister.GetWebauthnConfigPersister(tx),
+				RelyingPartyPerister:          p.persister.GetWebauthnRelyingPartyPersister(tx),
+				AuditConfigPersister:          p.persister.GetAuditLogConfigPersister(tx),
+				SecretPersister:               p.persister.GetSecretsPersister(tx),
+				JwkPersister:                  p.persister.GetJwkPersister(tx),
+				AuditLogPersister:             p.persister.GetAuditLogPersister(tx),
+				MFAConfigPersister:            p.persister.GetMFAConfigPersister(tx),
+				JwtConfigPersister:            p.persister.GetJwtConfigPersister(tx),
+				OidcClientPersister:           p.persister.GetOidcClientPersister(tx),
+				AaguidPolicyPersister:         p.persister.GetAaguidPolicyPersister(tx),
+				BackupPolicyPersister:         p.persister.GetBackupPolicyPersister(tx),
+				CeremonyConfigPersister:       p.persister.GetWebauthnCeremonyConfigPersister(tx),
+				RegistrationOverridePersister: p.persister.GetRegistrationOverridePersister(tx),
+				ConfigVersionPersister:        p.persister.GetConfigVersionPersister(tx),
+			})
+
+			changes, err := service.Provision(dto, dryRun)
+			if err != nil {
+				return fmt.Errorf("failed to provision tenant '%s': %w", dto.Id, err)
+			}
+
+			results = append(results, Result{
+				TenantID:    dto.Id.String(),
+				DisplayName: dto.DisplayName,
+				Changes:     changes,
+			})
+		}
+
+		// reading the config of a tenant may store its first config version, so a dry run must never be committed
+		if dryRun {
+			return errDryRun
+		}
+
+		return nil
+	})
+
+	if err != nil && !errors.Is(err, errDryRun) {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// ApplyOnStartup applies the tenants of the config before the server starts. Nothing is done when the config has no
+// tenants, so tenants created with the admin API are never touched.
+func ApplyOnStartup(persister persistence.Persister, tenants []config.Tenant) error {
+	if len(tenants) == 0 {
+		return nil
+	}
+
+	results, err := NewProvisioner(persister, tenants).Apply(false)
+	if err != nil {
+		return err
+	}
+
+	for _, result := range results {
+		zeroLogger.Info().
+			Str("tenant_id", result.TenantID).
+			Strs("changes", result.Changes).
+			Msg("provisioned tenant")
+	}
+
+	return nil
+}
+
+// toProvisionTenantDto resolves the secrets of the tenant and converts its config to the format of the admin API
+func toProvisionTenantDto(tenant config.Tenant) (*request.ProvisionTenantDto, error) {
+	id, err := uuid.FromString(tenant.ID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse id: %w", err)
+	}
+
+	configJson, err := json.Marshal(tenant.Config)
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal config: %w", err)
+	}
+
+	dto := request.ProvisionTenantDto{
+		Id:          id,
+		DisplayName: tenant.DisplayName,
+	}
+
+	err = json.Unmarshal(configJson, &dto.Config)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse config: %w", err)
+	}
+
+	dto.ApiKeys, err = resolveSecrets(tenant.ApiKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	dto.JwkKeys, err = resolveSecrets(tenant.JwkKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	err = validators.NewCustomValidator().Validate(&dto)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto, nil
+}
+
+func resolveSecrets(secrets []config.TenantSecret) ([]request.ProvisionSecretDto, error) {
+	dtos := make([]request.ProvisionSecretDto, 0)
+	for i := range secrets {
+		key, err := secrets[i].Resolve()
+		if err != nil {
+			return nil, err
+		}
+
+		scopes := make([]models.ApiKeyScope, 0, len(secrets[i].Scopes))
+		for _, scope := range secrets[i].Scopes {
+			scopes = append(scopes, models.ApiKeyScope(scope))
+		}
+
+		dtos = append(dtos, request.ProvisionSecretDto{
+			Name:      secrets[i].Name,
+			Key:       key,
+			Scopes:    scopes,
+			ExpiresAt: secrets[i].ExpiresAt,
+		})
+	}
+
+	return dtos, nil
+}
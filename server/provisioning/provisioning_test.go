@@ -0,0 +1,144 @@
+package provisioning
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gobuffalo/pop/v6"
+	"github.com/stretchr/testify/assert"
+	"github.com/teamhanko/passkey-server/config"
+	"github.com/teamhanko/passkey-server/persistence"
+	"github.com/teamhanko/passkey-server/persistence/models"
+	"github.com/teamhanko/passkey-server/persistence/persisters"
+)
+
+type testPersister struct {
+	persistence.Persister
+	locks *testLockPersister
+}
+
+func (p *testPersister) Transaction(fn func(tx *pop.Connection) error) error {
+	return fn(nil)
+}
+
+func (p *testPersister) GetLockPersister(_ *pop.Connection) persisters.LockPersister {
+	return p.locks
+}
+
+type testLockPersister struct {
+	locked []string
+}
+
+func (p *testLockPersister) Lock(name string) error {
+	p.locked = append(p.locked, name)
+	return errors.New("lock timeout")
+}
+
+func newTestTenant() config.Tenant {
+	return config.Tenant{
+		ID:          "0f3bf1c8-32b0-4a64-8f6a-3f0dbbd4a4c5",
+		DisplayName: "Example",
+		Config: map[string]interface{}{
+			"cors": map[string]interface{}{
+				"allowed_origins":       []interface{}{"https://example.com"},
+				"allow_unsafe_wildcard": false,
+			},
+			"webauthn": map[string]interface{}{
+				"relying_party": map[string]interface{}{
+					"id":           "example.com",
+					"display_name": "Example",
+					"origins":      []interface{}{"https://example.com"},
+				},
+				"timeout": 60000,
+			},
+		},
+		ApiKeys: []config.TenantSecret{{Name: "backend", Env: "PROVISIONING_TEST_API_KEY"}},
+	}
+}
+
+func TestToProvisionTenantDto(t *testing.T) {
+	// given
+	t.Setenv("PROVISIONING_TEST_API_KEY", " an-api-key-with-enough-characters\n")
+	tenant := newTestTenant()
+
+	// when
+	dto, err := toProvisionTenantDto(tenant)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, tenant.ID, dto.Id.String())
+	assert.Equal(t, "example.com", dto.Config.Passkey.RelyingParty.Id)
+	assert.Equal(t, []string{"https://example.com"}, dto.Config.Cors.AllowedOrigins)
+	assert.Len(t, dto.ApiKeys, 1)
+	assert.Equal(t, "an-api-key-with-enough-characters", dto.ApiKeys[0].Key)
+	assert.Empty(t, dto.JwkKeys)
+}
+
+func TestToProvisionTenantDtoWithMissingSecret(t *testing.T) {
+	// given
+	t.Setenv("PROVISIONING_TEST_API_KEY", "")
+	tenant := newTestTenant()
+
+	// when
+	_, err := toProvisionTenantDto(tenant)
+
+	// then
+	assert.EqualError(t, err, "secret 'backend' is empty")
+}
+
+func TestToProvisionTenantDtoWithShortSecret(t *testing.T) {
+	// given
+	t.Setenv("PROVISIONING_TEST_API_KEY", "too-short")
+	tenant := newTestTenant()
+
+	// when
+	_, err := toProvisionTenantDto(tenant)
+
+	// then
+	assert.Error(t, err)
+}
+
+func TestToProvisionTenantDtoWithScopesAndExpiry(t *testing.T) {
+	// given
+	t.Setenv("PROVISIONING_TEST_API_KEY", "an-api-key-with-enough-characters")
+	expiresAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	tenant := newTestTenant()
+	tenant.ApiKeys[0].Scopes = []string{"token:introspect"}
+	tenant.ApiKeys[0].ExpiresAt = &expiresAt
+
+	// when
+	dto, err := toProvisionTenantDto(tenant)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []models.ApiKeyScope{models.ApiKeyScopeTokenIntrospect}, dto.ApiKeys[0].Scopes)
+	assert.Equal(t, &expiresAt, dto.ApiKeys[0].ExpiresAt)
+}
+
+func TestToProvisionTenantDtoWithUnknownScope(t *testing.T) {
+	// given
+	t.Setenv("PROVISIONING_TEST_API_KEY", "an-api-key-with-enough-characters")
+	tenant := newTestTenant()
+	tenant.ApiKeys[0].Scopes = []string{"tenants:write"}
+
+	// when
+	_, err := toProvisionTenantDto(tenant)
+
+	// then
+	assert.Error(t, err)
+}
+
+func TestApplyTakesProvisioningLockFirst(t *testing.T) {
+	// given
+	t.Setenv("PROVISIONING_TEST_API_KEY", "an-api-key-with-enough-characters")
+	locks := &testLockPersister{}
+	provisioner := NewProvisioner(&testPersister{locks: locks}, []config.Tenant{newTestTenant()})
+
+	// when
+	_, err := provisioner.Apply(false)
+
+	// then
+	assert.EqualError(t, err, "lock timeout")
+	assert.Equal(t, []string{persisters.TenantProvisioningLock}, locks.locked)
+}
@@ -0,0 +1,53 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/teamhanko/passkey-server/persistence/models"
+)
+
+// Event is the payload which is sent to the webhook endpoints
+type Event struct {
+	// ID is the id of the audit log and can be used to detect duplicate deliveries
+	ID        uuid.UUID           `json:"id"`
+	Type      models.AuditLogType `json:"type"`
+	TenantID  uuid.UUID           `json:"tenant_id"`
+	CreatedAt time.Time           `json:"created_at"`
+	Data      models.AuditLog     `json:"data"`
+}
+
+// NewDeliveries creates a pending delivery of the audit log for every webhook
+func NewDeliveries(webhooks models.Webhooks, auditLog models.AuditLog) (models.WebhookDeliveries, error) {
+	payload, err := json.Marshal(Event{
+		ID:        auditLog.ID,
+		Type:      auditLog.Type,
+		TenantID:  auditLog.TenantID,
+		CreatedAt: auditLog.CreatedAt,
+		Data:      auditLog,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create webhook payload: %w", err)
+	}
+
+	now := time.Now().UTC()
+	deliveries := make(models.WebhookDeliveries, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		id, _ := uuid.NewV4()
+		deliveries = append(deliveries, models.WebhookDelivery{
+			ID:            id,
+			Event:         auditLog.Type,
+			Payload:       string(payload),
+			Status:        models.WebhookDeliveryStatusPending,
+			NextAttemptAt: now,
+			WebhookID:     webhook.ID,
+			TenantID:      webhook.TenantID,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		})
+	}
+
+	return deliveries, nil
+}
@@ -0,0 +1,27 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+const (
+	HeaderId        = "X-Webhook-Id"
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+
+	signatureVersion = "v1"
+)
+
+// Sign creates the signature of a delivery. The HMAC-SHA256 is calculated over the timestamp and the payload joined
+// by a dot, so receivers can reject replayed deliveries by checking the timestamp.
+func Sign(secret string, timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(fmt.Sprintf("%d.", timestamp)))
+	mac.Write(payload)
+
+	return fmt.Sprintf("%s=%s", signatureVersion, hex.EncodeToString(mac.Sum(nil)))
+}
@@ -0,0 +1,173 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gobuffalo/pop/v6"
+	"github.com/gofrs/uuid"
+	zeroLogger "github.com/rs/zerolog/log"
+	"github.com/teamhanko/passkey-server/config"
+	"github.com/teamhanko/passkey-server/persistence"
+	"github.com/teamhanko/passkey-server/persistence/models"
+)
+
+const batchSize = 50
+
+// Worker delivers pending webhook deliveries from the outbox
+type Worker struct {
+	persister persistence.Persister
+	cfg       config.Webhooks
+	client    *http.Client
+}
+
+func NewWorker(persister persistence.Persister, cfg config.Webhooks) *Worker {
+	return &Worker{
+		persister: persister,
+		cfg:       cfg,
+		client:    &http.Client{Timeout: cfg.GetTimeout()},
+	}
+}
+
+// Run processes due deliveries until the context is cancelled
+func (w *Worker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.cfg.GetPollInterval())
+	defer ticker.Stop()
+
+	for {
+		err := w.ProcessDue()
+		if err != nil {
+			zeroLogger.Error().Err(err).Msg("failed to process webhook deliveries")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProcessDue sends all deliveries whose next attempt is due
+func (w *Worker) ProcessDue() error {
+	deliveries, err := w.persister.GetWebhookDeliveryPersister(nil).ListDue(time.Now().UTC(), batchSize)
+	if err != nil {
+		return err
+	}
+
+	for i := range deliveries {
+		err = w.process(&deliveries[i])
+		if err != nil {
+			zeroLogger.Error().Err(err).Str("delivery_id", deliveries[i].ID.String()).Msg("failed to process webhook delivery")
+		}
+	}
+
+	return nil
+}
+
+func (w *Worker) process(delivery *models.WebhookDelivery) error {
+	// claim the delivery for the duration of the request, so other instances do not send it concurrently
+	claimed, err := w.persister.GetWebhookDeliveryPersister(nil).Claim(delivery, time.Now().UTC().Add(2*w.cfg.GetTimeout()))
+	if err != nil || !claimed {
+		return err
+	}
+
+	attempt := w.send(delivery)
+
+	return w.persister.Transaction(func(tx *pop.Connection) error {
+		deliveryPersister := w.persister.GetWebhookDeliveryPersister(tx)
+
+		err := deliveryPersister.CreateAttempt(attempt)
+		if err != nil {
+			return err
+		}
+
+		w.applyAttempt(delivery, attempt, time.Now().UTC())
+
+		return deliveryPersister.Update(delivery)
+	})
+}
+
+// applyAttempt updates the status of the delivery. Failed deliveries are retried with an exponential backoff until
+// the maximum number of attempts is reached.
+func (w *Worker) applyAttempt(delivery *models.WebhookDelivery, attempt *models.WebhookDeliveryAttempt, now time.Time) {
+	delivery.Attempts++
+	delivery.UpdatedAt = now
+
+	switch {
+	case attempt.Error == nil:
+		delivery.Status = models.WebhookDeliveryStatusSucceeded
+	case delivery.Attempts >= w.cfg.MaxAttempts || delivery.Webhook == nil || !delivery.Webhook.Enabled:
+		delivery.Status = models.WebhookDeliveryStatusFailed
+	default:
+		delivery.NextAttemptAt = now.Add(w.cfg.GetBackoff(delivery.Attempts))
+	}
+}
+
+func (w *Worker) send(delivery *models.WebhookDelivery) *models.WebhookDeliveryAttempt {
+	id, _ := uuid.NewV4()
+	start := time.Now().UTC()
+
+	attempt := &models.WebhookDeliveryAttempt{
+		ID:                id,
+		WebhookDeliveryID: delivery.ID,
+		CreatedAt:         start,
+		UpdatedAt:         start,
+	}
+
+	statusCode, err := w.post(delivery, start)
+	attempt.Duration = int(time.Since(start).Milliseconds())
+	if statusCode != 0 {
+		attempt.StatusCode = &statusCode
+	}
+
+	if err != nil {
+		errorMessage := err.Error()
+		attempt.Error = &errorMessage
+	}
+
+	return attempt
+}
+
+func (w *Worker) post(delivery *models.WebhookDelivery, now time.Time) (int, error) {
+	if delivery.Webhook == nil || delivery.Webhook.ID == uuid.Nil {
+		return 0, errors.New("webhook does not exist")
+	}
+
+	if !delivery.Webhook.Enabled {
+		return 0, errors.New("webhook is disabled")
+	}
+
+	payload := []byte(delivery.Payload)
+	timestamp := now.Unix()
+
+	req, err := http.NewRequest(http.MethodPost, delivery.Webhook.Url, bytes.NewReader(payload))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderId, delivery.ID.String())
+	req.Header.Set(HeaderEvent, string(delivery.Event))
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(delivery.Webhook.Secret, timestamp, payload))
+
+	res, err := w.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer res.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, 64*1024))
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return res.StatusCode, fmt.Errorf("endpoint responded with status %d", res.StatusCode)
+	}
+
+	return res.StatusCode, nil
+}
@@ -0,0 +1,118 @@
+package webhook
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/teamhanko/passkey-server/config"
+	"github.com/teamhanko/passkey-server/persistence/models"
+)
+
+func newTestDelivery(url string) *models.WebhookDelivery {
+	deliveryId, _ := uuid.NewV4()
+	webhookId, _ := uuid.NewV4()
+
+	return &models.WebhookDelivery{
+		ID:      deliveryId,
+		Event:   models.AuditLogWebAuthnCredentialDeleted,
+		Payload: `{"type":"webauthn_credential_deleted"}`,
+		Status:  models.WebhookDeliveryStatusPending,
+		Webhook: &models.Webhook{
+			ID:      webhookId,
+			Url:     url,
+			Secret:  "webhook-secret",
+			Enabled: true,
+		},
+		WebhookID: webhookId,
+	}
+}
+
+func TestWorkerSendsSignedDelivery(t *testing.T) {
+	// given
+	var received *http.Request
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	worker := NewWorker(nil, config.NewConfig().Webhooks)
+	delivery := newTestDelivery(server.URL)
+
+	// when
+	attempt := worker.send(delivery)
+
+	// then
+	assert.Nil(t, attempt.Error)
+	assert.Equal(t, http.StatusNoContent, *attempt.StatusCode)
+	assert.Equal(t, delivery.Payload, string(body))
+	assert.Equal(t, delivery.ID.String(), received.Header.Get(HeaderId))
+	assert.Equal(t, string(delivery.Event), received.Header.Get(HeaderEvent))
+
+	timestamp, err := strconv.ParseInt(received.Header.Get(HeaderTimestamp), 10, 64)
+	assert.NoError(t, err)
+	assert.Equal(t, Sign("webhook-secret", timestamp, body), received.Header.Get(HeaderSignature))
+}
+
+func TestWorkerRetriesFailedDelivery(t *testing.T) {
+	// given
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	cfg := config.NewConfig().Webhooks
+	cfg.MaxAttempts = 2
+	worker := NewWorker(nil, cfg)
+	delivery := newTestDelivery(server.URL)
+	now := time.Now().UTC()
+
+	// when
+	worker.applyAttempt(delivery, worker.send(delivery), now)
+	firstStatus, firstNextAttempt := delivery.Status, delivery.NextAttemptAt
+	worker.applyAttempt(delivery, worker.send(delivery), now)
+
+	// then
+	assert.Equal(t, models.WebhookDeliveryStatusPending, firstStatus)
+	assert.Equal(t, now.Add(cfg.GetBackoff(1)), firstNextAttempt)
+	assert.Equal(t, models.WebhookDeliveryStatusFailed, delivery.Status)
+	assert.Equal(t, 2, delivery.Attempts)
+}
+
+func TestWorkerFailsDeliveryOfDisabledWebhook(t *testing.T) {
+	// given
+	worker := NewWorker(nil, config.NewConfig().Webhooks)
+	delivery := newTestDelivery("http://localhost")
+	delivery.Webhook.Enabled = false
+
+	// when
+	attempt := worker.send(delivery)
+	worker.applyAttempt(delivery, attempt, time.Now().UTC())
+
+	// then
+	assert.Equal(t, "webhook is disabled", *attempt.Error)
+	assert.Nil(t, attempt.StatusCode)
+	assert.Equal(t, models.WebhookDeliveryStatusFailed, delivery.Status)
+}
+
+func TestSignIsDeterministic(t *testing.T) {
+	// given
+	payload := []byte(`{"id":"1"}`)
+
+	// when
+	signature := Sign("secret", 1700000000, payload)
+
+	// then
+	assert.Equal(t, signature, Sign("secret", 1700000000, payload))
+	assert.NotEqual(t, signature, Sign("secret", 1700000001, payload))
+	assert.NotEqual(t, signature, Sign("another-secret", 1700000000, payload))
+	assert.Regexp(t, "^v1=[0-9a-f]{64}$", signature)
+}